@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/mail"
 	"os"
 	"os/signal"
 	"runtime"
@@ -15,11 +16,27 @@ import (
 	"time"
 
 	"github.com/ardanlabs/conf/v3"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account/stores/accountdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey/stores/apikeydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session/stores/sessiondb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
 	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/debug"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/mailer"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
 	"github.com/diegomagalhaes-dev/go-service/foundation/vault"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 	"go.opentelemetry.io/otel"
@@ -77,12 +94,37 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 			IdleTimeout     time.Duration `conf:"default:120s"`
 			ShutdownTimeout time.Duration `conf:"default:20s"`
 			APIHost         string        `conf:"default:0.0.0.0:3000"`
+			PublicURL       string        `conf:"default:http://localhost:3000"`
 			DebugHost       string        `conf:"default:0.0.0.0:4000"`
 		}
 		Auth struct {
 			// KeysFolder string `conf:"default:zarf/keys/"`
 			// ActiveKID  string `conf:"default:54bb2165-71e1-41a6-af3e-7da4a0e1e2c1"`
-			Issuer string `conf:"default:service project"`
+			Issuer       string        `conf:"default:service project"`
+			KeyCacheTTL  time.Duration `conf:"default:5m"`
+			PolicyDir    string
+			PolicyReload time.Duration `conf:"default:10s"`
+		}
+		Lockout struct {
+			Threshold int           `conf:"default:5"`
+			BaseDelay time.Duration `conf:"default:1m"`
+			MaxDelay  time.Duration `conf:"default:1h"`
+		}
+		Mail struct {
+			Host      string `conf:"default:mail-service.sales-system.svc.cluster.local"`
+			Port      int    `conf:"default:25"`
+			Username  string
+			Password  string `conf:"mask"`
+			From      string `conf:"default:Sales <no-reply@example.com>"`
+			ResetURL  string
+			VerifyURL string
+		}
+		OIDC struct {
+			IssuerURL    string
+			ClientID     string
+			ClientSecret string   `conf:"mask"`
+			RedirectURL  string   `conf:"default:http://localhost:3000/v1/oidc/callback"`
+			DefaultRoles []string `conf:"default:USER"`
 		}
 		Vault struct {
 			Address   string `conf:"default:http://vault-service.sales-system.svc.cluster.local:8200"`
@@ -98,6 +140,13 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 			MaxOpenConns int    `conf:"default:0"`
 			DisableTLS   bool   `conf:"default:true"`
 		}
+		Event struct {
+			RelayInterval time.Duration `conf:"default:1s"`
+			BatchSize     int           `conf:"default:100"`
+			MaxAttempts   int           `conf:"default:10"`
+			BaseBackoff   time.Duration `conf:"default:1s"`
+			MaxBackoff    time.Duration `conf:"default:5m"`
+		}
 		Tempo struct {
 			ReporterURI string  `conf:"default:tempo.sales-system.svc.cluster.local:4317"`
 			ServiceName string  `conf:"default:sales-api"`
@@ -156,6 +205,57 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 		db.Close()
 	}()
 
+	// -------------------------------------------------------------------------
+	// Initialize mail support
+
+	from, err := mail.ParseAddress(cfg.Mail.From)
+	if err != nil {
+		return fmt.Errorf("parsing mail from address: %w", err)
+	}
+
+	mailer := mailer.NewSMTP(mailer.SMTPConfig{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     *from,
+	})
+
+	// -------------------------------------------------------------------------
+	// Start Event Relay
+
+	log.Info(ctx, "startup", "status", "initializing event relay support")
+
+	// The relay needs every core that handles events to be constructed so
+	// their handlers are registered with the event core.
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db))
+	usrCore := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
+	product.NewCore(log, evnCore, audCore, usrCore, productdb.NewStore(log, db))
+	sesCore := session.NewCore(log, sessiondb.NewStore(log, db))
+	apkCore := apikey.NewCore(log, usrCore, apikeydb.NewStore(log, db))
+	account.NewCore(log, evnCore, usrCore, sesCore, apkCore, mailer, accountdb.NewStore(log, db), account.WithLinks(cfg.Mail.ResetURL, cfg.Mail.VerifyURL))
+
+	relayCtx, relayCancel := context.WithCancel(ctx)
+	relayDone := make(chan struct{})
+
+	go func() {
+		defer close(relayDone)
+		evnCore.Relay(relayCtx, event.RelayConfig{
+			Interval:    cfg.Event.RelayInterval,
+			BatchSize:   cfg.Event.BatchSize,
+			MaxAttempts: cfg.Event.MaxAttempts,
+			BaseBackoff: cfg.Event.BaseBackoff,
+			MaxBackoff:  cfg.Event.MaxBackoff,
+		})
+	}()
+
+	defer func() {
+		log.Info(ctx, "shutdown", "status", "stopping event relay")
+		relayCancel()
+		<-relayDone
+	}()
+
 	// -------------------------------------------------------------------------
 	// Initialize authentication support
 
@@ -177,9 +277,12 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 	}
 
 	authCfg := auth.Config{
-		Log:       log,
-		DB:        db,
-		KeyLookup: vault,
+		Log:         log,
+		DB:          db,
+		KeyLookup:   vault,
+		Issuer:      cfg.Auth.Issuer,
+		KeyCacheTTL: cfg.Auth.KeyCacheTTL,
+		PolicyDir:   cfg.Auth.PolicyDir,
 	}
 
 	auth, err := auth.New(authCfg)
@@ -187,6 +290,21 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	// When the policies are loaded from a directory, they are reloaded as
+	// the files change without the need of a restart.
+	policyCtx, policyCancel := context.WithCancel(ctx)
+	policyDone := make(chan struct{})
+
+	go func() {
+		defer close(policyDone)
+		auth.WatchPolicies(policyCtx, cfg.Auth.PolicyReload)
+	}()
+
+	defer func() {
+		policyCancel()
+		<-policyDone
+	}()
+
 	// -------------------------------------------------------------------------
 	// Start Tracing Support
 
@@ -230,13 +348,48 @@ func run(ctx context.Context, log *logger.Logger, build string, routeAdder v1.Ro
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
+	// -------------------------------------------------------------------------
+	// Initialize OpenID Connect support
+
+	var provider *oidc.Provider
+	if cfg.OIDC.IssuerURL != "" {
+		log.Info(ctx, "startup", "status", "initializing OIDC support", "issuer", cfg.OIDC.IssuerURL)
+
+		provider = oidc.New(oidc.Config{
+			IssuerURL:    cfg.OIDC.IssuerURL,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+		})
+	}
+
+	oidcRoles := make([]user.Role, len(cfg.OIDC.DefaultRoles))
+	for i, r := range cfg.OIDC.DefaultRoles {
+		role, err := user.ParseRole(r)
+		if err != nil {
+			return fmt.Errorf("parsing oidc default role: %w", err)
+		}
+		oidcRoles[i] = role
+	}
+
 	cfgMux := v1.APIMuxConfig{
-		Build:    build,
-		Shutdown: shutdown,
-		Log:      log,
-		Auth:     auth,
-		DB:       db,
-		Tracer:   tracer,
+		Build:     build,
+		Shutdown:  shutdown,
+		Log:       log,
+		Auth:      auth,
+		DB:        db,
+		Tracer:    tracer,
+		PublicURL: cfg.Web.PublicURL,
+		Lockout: user.LockoutConfig{
+			Threshold: cfg.Lockout.Threshold,
+			BaseDelay: cfg.Lockout.BaseDelay,
+			MaxDelay:  cfg.Lockout.MaxDelay,
+		},
+		Mailer:    mailer,
+		ResetURL:  cfg.Mail.ResetURL,
+		VerifyURL: cfg.Mail.VerifyURL,
+		OIDC:      provider,
+		OIDCRoles: oidcRoles,
 	}
 
 	apiMux := v1.APIMux(cfgMux, routeAdder, v1.WithCORS("*"))
@@ -2,9 +2,16 @@
 package crud
 
 import (
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/apikeygrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/auditgrp"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/checkgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/oidcgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/ordergrp"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/productgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/rolegrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/tenantgrp"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/wellknowngrp"
 	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 )
@@ -31,8 +38,56 @@ func (add) Add(app *web.App, cfg v1.APIMuxConfig) {
 		DB:   cfg.DB,
 	})
 	usergrp.Routes(app, usergrp.Config{
+		Log:       cfg.Log,
+		Auth:      cfg.Auth,
+		DB:        cfg.DB,
+		Lockout:   cfg.Lockout,
+		Mailer:    cfg.Mailer,
+		ResetURL:  cfg.ResetURL,
+		VerifyURL: cfg.VerifyURL,
+	})
+
+	ordergrp.Routes(app, ordergrp.Config{
+		Log:  cfg.Log,
+		Auth: cfg.Auth,
+		DB:   cfg.DB,
+	})
+
+	rolegrp.Routes(app, rolegrp.Config{
+		Log:  cfg.Log,
+		Auth: cfg.Auth,
+		DB:   cfg.DB,
+	})
+
+	tenantgrp.Routes(app, tenantgrp.Config{
+		Log:  cfg.Log,
+		Auth: cfg.Auth,
+		DB:   cfg.DB,
+	})
+
+	auditgrp.Routes(app, auditgrp.Config{
 		Log:  cfg.Log,
 		Auth: cfg.Auth,
 		DB:   cfg.DB,
 	})
+
+	oidcgrp.Routes(app, oidcgrp.Config{
+		Log:          cfg.Log,
+		Auth:         cfg.Auth,
+		DB:           cfg.DB,
+		Provider:     cfg.OIDC,
+		DefaultRoles: cfg.OIDCRoles,
+	})
+
+	apikeygrp.Routes(app, apikeygrp.Config{
+		Log:  cfg.Log,
+		Auth: cfg.Auth,
+		DB:   cfg.DB,
+	})
+
+	wellknowngrp.Routes(app, wellknowngrp.Config{
+		Log:       cfg.Log,
+		Auth:      cfg.Auth,
+		PublicURL: cfg.PublicURL,
+	})
 }
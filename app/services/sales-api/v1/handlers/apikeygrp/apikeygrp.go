@@ -0,0 +1,178 @@
+// Package apikeygrp maintains the group of handlers for api key access.
+package apikeygrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for handling api key group errors.
+var (
+	ErrInvalidID        = errors.New("ID is not in its proper form")
+	ErrAPIKeyNotAllowed = errors.New("api keys can't be created with an api key")
+)
+
+// Handlers manages the set of api key endpoints.
+type Handlers struct {
+	apikey *apikey.Core
+	auth   *auth.Auth
+}
+
+// New constructs a handlers for route access.
+func New(apikey *apikey.Core, auth *auth.Auth) *Handlers {
+	return &Handlers{
+		apikey: apikey,
+		auth:   auth,
+	}
+}
+
+// executeUnderTransaction constructs a new Handlers value with the core apis
+// using a store transaction that was created via middleware.
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		apikey, err := h.apikey.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &Handlers{
+			apikey: apikey,
+			auth:   h.auth,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+// Create adds a new api key for the authenticated user. The raw key is only
+// returned by this call. Keys are only created by callers holding a token from
+// an interactive login, and never with roles the caller doesn't act with.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	claims := auth.GetClaims(ctx)
+
+	if slices.Contains(claims.AMR, auth.AMRAPIKey) {
+		return response.NewError(ErrAPIKeyNotAllowed, http.StatusForbidden)
+	}
+
+	var app AppNewAPIKey
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	nk, err := toCoreNewAPIKey(app, userID, claims.Roles)
+	if err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	raw, key, err := h.apikey.Create(ctx, nk)
+	if err != nil {
+		switch {
+		case errors.Is(err, apikey.ErrInvalidRole):
+			return response.NewError(err, http.StatusForbidden)
+		default:
+			return fmt.Errorf("create: userID[%s]: %w", userID, err)
+		}
+	}
+
+	created := AppCreatedAPIKey{
+		Key:    raw,
+		APIKey: toAppAPIKey(key),
+	}
+
+	return web.Respond(ctx, w, created, http.StatusCreated)
+}
+
+// Query returns the api keys of the authenticated user.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	keys, err := h.apikey.QueryByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("querybyuserid: userID[%s]: %w", userID, err)
+	}
+
+	return web.Respond(ctx, w, toAppAPIKeys(keys), http.StatusOK)
+}
+
+// QueryByID returns an api key by its ID.
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	key, err := h.queryOwnedKey(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppAPIKey(key), http.StatusOK)
+}
+
+// Revoke revokes an api key so it can't be used anymore.
+func (h *Handlers) Revoke(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	key, err := h.queryOwnedKey(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.apikey.Revoke(ctx, key); err != nil {
+		return fmt.Errorf("revoke: keyID[%s]: %w", key.ID, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// =============================================================================
+
+// queryOwnedKey retrieves the api key specified in the request and validates
+// the caller is either an admin or the user who owns the key.
+func (h *Handlers) queryOwnedKey(ctx context.Context, r *http.Request) (apikey.APIKey, error) {
+	keyID, err := uuid.Parse(web.Param(r, "apikey_id"))
+	if err != nil {
+		return apikey.APIKey{}, response.NewError(ErrInvalidID, http.StatusBadRequest)
+	}
+
+	key, err := h.apikey.QueryByID(ctx, keyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apikey.ErrNotFound):
+			return apikey.APIKey{}, response.NewError(err, http.StatusNotFound)
+		default:
+			return apikey.APIKey{}, fmt.Errorf("querybyid: keyID[%s]: %w", keyID, err)
+		}
+	}
+
+	claims := auth.GetClaims(ctx)
+
+	if err := h.auth.Authorize(ctx, claims, key.UserID, auth.RuleAdminOrSubject); err != nil {
+		return apikey.APIKey{}, auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+	}
+
+	return key, nil
+}
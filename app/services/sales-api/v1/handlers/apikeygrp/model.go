@@ -0,0 +1,116 @@
+package apikeygrp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+// AppAPIKey represents an individual api key. The raw key is never part of
+// this model.
+type AppAPIKey struct {
+	ID           string   `json:"id"`
+	UserID       string   `json:"userID"`
+	Name         string   `json:"name"`
+	Prefix       string   `json:"prefix"`
+	Roles        []string `json:"roles"`
+	DateCreated  string   `json:"dateCreated"`
+	DateExpires  string   `json:"dateExpires,omitempty"`
+	DateLastUsed string   `json:"dateLastUsed,omitempty"`
+	DateRevoked  string   `json:"dateRevoked,omitempty"`
+}
+
+func toAppAPIKey(key apikey.APIKey) AppAPIKey {
+	roles := make([]string, len(key.Roles))
+	for i, role := range key.Roles {
+		roles[i] = role.Name()
+	}
+
+	return AppAPIKey{
+		ID:           key.ID.String(),
+		UserID:       key.UserID.String(),
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		Roles:        roles,
+		DateCreated:  key.DateCreated.Format(time.RFC3339),
+		DateExpires:  formatTime(key.DateExpires),
+		DateLastUsed: formatTime(key.DateLastUsed),
+		DateRevoked:  formatTime(key.DateRevoked),
+	}
+}
+
+func toAppAPIKeys(keys []apikey.APIKey) []AppAPIKey {
+	items := make([]AppAPIKey, len(keys))
+	for i, key := range keys {
+		items[i] = toAppAPIKey(key)
+	}
+
+	return items
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// =============================================================================
+
+// AppCreatedAPIKey is returned once when an api key is created. It's the only
+// time the raw key is provided.
+type AppCreatedAPIKey struct {
+	Key    string    `json:"key"`
+	APIKey AppAPIKey `json:"apiKey"`
+}
+
+// =============================================================================
+
+// AppNewAPIKey is what we require from clients when adding an APIKey.
+type AppNewAPIKey struct {
+	Name        string   `json:"name" validate:"required"`
+	Roles       []string `json:"roles" validate:"required,min=1"`
+	DateExpires string   `json:"dateExpires"`
+}
+
+func toCoreNewAPIKey(app AppNewAPIKey, userID uuid.UUID, grantorRoles []user.Role) (apikey.NewAPIKey, error) {
+	roles := make([]user.Role, len(app.Roles))
+	for i, roleStr := range app.Roles {
+		role, err := user.ParseRole(roleStr)
+		if err != nil {
+			return apikey.NewAPIKey{}, fmt.Errorf("parsing role: %w", err)
+		}
+		roles[i] = role
+	}
+
+	nk := apikey.NewAPIKey{
+		UserID:       userID,
+		Name:         app.Name,
+		Roles:        roles,
+		GrantorRoles: grantorRoles,
+	}
+
+	if app.DateExpires != "" {
+		expires, err := time.Parse(time.RFC3339, app.DateExpires)
+		if err != nil {
+			return apikey.NewAPIKey{}, fmt.Errorf("parsing dateExpires: %w", err)
+		}
+		nk.DateExpires = expires
+	}
+
+	return nk, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewAPIKey) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package apikeygrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey/stores/apikeydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/usercache"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	envCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(cfg.Log, envCore, audCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
+	apkCore := apikey.NewCore(cfg.Log, usrCore, apikeydb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := New(apkCore, cfg.Auth)
+	app.Handle(http.MethodGet, version, "/apikeys", hdl.Query, authen)
+	app.Handle(http.MethodGet, version, "/apikeys/:apikey_id", hdl.QueryByID, authen)
+	app.Handle(http.MethodPost, version, "/apikeys", hdl.Create, authen, ruleAny, tran)
+	app.Handle(http.MethodDelete, version, "/apikeys/:apikey_id", hdl.Revoke, authen, tran)
+}
@@ -0,0 +1,72 @@
+package auditgrp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+func parseFilter(r *http.Request) (audit.QueryFilter, error) {
+	const (
+		filterByEntity           = "entity"
+		filterByEntityID         = "entity_id"
+		filterByAction           = "action"
+		filterByActor            = "actor"
+		filterByTraceID          = "trace_id"
+		filterByStartCreatedDate = "start_created_date"
+		filterByEndCreatedDate   = "end_created_date"
+	)
+
+	values := r.URL.Query()
+
+	var filter audit.QueryFilter
+
+	if entity := values.Get(filterByEntity); entity != "" {
+		filter.WithEntity(entity)
+	}
+
+	if entityID := values.Get(filterByEntityID); entityID != "" {
+		id, err := uuid.Parse(entityID)
+		if err != nil {
+			return audit.QueryFilter{}, validate.NewFieldsError(filterByEntityID, err)
+		}
+		filter.WithEntityID(id)
+	}
+
+	if action := values.Get(filterByAction); action != "" {
+		filter.WithAction(action)
+	}
+
+	if actor := values.Get(filterByActor); actor != "" {
+		filter.WithActor(actor)
+	}
+
+	if traceID := values.Get(filterByTraceID); traceID != "" {
+		filter.WithTraceID(traceID)
+	}
+
+	if createdDate := values.Get(filterByStartCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return audit.QueryFilter{}, validate.NewFieldsError(filterByStartCreatedDate, err)
+		}
+		filter.WithStartDateCreated(t)
+	}
+
+	if createdDate := values.Get(filterByEndCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return audit.QueryFilter{}, validate.NewFieldsError(filterByEndCreatedDate, err)
+		}
+		filter.WithEndCreatedDate(t)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return audit.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
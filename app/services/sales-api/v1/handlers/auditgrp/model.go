@@ -0,0 +1,55 @@
+package auditgrp
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+)
+
+// AppChange represents the value of a field before and after a mutation.
+type AppChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AppEntry represents information about a mutation in the audit log.
+type AppEntry struct {
+	ID          string               `json:"id"`
+	Actor       string               `json:"actor"`
+	TraceID     string               `json:"traceID"`
+	Entity      string               `json:"entity"`
+	EntityID    string               `json:"entityID"`
+	Action      string               `json:"action"`
+	Diff        map[string]AppChange `json:"diff"`
+	DateCreated string               `json:"dateCreated"`
+}
+
+func toAppEntry(entry audit.Entry) AppEntry {
+	diff := make(map[string]AppChange, len(entry.Diff))
+	for field, chg := range entry.Diff {
+		diff[field] = AppChange{
+			Before: chg.Before,
+			After:  chg.After,
+		}
+	}
+
+	return AppEntry{
+		ID:          entry.ID.String(),
+		Actor:       entry.Actor,
+		TraceID:     entry.TraceID,
+		Entity:      entry.Entity,
+		EntityID:    entry.EntityID.String(),
+		Action:      entry.Action,
+		Diff:        diff,
+		DateCreated: entry.DateCreated.Format(time.RFC3339),
+	}
+}
+
+func toAppEntries(entries []audit.Entry) []AppEntry {
+	items := make([]AppEntry, len(entries))
+	for i, entry := range entries {
+		items[i] = toAppEntry(entry)
+	}
+
+	return items
+}
@@ -0,0 +1,33 @@
+package auditgrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+)
+
+func parseOrder(r *http.Request) (order.By, error) {
+	const (
+		orderByID          = "audit_id"
+		orderByEntity      = "entity"
+		orderByAction      = "action"
+		orderByActor       = "actor"
+		orderByDateCreated = "date_created"
+	)
+
+	var orderByFields = map[string]string{
+		orderByID:          audit.OrderByID,
+		orderByEntity:      audit.OrderByEntity,
+		orderByAction:      audit.OrderByAction,
+		orderByActor:       audit.OrderByActor,
+		orderByDateCreated: audit.OrderByDateCreated,
+	}
+
+	orderBy, err := order.Parse(r, order.NewBy(orderByDateCreated, order.DESC))
+	if err != nil {
+		return order.By{}, err
+	}
+
+	return orderBy.Map(orderByFields)
+}
@@ -0,0 +1,34 @@
+package auditgrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermAuditRead)
+
+	hdl := New(audCore)
+	app.Handle(http.MethodGet, version, "/audit", hdl.Query, authen, permRead)
+}
@@ -0,0 +1,35 @@
+package oidcgrp
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+)
+
+type token struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+func toToken(v string, refresh string) token {
+	return token{
+		Token:        v,
+		RefreshToken: refresh,
+	}
+}
+
+// AppMFAChallenge is returned in place of a token when the user has to
+// provide a second factor to complete the login.
+type AppMFAChallenge struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+	DateExpires string `json:"dateExpires"`
+}
+
+func toAppMFAChallenge(raw string, ch mfa.Challenge) AppMFAChallenge {
+	return AppMFAChallenge{
+		MFARequired: true,
+		MFAToken:    raw,
+		DateExpires: ch.DateExpires.Format(time.RFC3339),
+	}
+}
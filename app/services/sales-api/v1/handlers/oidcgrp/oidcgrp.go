@@ -0,0 +1,258 @@
+// Package oidcgrp maintains the group of handlers for logging in through an
+// OpenID Connect provider.
+package oidcgrp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"slices"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// StateCookie is the cookie binding the login to the browser that started it.
+const StateCookie = "oidc_state"
+
+// Set of error variables for handling oidc group errors.
+var (
+	ErrMissingEmail = errors.New("provider didn't return an email")
+)
+
+// Handlers manages the set of oidc endpoints.
+type Handlers struct {
+	identity *identity.Core
+	session  *session.Core
+	mfa      *mfa.Core
+	provider *oidc.Provider
+	auth     *auth.Auth
+}
+
+// New constructs a handlers for route access.
+func New(identity *identity.Core, session *session.Core, mfa *mfa.Core, provider *oidc.Provider, auth *auth.Auth) *Handlers {
+	return &Handlers{
+		identity: identity,
+		session:  session,
+		mfa:      mfa,
+		provider: provider,
+		auth:     auth,
+	}
+}
+
+// executeUnderTransaction constructs a new Handlers value with the core apis
+// using a store transaction that was created via middleware.
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		identity, err := h.identity.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		session, err := h.session.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		mfa, err := h.mfa.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &Handlers{
+			identity: identity,
+			session:  session,
+			mfa:      mfa,
+			provider: h.provider,
+			auth:     h.auth,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+// Login starts a login by sending the user to the provider.
+func (h *Handlers) Login(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	state, lgn, err := h.identity.StartLogin(ctx)
+	if err != nil {
+		return fmt.Errorf("startlogin: %w", err)
+	}
+
+	authURL, err := h.provider.AuthCodeURL(ctx, state, lgn.Nonce, oidc.Challenge(lgn.Verifier))
+	if err != nil {
+		return fmt.Errorf("authcodeurl: %w", err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     StateCookie,
+		Value:    state,
+		Path:     "/v1/oidc",
+		MaxAge:   int(identity.LoginTTL / time.Second),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return web.Redirect(ctx, w, r, authURL, http.StatusFound)
+}
+
+// Callback completes the login when the provider sends the user back and
+// provides an API token and a refresh token for the user. Users logging in for
+// the first time are provisioned. Users with MFA enabled get a challenge
+// unless the provider already asserted a second factor.
+func (h *Handlers) Callback(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := r.URL.Query()
+
+	if e := q.Get("error"); e != "" {
+		return auth.NewAuthError("provider: %s: %s", e, q.Get("error_description"))
+	}
+
+	state := q.Get("state")
+
+	cookie, err := r.Cookie(StateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return auth.NewAuthError("state doesn't match the login")
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     StateCookie,
+		Path:     "/v1/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	lgn, err := h.identity.FinishLogin(ctx, state)
+	if err != nil {
+		switch {
+		case errors.Is(err, identity.ErrInvalidLogin), errors.Is(err, identity.ErrLoginExpired):
+			return auth.NewAuthError(err.Error())
+		default:
+			return fmt.Errorf("finishlogin: %w", err)
+		}
+	}
+
+	claims, err := h.provider.Exchange(ctx, q.Get("code"), lgn.Verifier, lgn.Nonce)
+	if err != nil {
+		return auth.NewAuthError("exchange: %s", err)
+	}
+
+	if claims.Email == "" {
+		return response.NewError(ErrMissingEmail, http.StatusUnauthorized)
+	}
+
+	ext := identity.External{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         mail.Address{Name: claims.Name, Address: claims.Email},
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}
+
+	usr, err := h.identity.Resolve(ctx, ext)
+	if err != nil {
+		switch {
+		case errors.Is(err, identity.ErrEmailNotVerified):
+			return response.NewError(err, http.StatusConflict)
+		case errors.Is(err, user.ErrUniqueEmail):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("resolve: subject[%s]: %w", ext.Subject, err)
+		}
+	}
+
+	if !usr.Enabled {
+		return auth.NewAuthError("user is disabled")
+	}
+
+	amr := []string{auth.AMRFederated}
+	if slices.Contains(claims.AMR, auth.AMRMFA) {
+		amr = append(amr, auth.AMRMFA)
+	}
+
+	if !slices.Contains(amr, auth.AMRMFA) {
+		enabled, err := h.mfa.Enabled(ctx, usr.ID)
+		if err != nil {
+			return fmt.Errorf("mfa.enabled: userID[%s]: %w", usr.ID, err)
+		}
+
+		if enabled {
+			raw, ch, err := h.mfa.Challenge(ctx, usr.ID)
+			if err != nil {
+				switch {
+				case errors.Is(err, mfa.ErrLocked):
+					return response.NewError(err, http.StatusLocked)
+				default:
+					return fmt.Errorf("mfa.challenge: userID[%s]: %w", usr.ID, err)
+				}
+			}
+
+			return web.Respond(ctx, w, toAppMFAChallenge(raw, ch), http.StatusOK)
+		}
+	}
+
+	refresh, _, err := h.session.Create(ctx, usr.ID, usr.TenantID, amr)
+	if err != nil {
+		return fmt.Errorf("session.create: userID[%s]: %w", usr.ID, err)
+	}
+
+	tkn, err := h.generateToken(ctx, usr, refresh, amr)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
+
+// =============================================================================
+
+// generateToken constructs the signed API token for the user.
+func (h *Handlers) generateToken(ctx context.Context, usr user.User, refresh string, amr []string) (token, error) {
+	perms, err := h.auth.Permissions(ctx, usr.TenantID, usr.Roles)
+	if err != nil {
+		return token{}, fmt.Errorf("permissions: userID[%s]: %w", usr.ID, err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   usr.ID.String(),
+			Issuer:    h.auth.Issuer(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		TenantID:      usr.TenantID.String(),
+		Roles:         usr.Roles,
+		Permissions:   perms,
+		AMR:           amr,
+		EmailVerified: usr.EmailVerified,
+	}
+
+	tkn, err := h.auth.GenerateToken(claims)
+	if err != nil {
+		return token{}, fmt.Errorf("generatetoken: %w", err)
+	}
+
+	return toToken(tkn, refresh), nil
+}
@@ -0,0 +1,60 @@
+package oidcgrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity/stores/identitydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa/stores/mfadb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session/stores/sessiondb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/usercache"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build        string
+	Log          *logger.Logger
+	DB           *sqlx.DB
+	Auth         *auth.Auth
+	Provider     *oidc.Provider
+	DefaultRoles []user.Role
+}
+
+// Routes adds specific routes for this group. No routes are added when no
+// provider is configured.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	if cfg.Provider == nil {
+		return
+	}
+
+	envCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(cfg.Log, envCore, audCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
+	idnCore := identity.NewCore(cfg.Log, usrCore, identitydb.NewStore(cfg.Log, cfg.DB), identity.WithDefaultRoles(cfg.DefaultRoles...))
+	sesCore := session.NewCore(cfg.Log, sessiondb.NewStore(cfg.Log, cfg.DB))
+	mfaCore := mfa.NewCore(cfg.Log, mfadb.NewStore(cfg.Log, cfg.DB))
+
+	unscoped := mid.Unscoped()
+	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := New(idnCore, sesCore, mfaCore, cfg.Provider, cfg.Auth)
+	app.Handle(http.MethodGet, version, "/oidc/login", hdl.Login)
+	app.Handle(http.MethodGet, version, "/oidc/callback", hdl.Callback, unscoped, tran)
+}
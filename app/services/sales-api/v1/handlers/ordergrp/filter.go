@@ -0,0 +1,70 @@
+package ordergrp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+func parseFilter(r *http.Request) (order.QueryFilter, error) {
+	const (
+		filterByOrderID          = "order_id"
+		filterByUserID           = "user_id"
+		filterByStatus           = "status"
+		filterByStartCreatedDate = "start_created_date"
+		filterByEndCreatedDate   = "end_created_date"
+	)
+
+	values := r.URL.Query()
+
+	var filter order.QueryFilter
+
+	if orderID := values.Get(filterByOrderID); orderID != "" {
+		id, err := uuid.Parse(orderID)
+		if err != nil {
+			return order.QueryFilter{}, validate.NewFieldsError(filterByOrderID, err)
+		}
+		filter.WithOrderID(id)
+	}
+
+	if userID := values.Get(filterByUserID); userID != "" {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return order.QueryFilter{}, validate.NewFieldsError(filterByUserID, err)
+		}
+		filter.WithUserID(id)
+	}
+
+	if status := values.Get(filterByStatus); status != "" {
+		sts, err := order.ParseStatus(status)
+		if err != nil {
+			return order.QueryFilter{}, validate.NewFieldsError(filterByStatus, err)
+		}
+		filter.WithStatus(sts)
+	}
+
+	if createdDate := values.Get(filterByStartCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return order.QueryFilter{}, validate.NewFieldsError(filterByStartCreatedDate, err)
+		}
+		filter.WithStartDateCreated(t)
+	}
+
+	if createdDate := values.Get(filterByEndCreatedDate); createdDate != "" {
+		t, err := time.Parse(time.RFC3339, createdDate)
+		if err != nil {
+			return order.QueryFilter{}, validate.NewFieldsError(filterByEndCreatedDate, err)
+		}
+		filter.WithEndCreatedDate(t)
+	}
+
+	if err := filter.Validate(); err != nil {
+		return order.QueryFilter{}, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,102 @@
+package ordergrp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+// AppOrder represents an individual order.
+type AppOrder struct {
+	ID          string         `json:"id"`
+	UserID      string         `json:"userID"`
+	Status      string         `json:"status"`
+	Items       []AppOrderItem `json:"items"`
+	Total       float64        `json:"total"`
+	DateCreated string         `json:"dateCreated"`
+	DateUpdated string         `json:"dateUpdated"`
+}
+
+// AppOrderItem represents an individual product line inside an order.
+type AppOrderItem struct {
+	ProductID string  `json:"productID"`
+	Quantity  int     `json:"quantity"`
+	Cost      float64 `json:"cost"`
+}
+
+func toAppOrder(ord order.Order) AppOrder {
+	items := make([]AppOrderItem, len(ord.Items))
+	for i, item := range ord.Items {
+		items[i] = AppOrderItem{
+			ProductID: item.ProductID.String(),
+			Quantity:  item.Quantity,
+			Cost:      item.Cost,
+		}
+	}
+
+	return AppOrder{
+		ID:          ord.ID.String(),
+		UserID:      ord.UserID.String(),
+		Status:      ord.Status.Name(),
+		Items:       items,
+		Total:       ord.Total,
+		DateCreated: ord.DateCreated.Format(time.RFC3339),
+		DateUpdated: ord.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppOrders(ords []order.Order) []AppOrder {
+	items := make([]AppOrder, len(ords))
+	for i, ord := range ords {
+		items[i] = toAppOrder(ord)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewOrder is what we require from clients when placing an Order.
+type AppNewOrder struct {
+	Items []AppNewOrderItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// AppNewOrderItem is what we require from clients for each product ordered.
+type AppNewOrderItem struct {
+	ProductID string `json:"productID" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"gte=1"`
+}
+
+func toCoreNewOrder(app AppNewOrder, userID uuid.UUID) (order.NewOrder, error) {
+	items := make([]order.NewItem, len(app.Items))
+	for i, item := range app.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			return order.NewOrder{}, fmt.Errorf("parsing productid: %w", err)
+		}
+
+		items[i] = order.NewItem{
+			ProductID: productID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	no := order.NewOrder{
+		UserID: userID,
+		Items:  items,
+	}
+
+	return no, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewOrder) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package ordergrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+)
+
+func parseOrder(r *http.Request) (ordering.By, error) {
+	const (
+		orderByOrderID     = "order_id"
+		orderByUserID      = "user_id"
+		orderByStatus      = "status"
+		orderByTotal       = "total"
+		orderByDateCreated = "date_created"
+	)
+
+	var orderByFields = map[string]string{
+		orderByOrderID:     order.OrderByOrderID,
+		orderByUserID:      order.OrderByUserID,
+		orderByStatus:      order.OrderByStatus,
+		orderByTotal:       order.OrderByTotal,
+		orderByDateCreated: order.OrderByDateCreated,
+	}
+
+	orderBy, err := ordering.Parse(r, ordering.NewBy(orderByOrderID, ordering.ASC))
+	if err != nil {
+		return ordering.By{}, err
+	}
+
+	return orderBy.Map(orderByFields)
+}
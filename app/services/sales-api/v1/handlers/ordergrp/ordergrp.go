@@ -0,0 +1,189 @@
+// Package ordergrp maintains the group of handlers for order access.
+package ordergrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for handling order group errors.
+var (
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// Handlers manages the set of order endpoints.
+type Handlers struct {
+	order *order.Core
+	auth  *auth.Auth
+}
+
+// New constructs a handlers for route access.
+func New(order *order.Core, auth *auth.Auth) *Handlers {
+	return &Handlers{
+		order: order,
+		auth:  auth,
+	}
+}
+
+// executeUnderTransaction constructs a new Handlers value with the core apis
+// using a store transaction that was created via middleware.
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		order, err := h.order.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &Handlers{
+			order: order,
+			auth:  h.auth,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+// Create places a new order for the authenticated user.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewOrder
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	userID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	no, err := toCoreNewOrder(app, userID)
+	if err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	ord, err := h.order.Create(ctx, no)
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrUserDisabled):
+			return response.NewError(err, http.StatusForbidden)
+		case errors.Is(err, order.ErrNoItems), errors.Is(err, order.ErrInvalidQuantity), errors.Is(err, order.ErrProductInactive), errors.Is(err, product.ErrNotFound):
+			return response.NewError(err, http.StatusBadRequest)
+		case errors.Is(err, order.ErrInsufficientStock):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("create: app[%+v]: %w", app, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppOrder(ord), http.StatusCreated)
+}
+
+// Cancel cancels an order and returns the items to stock.
+func (h *Handlers) Cancel(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	ord, err := h.queryOwnedOrder(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	cancelled, err := h.order.Cancel(ctx, ord)
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrAlreadyCancelled):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("cancel: orderID[%s]: %w", ord.ID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppOrder(cancelled), http.StatusOK)
+}
+
+// Query returns a list of orders with paging.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	page, err := page.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r)
+	if err != nil {
+		return err
+	}
+
+	ords, err := h.order.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	total, err := h.order.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewPageDocument(toAppOrders(ords), total, page.Number, page.RowsPerPage), http.StatusOK)
+}
+
+// QueryByID returns an order by its ID.
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ord, err := h.queryOwnedOrder(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppOrder(ord), http.StatusOK)
+}
+
+// =============================================================================
+
+// queryOwnedOrder retrieves the order specified in the request and validates
+// the caller is either an admin or the user who placed the order.
+func (h *Handlers) queryOwnedOrder(ctx context.Context, r *http.Request) (order.Order, error) {
+	orderID, err := uuid.Parse(web.Param(r, "order_id"))
+	if err != nil {
+		return order.Order{}, response.NewError(ErrInvalidID, http.StatusBadRequest)
+	}
+
+	ord, err := h.order.QueryByID(ctx, orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, order.ErrNotFound):
+			return order.Order{}, response.NewError(err, http.StatusNotFound)
+		default:
+			return order.Order{}, fmt.Errorf("querybyid: orderID[%s]: %w", orderID, err)
+		}
+	}
+
+	claims := auth.GetClaims(ctx)
+	if err := h.auth.Authorize(ctx, claims, ord.UserID, auth.RuleAdminOrSubject); err != nil {
+		return order.Order{}, auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+	}
+
+	return ord, nil
+}
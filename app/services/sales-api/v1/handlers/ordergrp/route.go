@@ -0,0 +1,54 @@
+package ordergrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/order/stores/orderdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/usercache"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	envCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(cfg.Log, envCore, audCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
+	prdCore := product.NewCore(cfg.Log, envCore, audCore, usrCore, productdb.NewStore(cfg.Log, cfg.DB))
+	ordCore := order.NewCore(cfg.Log, usrCore, prdCore, orderdb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermOrderRead)
+	permWrite := mid.AuthorizePermission(cfg.Auth, auth.PermOrderWrite)
+	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := New(ordCore, cfg.Auth)
+	app.Handle(http.MethodGet, version, "/orders", hdl.Query, authen, ruleAdmin, permRead)
+	app.Handle(http.MethodGet, version, "/orders/:order_id", hdl.QueryByID, authen, permRead)
+	app.Handle(http.MethodPost, version, "/orders", hdl.Create, authen, permWrite, tran)
+	app.Handle(http.MethodPost, version, "/orders/:order_id/cancel", hdl.Cancel, authen, permWrite, tran)
+}
@@ -0,0 +1,323 @@
+package productgrp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// maxBatchOperations is the number of operations a batch can hold.
+const maxBatchOperations = 1000
+
+// Set of error variables for handling batch errors.
+var (
+	ErrBatchAborted   = errors.New("batch aborted by a failing operation")
+	ErrBatchDuplicate = errors.New("product changed by another operation of the batch")
+)
+
+// batchChange is an update or delete of a batch that passed the checks.
+type batchChange struct {
+	index int
+	prd   product.Product
+	up    product.UpdateProduct
+}
+
+// Batch applies a batch of operations on products within a single
+// transaction. Every operation is checked before any is applied, an atomic
+// batch with a failing operation applies none of them.
+func (h *Handlers) Batch(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppBatch
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	if len(app.Operations) > maxBatchOperations {
+		err := validate.NewFieldsError("operations", fmt.Errorf("operations must hold at most %d items", maxBatchOperations))
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	atomic := app.Mode == BatchAtomic
+
+	results := make([]AppBatchResult, len(app.Operations))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	fail := func(i int, err error) error {
+		res, ok := batchFailure(i, err)
+		if !ok {
+			return fmt.Errorf("operations[%d]: %w", i, err)
+		}
+		results[i] = res
+		return nil
+	}
+
+	// -------------------------------------------------------------------------
+	// Check every operation before applying any.
+
+	var changes []batchChange
+	var creates []int
+	var nps []product.NewProduct
+
+	seen := make(map[uuid.UUID]bool)
+
+	for i, op := range app.Operations {
+		if err := op.Validate(); err != nil {
+			if err := fail(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if op.Op == BatchCreate {
+			np, err := h.checkBatchCreate(ctx, op)
+			if err != nil {
+				if err := fail(i, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			creates = append(creates, i)
+			nps = append(nps, np)
+			continue
+		}
+
+		chg, err := h.checkBatchChange(ctx, op, seen)
+		if err != nil {
+			if err := fail(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		chg.index = i
+		changes = append(changes, chg)
+	}
+
+	prds, errs, err := h.product.PrepareBatch(ctx, nps)
+	if err != nil {
+		return fmt.Errorf("preparebatch: %w", err)
+	}
+
+	var newPrds []product.Product
+	var newIndexes []int
+
+	for j, i := range creates {
+		if errs[j] != nil {
+			if err := fail(i, errs[j]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		newPrds = append(newPrds, prds[j])
+		newIndexes = append(newIndexes, i)
+	}
+
+	if atomic && batchFailed(results) {
+		for i := range results {
+			if results[i].Status == 0 {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Error = ErrBatchAborted.Error()
+			}
+		}
+
+		return web.Respond(ctx, w, AppBatchResults{Mode: app.Mode, Results: results}, http.StatusUnprocessableEntity)
+	}
+
+	// -------------------------------------------------------------------------
+	// Apply the operations that passed the checks.
+
+	for _, chg := range changes {
+		op := app.Operations[chg.index].Op
+
+		var prd product.Product
+		var err error
+
+		switch op {
+		case BatchDelete:
+			err = h.product.Delete(ctx, chg.prd)
+		default:
+			prd, err = h.product.Update(ctx, chg.prd, chg.up)
+		}
+
+		if err != nil {
+			if !errors.Is(err, product.ErrConcurrentModification) {
+				return fmt.Errorf("%s: operations[%d]: productID[%s]: %w", op, chg.index, chg.prd.ID, err)
+			}
+
+			// The product changed since it was checked, an atomic batch has
+			// to roll back what it already applied.
+			if atomic {
+				return response.NewError(fmt.Errorf("operations[%d]: %w", chg.index, err), http.StatusPreconditionFailed)
+			}
+
+			if err := fail(chg.index, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if op == BatchDelete {
+			results[chg.index].Status = http.StatusNoContent
+			continue
+		}
+
+		appPrd := toAppProduct(prd)
+		results[chg.index].Status = http.StatusOK
+		results[chg.index].Product = &appPrd
+	}
+
+	if err := h.product.CreateBatch(ctx, newPrds); err != nil {
+		return fmt.Errorf("createbatch: %w", err)
+	}
+
+	for j, i := range newIndexes {
+		appPrd := toAppProduct(newPrds[j])
+		results[i].Status = http.StatusCreated
+		results[i].Product = &appPrd
+	}
+
+	return web.Respond(ctx, w, AppBatchResults{Mode: app.Mode, Committed: true, Results: results}, http.StatusOK)
+}
+
+// checkBatchCreate checks a create of a batch and returns the new product.
+func (h *Handlers) checkBatchCreate(ctx context.Context, op AppBatchOperation) (product.NewProduct, error) {
+	var app AppNewProduct
+	if err := decodeBatchProduct(op.Product, &app); err != nil {
+		return product.NewProduct{}, err
+	}
+
+	np, err := toCoreNewProduct(app)
+	if err != nil {
+		return product.NewProduct{}, validate.NewFieldsError("userID", err)
+	}
+
+	if err := h.authorizeOwner(ctx, np.UserID); err != nil {
+		return product.NewProduct{}, err
+	}
+
+	return np, nil
+}
+
+// checkBatchChange checks an update or delete of a batch and returns the
+// change to apply. A product can only be changed once in a batch.
+func (h *Handlers) checkBatchChange(ctx context.Context, op AppBatchOperation, seen map[uuid.UUID]bool) (batchChange, error) {
+	productID, err := uuid.Parse(op.ID)
+	if err != nil {
+		return batchChange{}, validate.NewFieldsError("id", ErrInvalidID)
+	}
+
+	if seen[productID] {
+		return batchChange{}, ErrBatchDuplicate
+	}
+	seen[productID] = true
+
+	prd, err := h.product.QueryByID(ctx, productID)
+	if err != nil {
+		return batchChange{}, fmt.Errorf("querybyid: productID[%s]: %w", productID, err)
+	}
+
+	if err := h.authorizeOwner(ctx, prd.UserID); err != nil {
+		return batchChange{}, err
+	}
+
+	if op.Version != nil && *op.Version != prd.Version {
+		return batchChange{}, product.ErrConcurrentModification
+	}
+
+	chg := batchChange{
+		prd: prd,
+	}
+
+	if op.Op == BatchUpdate {
+		var app AppUpdateProduct
+		if err := decodeBatchProduct(op.Product, &app); err != nil {
+			return batchChange{}, err
+		}
+
+		chg.up = toCoreUpdateProduct(app)
+	}
+
+	return chg, nil
+}
+
+// =============================================================================
+
+// decodeBatchProduct decodes and validates the product of an operation with
+// the same rules a request body is held to.
+func decodeBatchProduct(data json.RawMessage, val interface{ Validate() error }) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(val); err != nil {
+		return validate.NewFieldsError("product", err)
+	}
+
+	return val.Validate()
+}
+
+// batchFailure returns the result of an operation failing with the error. It
+// reports false for an unexpected error, which fails the whole batch.
+func batchFailure(index int, err error) (AppBatchResult, bool) {
+	res := AppBatchResult{
+		Index: index,
+		Error: err.Error(),
+	}
+
+	switch {
+	case validate.IsFieldErrors(err):
+		res.Status = http.StatusBadRequest
+		res.Error = "data validation error"
+		res.Fields = validate.GetFieldErrors(err)
+
+	case auth.IsAuthError(err):
+		res.Status = http.StatusUnauthorized
+		res.Error = http.StatusText(http.StatusUnauthorized)
+
+	case errors.Is(err, product.ErrNotFound):
+		res.Status = http.StatusNotFound
+
+	case errors.Is(err, product.ErrConcurrentModification):
+		res.Status = http.StatusPreconditionFailed
+
+	case errors.Is(err, ErrBatchDuplicate),
+		errors.Is(err, user.ErrNotFound),
+		errors.Is(err, product.ErrUserDisabled),
+		errors.Is(err, product.ErrInvalidCost):
+		res.Status = http.StatusBadRequest
+
+	default:
+		return AppBatchResult{}, false
+	}
+
+	return res, true
+}
+
+// batchFailed reports whether an operation of the batch failed its checks.
+func batchFailed(results []AppBatchResult) bool {
+	for _, res := range results {
+		if res.Status >= http.StatusBadRequest {
+			return true
+		}
+	}
+
+	return false
+}
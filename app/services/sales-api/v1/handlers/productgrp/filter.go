@@ -5,16 +5,21 @@ import (
 	"strconv"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
 
 func parseFilter(r *http.Request) (product.QueryFilter, error) {
 	const (
-		filterByProdID   = "product_id"
-		filterByCost     = "cost"
-		filterByQuantity = "quantity"
-		filterByName     = "name"
+		filterByProdID      = "product_id"
+		filterByUserID      = "user_id"
+		filterByCost        = "cost"
+		filterByQuantity    = "quantity"
+		filterByName        = "name"
+		filterBySearch      = "q"
+		filterByDateCreated = "date_created"
+		filterByInactive    = "include_inactive"
 	)
 
 	values := r.URL.Query()
@@ -49,6 +54,48 @@ func parseFilter(r *http.Request) (product.QueryFilter, error) {
 		filter.WithName(name)
 	}
 
+	if search := values.Get(filterBySearch); search != "" {
+		filter.WithSearch(search)
+	}
+
+	productIDs, err := query.ParseList(values, filterByProdID, uuid.Parse)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithProductIDs(productIDs)
+
+	userIDs, err := query.ParseList(values, filterByUserID, uuid.Parse)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithUserIDs(userIDs)
+
+	cost, err := query.ParseRange(values, filterByCost, query.ParseFloat)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithCostRange(cost)
+
+	quantity, err := query.ParseRange(values, filterByQuantity, strconv.Atoi)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithQuantityRange(quantity)
+
+	dateCreated, err := query.ParseRange(values, filterByDateCreated, query.ParseTime)
+	if err != nil {
+		return product.QueryFilter{}, err
+	}
+	filter.WithDateCreatedRange(dateCreated)
+
+	if inactive := values.Get(filterByInactive); inactive != "" {
+		include, err := strconv.ParseBool(inactive)
+		if err != nil {
+			return product.QueryFilter{}, validate.NewFieldsError(filterByInactive, err)
+		}
+		filter.WithIncludeInactive(include)
+	}
+
 	if err := filter.Validate(); err != nil {
 		return product.QueryFilter{}, err
 	}
@@ -1,7 +1,9 @@
 package productgrp
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
@@ -17,8 +19,10 @@ type AppProduct struct {
 	Name        string  `json:"name"`
 	Cost        float64 `json:"cost"`
 	Quantity    int     `json:"quantity"`
+	Active      bool    `json:"active"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
+	Snippet     string  `json:"snippet,omitempty"`
 }
 
 func toAppProduct(prd product.Product) AppProduct {
@@ -28,8 +32,27 @@ func toAppProduct(prd product.Product) AppProduct {
 		Name:        prd.Name,
 		Cost:        prd.Cost,
 		Quantity:    prd.Quantity,
+		Active:      prd.Active,
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		Snippet:     prd.Snippet,
+	}
+}
+
+// appProductCSVHeader is the header of the products written as CSV.
+var appProductCSVHeader = []string{"id", "userID", "name", "cost", "quantity", "active", "dateCreated", "dateUpdated"}
+
+// CSVRecord implements the web.CSVRecorder interface.
+func (app AppProduct) CSVRecord() []string {
+	return []string{
+		app.ID,
+		app.UserID,
+		app.Name,
+		strconv.FormatFloat(app.Cost, 'f', -1, 64),
+		strconv.Itoa(app.Quantity),
+		strconv.FormatBool(app.Active),
+		app.DateCreated,
+		app.DateUpdated,
 	}
 }
 
@@ -45,6 +68,7 @@ type AppProductDetails struct {
 	UserName    string  `json:"userName"`
 	DateCreated string  `json:"dateCreated"`
 	DateUpdated string  `json:"dateUpdated"`
+	Snippet     string  `json:"snippet,omitempty"`
 }
 
 func toAppProductDetails(prd product.Product, usr user.User) AppProductDetails {
@@ -57,6 +81,7 @@ func toAppProductDetails(prd product.Product, usr user.User) AppProductDetails {
 		UserName:    usr.Name,
 		DateCreated: prd.DateCreated.Format(time.RFC3339),
 		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+		Snippet:     prd.Snippet,
 	}
 }
 
@@ -131,3 +156,72 @@ func (app AppUpdateProduct) Validate() error {
 
 	return nil
 }
+
+// =============================================================================
+
+// Set of modes a batch can be executed in. An atomic batch applies all of its
+// operations or none of them, a best effort one applies the operations that
+// succeed.
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "bestEffort"
+)
+
+// Set of operations a batch can hold.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// AppBatch is what we require from clients when changing products in batch.
+type AppBatch struct {
+	Mode       string              `json:"mode" validate:"required,oneof=atomic bestEffort"`
+	Operations []AppBatchOperation `json:"operations" validate:"required,min=1"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppBatch) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppBatchOperation represents a single operation of a batch. The product
+// holds an AppNewProduct for a create and an AppUpdateProduct for an update.
+// The version, when provided, must match the one of the product updated or
+// deleted.
+type AppBatchOperation struct {
+	Op      string          `json:"op" validate:"required,oneof=create update delete"`
+	ID      string          `json:"id" validate:"required_unless=Op create"`
+	Version *int            `json:"version"`
+	Product json.RawMessage `json:"product" validate:"required_unless=Op delete"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppBatchOperation) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppBatchResult represents the outcome of an operation of a batch. The
+// fields hold the validation errors of a rejected operation.
+type AppBatchResult struct {
+	Index   int                  `json:"index"`
+	Status  int                  `json:"status"`
+	Product *AppProduct          `json:"product,omitempty"`
+	Error   string               `json:"error,omitempty"`
+	Fields  validate.FieldErrors `json:"fields,omitempty"`
+}
+
+// AppBatchResults represents the outcome of a batch.
+type AppBatchResults struct {
+	Mode      string           `json:"mode"`
+	Committed bool             `json:"committed"`
+	Results   []AppBatchResult `json:"results"`
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 )
 
-func parseOrder(r *http.Request) (order.By, error) {
+// parseOrder parses the ordering of the products. A query searching the
+// products is ordered by relevance unless told otherwise, which is the only
+// kind of query that can be.
+func parseOrder(r *http.Request, filter product.QueryFilter) (order.By, error) {
 	const (
 		orderByProdID   = "product_id"
 		orderByName     = "name"
@@ -18,6 +21,8 @@ func parseOrder(r *http.Request) (order.By, error) {
 		orderBySold     = "sold"
 		orderByRevenue  = "revenue"
 		orderByUserID   = "user_id"
+
+		orderByRelevance = "relevance"
 	)
 
 	var orderByFields = map[string]string{
@@ -28,18 +33,27 @@ func parseOrder(r *http.Request) (order.By, error) {
 		orderBySold:     product.OrderBySold,
 		orderByRevenue:  product.OrderByRevenue,
 		orderByUserID:   product.OrderByUserID,
+
+		orderByRelevance: product.OrderByRelevance,
 	}
 
-	orderBy, err := order.Parse(r, order.NewBy(orderByProdID, order.ASC))
+	defaultOrder := order.NewBy(orderByProdID, order.ASC)
+	if filter.Search != nil {
+		defaultOrder = order.NewBy(orderByRelevance, order.DESC)
+	}
+
+	orderBy, err := order.Parse(r, defaultOrder)
 	if err != nil {
 		return order.By{}, err
 	}
 
-	if _, exists := orderByFields[orderBy.Field]; !exists {
-		return order.By{}, validate.NewFieldsError(orderBy.Field, errors.New("order field does not exist"))
+	if filter.Search == nil {
+		for _, by := range orderBy.Fields() {
+			if by.Field == orderByRelevance {
+				return order.By{}, validate.NewFieldsError(by.Field, errors.New("order field requires a search"))
+			}
+		}
 	}
 
-	orderBy.Field = orderByFields[orderBy.Field]
-
-	return orderBy, nil
+	return orderBy.Map(orderByFields)
 }
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 	"github.com/google/uuid"
 )
@@ -21,17 +24,23 @@ var (
 	ErrInvalidID = errors.New("ID is not in its proper form")
 )
 
+// exportWriteTimeout is how long an export can go without making progress
+// before its response is cut off.
+const exportWriteTimeout = 30 * time.Second
+
 // Handlers manages the set of product endpoints.
 type Handlers struct {
 	product *product.Core
 	user    *user.Core
+	auth    *auth.Auth
 }
 
 // New constructs a handlers for route access.
-func New(product *product.Core, user *user.Core) *Handlers {
+func New(product *product.Core, user *user.Core, auth *auth.Auth) *Handlers {
 	return &Handlers{
 		product: product,
 		user:    user,
+		auth:    auth,
 	}
 }
 
@@ -52,6 +61,7 @@ func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, erro
 		h = &Handlers{
 			user:    user,
 			product: product,
+			auth:    h.auth,
 		}
 
 		return h, nil
@@ -62,6 +72,11 @@ func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, erro
 
 // Create adds a new product to the system.
 func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
 	var app AppNewProduct
 	if err := web.Decode(r, &app); err != nil {
 		return response.NewError(err, http.StatusBadRequest)
@@ -72,11 +87,17 @@ func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return response.NewError(err, http.StatusBadRequest)
 	}
 
+	if err := h.authorizeOwner(ctx, np.UserID); err != nil {
+		return err
+	}
+
 	prd, err := h.product.Create(ctx, np)
 	if err != nil {
 		return fmt.Errorf("create: app[%+v]: %w", app, err)
 	}
 
+	w.Header().Set("ETag", response.ETag(prd.Version))
+
 	return web.Respond(ctx, w, toAppProduct(prd), http.StatusCreated)
 }
 
@@ -107,11 +128,26 @@ func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	if err := h.authorizeOwner(ctx, prd.UserID); err != nil {
+		return err
+	}
+
+	if !web.IfMatch(r, response.ETag(prd.Version)) {
+		return response.NewError(product.ErrConcurrentModification, http.StatusPreconditionFailed)
+	}
+
 	prd, err = h.product.Update(ctx, prd, toCoreUpdateProduct(app))
 	if err != nil {
-		return fmt.Errorf("update: productID[%s] app[%+v]: %w", productID, app, err)
+		switch {
+		case errors.Is(err, product.ErrConcurrentModification):
+			return response.NewError(product.ErrConcurrentModification, http.StatusPreconditionFailed)
+		default:
+			return fmt.Errorf("update: productID[%s] app[%+v]: %w", productID, app, err)
+		}
 	}
 
+	w.Header().Set("ETag", response.ETag(prd.Version))
+
 	return web.Respond(ctx, w, toAppProduct(prd), http.StatusOK)
 }
 
@@ -141,8 +177,21 @@ func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	if err := h.authorizeOwner(ctx, prd.UserID); err != nil {
+		return err
+	}
+
+	if !web.IfMatch(r, response.ETag(prd.Version)) {
+		return response.NewError(product.ErrConcurrentModification, http.StatusPreconditionFailed)
+	}
+
 	if err := h.product.Delete(ctx, prd); err != nil {
-		return fmt.Errorf("delete: productID[%s]: %w", productID, err)
+		switch {
+		case errors.Is(err, product.ErrConcurrentModification):
+			return response.NewError(product.ErrConcurrentModification, http.StatusPreconditionFailed)
+		default:
+			return fmt.Errorf("delete: productID[%s]: %w", productID, err)
+		}
 	}
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
@@ -160,48 +209,124 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return err
 	}
 
-	orderBy, err := parseOrder(r)
+	orderBy, err := parseOrder(r, filter)
 	if err != nil {
 		return err
 	}
 
+	if page.Cursor != nil {
+		return h.queryByCursor(ctx, w, r, filter, page.Cursor.Ordered(orderBy), page.RowsPerPage)
+	}
+
 	prds, err := h.product.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
 	}
 
-	// -------------------------------------------------------------------------
-	// Capture the unique set of users
+	users, err := h.queryUsers(ctx, prds)
+	if err != nil {
+		return err
+	}
 
-	users := make(map[uuid.UUID]user.User)
-	if len(prds) > 0 {
-		for _, prd := range prds {
-			users[prd.UserID] = user.User{}
-		}
+	total, err := h.product.Count(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("count: %w", err)
+	}
 
-		userIDs := make([]uuid.UUID, 0, len(users))
-		for userID := range users {
-			userIDs = append(userIDs, userID)
-		}
+	// A list has no Last-Modified since removing a product does not move the
+	// date of the products left, the ETag of the body tells them apart.
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+	}
 
-		usrs, err := h.user.QueryByIDs(ctx, userIDs)
-		if err != nil {
-			return fmt.Errorf("user.querybyids: userIDs[%s]: %w", userIDs, err)
+	return web.RespondConditional(ctx, w, r, response.NewPageDocument(toAppProductsDetails(prds, users), total, page.Number, page.RowsPerPage), cache)
+}
+
+// Export streams every product matching the filter as a JSON array, NDJSON
+// or CSV depending on the Accept header of the request.
+func (h *Handlers) Export(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return err
+	}
+
+	orderBy, err := parseOrder(r, filter)
+	if err != nil {
+		return err
+	}
+
+	cfg := web.StreamConfig{
+		CSVHeader:    appProductCSVHeader,
+		WriteTimeout: exportWriteTimeout,
+	}
+
+	produce := func(send func(v any) error) error {
+		return h.product.QueryStream(ctx, filter, orderBy, func(prd product.Product) error {
+			return send(toAppProduct(prd))
+		})
+	}
+
+	if err := web.RespondStream(ctx, w, r, cfg, produce); err != nil {
+		if errors.Is(err, web.ErrNotAcceptable) {
+			return response.NewError(err, http.StatusNotAcceptable)
 		}
+		return fmt.Errorf("export: %w", err)
+	}
+
+	return nil
+}
 
-		for _, usr := range usrs {
-			users[usr.ID] = usr
+// queryByCursor returns the page of products past the cursor.
+func (h *Handlers) queryByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, filter product.QueryFilter, cur page.Cursor, rowsPerPage int) error {
+	prds, cursors, err := h.product.QueryByCursor(ctx, filter, cur, rowsPerPage)
+	if err != nil {
+		if errors.Is(err, page.ErrCursorOrder) {
+			return response.NewError(err, http.StatusBadRequest)
+		}
+		if errors.Is(err, page.ErrInvalidCursor) {
+			return validate.NewFieldsError("cursor", err)
 		}
+		return fmt.Errorf("querybycursor: %w", err)
 	}
 
-	// -------------------------------------------------------------------------
+	users, err := h.queryUsers(ctx, prds)
+	if err != nil {
+		return err
+	}
 
-	total, err := h.product.Count(ctx, filter)
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+	}
+
+	return web.RespondConditional(ctx, w, r, response.NewCursorDocument(toAppProductsDetails(prds, users), cursors, rowsPerPage), cache)
+}
+
+// queryUsers captures the unique set of users owning the products.
+func (h *Handlers) queryUsers(ctx context.Context, prds []product.Product) (map[uuid.UUID]user.User, error) {
+	users := make(map[uuid.UUID]user.User)
+	if len(prds) == 0 {
+		return users, nil
+	}
+
+	for _, prd := range prds {
+		users[prd.UserID] = user.User{}
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+
+	usrs, err := h.user.QueryByIDs(ctx, userIDs)
 	if err != nil {
-		return fmt.Errorf("count: %w", err)
+		return nil, fmt.Errorf("user.querybyids: userIDs[%s]: %w", userIDs, err)
 	}
 
-	return web.Respond(ctx, w, response.NewPageDocument(toAppProductsDetails(prds, users), total, page.Number, page.RowsPerPage), http.StatusOK)
+	for _, usr := range usrs {
+		users[usr.ID] = usr
+	}
+
+	return users, nil
 }
 
 // QueryByID returns a product by its ID.
@@ -221,5 +346,23 @@ func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http
 		}
 	}
 
-	return web.Respond(ctx, w, toAppProduct(prd), http.StatusOK)
+	w.Header().Set("ETag", response.ETag(prd.Version))
+
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+		LastModified: prd.DateUpdated,
+	}
+
+	return web.RespondConditional(ctx, w, r, toAppProduct(prd), cache)
+}
+
+// authorizeOwner checks the caller is an admin or the owner of the product.
+func (h *Handlers) authorizeOwner(ctx context.Context, ownerID uuid.UUID) error {
+	claims := auth.GetClaims(ctx)
+
+	if err := h.auth.AuthorizeOwner(ctx, claims, ownerID); err != nil {
+		return auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrOwner, err)
+	}
+
+	return nil
 }
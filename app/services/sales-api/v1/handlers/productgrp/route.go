@@ -3,7 +3,10 @@ package productgrp
 import (
 	"net/http"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
@@ -29,17 +32,22 @@ type Config struct {
 func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
-	envCore := event.NewCore(cfg.Log)
-	usrCore := user.NewCore(cfg.Log, envCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
-	prdCore := product.NewCore(cfg.Log, envCore, usrCore, productdb.NewStore(cfg.Log, cfg.DB))
+	envCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(cfg.Log, envCore, audCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
+	prdCore := product.NewCore(cfg.Log, envCore, audCore, usrCore, productdb.NewStore(cfg.Log, cfg.DB))
 
 	authen := mid.Authenticate(cfg.Auth)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermProductRead)
+	permWrite := mid.AuthorizePermission(cfg.Auth, auth.PermProductWrite)
 	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
 
-	hdl := New(prdCore, usrCore)
-	app.Handle(http.MethodGet, version, "/products", hdl.Query, authen)
-	app.Handle(http.MethodGet, version, "/products/:product_id", hdl.QueryByID, authen)
-	app.Handle(http.MethodPost, version, "/products", hdl.Create, authen)
-	app.Handle(http.MethodPut, version, "/products/:product_id", hdl.Update, authen, tran)
-	app.Handle(http.MethodDelete, version, "/products/:product_id", hdl.Delete, authen, tran)
+	hdl := New(prdCore, usrCore, cfg.Auth)
+	app.Handle(http.MethodGet, version, "/products", hdl.Query, authen, permRead)
+	app.Handle(http.MethodGet, version, "/products/export", hdl.Export, authen, permRead)
+	app.Handle(http.MethodGet, version, "/products/:product_id", hdl.QueryByID, authen, permRead)
+	app.Handle(http.MethodPost, version, "/products", hdl.Create, authen, permWrite, tran)
+	app.Handle(http.MethodPost, version, "/products:batch", hdl.Batch, authen, permWrite, tran)
+	app.Handle(http.MethodPut, version, "/products/:product_id", hdl.Update, authen, permWrite, tran)
+	app.Handle(http.MethodDelete, version, "/products/:product_id", hdl.Delete, authen, permWrite, tran)
 }
@@ -0,0 +1,100 @@
+package rolegrp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+)
+
+// AppRole represents information about an individual role.
+type AppRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+func toAppRole(rol role.Role) AppRole {
+	perms := rol.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+
+	return AppRole{
+		Name:        rol.Name.Name(),
+		Description: rol.Description,
+		Permissions: perms,
+		DateCreated: rol.DateCreated.Format(time.RFC3339),
+		DateUpdated: rol.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppRoles(roles []role.Role) []AppRole {
+	items := make([]AppRole, len(roles))
+	for i, rol := range roles {
+		items[i] = toAppRole(rol)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewRole contains information needed to create a new role.
+type AppNewRole struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+func toCoreNewRole(app AppNewRole) (role.NewRole, error) {
+	name, err := user.ParseRole(app.Name)
+	if err != nil {
+		return role.NewRole{}, fmt.Errorf("parsing role: %w", err)
+	}
+
+	nr := role.NewRole{
+		Name:        name,
+		Description: app.Description,
+		Permissions: app.Permissions,
+	}
+
+	return nr, nil
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewRole) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// AppUpdateRole contains information needed to update a role.
+type AppUpdateRole struct {
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func toCoreUpdateRole(app AppUpdateRole) role.UpdateRole {
+	return role.UpdateRole{
+		Description: app.Description,
+		Permissions: app.Permissions,
+	}
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppUpdateRole) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+// Package rolegrp maintains the group of handlers for role access.
+package rolegrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+// Handlers manages the set of role endpoints.
+type Handlers struct {
+	role *role.Core
+}
+
+// New constructs a handlers for route access.
+func New(role *role.Core) *Handlers {
+	return &Handlers{
+		role: role,
+	}
+}
+
+// executeUnderTransaction constructs a new Handlers value with the core apis
+// using a store transaction that was created via middleware.
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		role, err := h.role.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &Handlers{
+			role: role,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+// Create adds a new role to the system.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewRole
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	nr, err := toCoreNewRole(app)
+	if err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	rol, err := h.role.Create(ctx, nr)
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrUniqueName):
+			return response.NewError(err, http.StatusConflict)
+		case errors.Is(err, role.ErrInvalidPermission):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("create: rol[%+v]: %w", nr, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppRole(rol), http.StatusCreated)
+}
+
+// Update updates a role in the system.
+func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppUpdateRole
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	rol, err := h.queryRole(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	rol, err = h.role.Update(ctx, rol, toCoreUpdateRole(app))
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrInvalidPermission):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("update: name[%s]: %w", rol.Name.Name(), err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppRole(rol), http.StatusOK)
+}
+
+// Delete removes a role from the system.
+func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	name, err := user.ParseRole(web.Param(r, "name"))
+	if err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	rol, err := h.role.QueryByName(ctx, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrNotFound):
+			return web.Respond(ctx, w, nil, http.StatusNoContent)
+		default:
+			return fmt.Errorf("querybyname: name[%s]: %w", name.Name(), err)
+		}
+	}
+
+	if err := h.role.Delete(ctx, rol); err != nil {
+		switch {
+		case errors.Is(err, role.ErrBuiltIn):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("delete: name[%s]: %w", rol.Name.Name(), err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Query returns every role.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	roles, err := h.role.Query(ctx)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppRoles(roles), http.StatusOK)
+}
+
+// QueryByName returns a role by its name.
+func (h *Handlers) QueryByName(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	rol, err := h.queryRole(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, toAppRole(rol), http.StatusOK)
+}
+
+// =============================================================================
+
+// queryRole retrieves the role specified in the request.
+func (h *Handlers) queryRole(ctx context.Context, r *http.Request) (role.Role, error) {
+	name, err := user.ParseRole(web.Param(r, "name"))
+	if err != nil {
+		return role.Role{}, response.NewError(err, http.StatusBadRequest)
+	}
+
+	rol, err := h.role.QueryByName(ctx, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, role.ErrNotFound):
+			return role.Role{}, response.NewError(err, http.StatusNotFound)
+		default:
+			return role.Role{}, fmt.Errorf("querybyname: name[%s]: %w", name.Name(), err)
+		}
+	}
+
+	return rol, nil
+}
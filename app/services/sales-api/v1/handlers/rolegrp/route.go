@@ -0,0 +1,41 @@
+package rolegrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role/stores/roledb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	rolCore := role.NewCore(cfg.Log, roledb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermRoleRead)
+	permWrite := mid.AuthorizePermission(cfg.Auth, auth.PermRoleWrite)
+	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := New(rolCore)
+	app.Handle(http.MethodGet, version, "/roles", hdl.Query, authen, permRead)
+	app.Handle(http.MethodGet, version, "/roles/:name", hdl.QueryByName, authen, permRead)
+	app.Handle(http.MethodPost, version, "/roles", hdl.Create, authen, permWrite, tran)
+	app.Handle(http.MethodPut, version, "/roles/:name", hdl.Update, authen, permWrite, tran)
+	app.Handle(http.MethodDelete, version, "/roles/:name", hdl.Delete, authen, permWrite, tran)
+}
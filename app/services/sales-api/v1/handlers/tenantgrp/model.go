@@ -0,0 +1,56 @@
+package tenantgrp
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+)
+
+// AppTenant represents information about an individual tenant.
+type AppTenant struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DateCreated string `json:"dateCreated"`
+	DateUpdated string `json:"dateUpdated"`
+}
+
+func toAppTenant(tnt tenant.Tenant) AppTenant {
+	return AppTenant{
+		ID:          tnt.ID.String(),
+		Name:        tnt.Name,
+		DateCreated: tnt.DateCreated.Format(time.RFC3339),
+		DateUpdated: tnt.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppTenants(tnts []tenant.Tenant) []AppTenant {
+	items := make([]AppTenant, len(tnts))
+	for i, tnt := range tnts {
+		items[i] = toAppTenant(tnt)
+	}
+
+	return items
+}
+
+// =============================================================================
+
+// AppNewTenant contains information needed to create a new tenant.
+type AppNewTenant struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func toCoreNewTenant(app AppNewTenant) tenant.NewTenant {
+	return tenant.NewTenant{
+		Name: app.Name,
+	}
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppNewTenant) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package tenantgrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role/stores/roledb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant/stores/tenantdb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	DB    *sqlx.DB
+	Auth  *auth.Auth
+}
+
+// Routes adds specific routes for this group.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	tntCore := tenant.NewCore(cfg.Log, tenantdb.NewStore(cfg.Log, cfg.DB))
+	rolCore := role.NewCore(cfg.Log, roledb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermTenantRead)
+	permWrite := mid.AuthorizePermission(cfg.Auth, auth.PermTenantWrite)
+	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := New(tntCore, rolCore)
+	app.Handle(http.MethodGet, version, "/tenants", hdl.Query, authen, permRead)
+	app.Handle(http.MethodGet, version, "/tenants/:tenant_id", hdl.QueryByID, authen, permRead)
+	app.Handle(http.MethodPost, version, "/tenants", hdl.Create, authen, permWrite, tran)
+}
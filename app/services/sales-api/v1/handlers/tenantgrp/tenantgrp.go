@@ -0,0 +1,119 @@
+// Package tenantgrp maintains the group of handlers for tenant access.
+package tenantgrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for handling tenant group errors.
+var (
+	ErrInvalidID = errors.New("ID is not in its proper form")
+)
+
+// Handlers manages the set of tenant endpoints.
+type Handlers struct {
+	tenant *tenant.Core
+	role   *role.Core
+}
+
+// New constructs a handlers for route access.
+func New(tenant *tenant.Core, role *role.Core) *Handlers {
+	return &Handlers{
+		tenant: tenant,
+		role:   role,
+	}
+}
+
+// executeUnderTransaction constructs a new Handlers value with the core apis
+// using a store transaction that was created via middleware.
+func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		tenant, err := h.tenant.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		role, err := h.role.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &Handlers{
+			tenant: tenant,
+			role:   role,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+// Create adds a new tenant to the system along with the built in roles.
+func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppNewTenant
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	tnt, err := h.tenant.Create(ctx, toCoreNewTenant(app))
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrUniqueName):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("create: app[%+v]: %w", app, err)
+		}
+	}
+
+	if err := h.role.CreateBuiltIn(tenant.Set(ctx, tnt.ID)); err != nil {
+		return fmt.Errorf("role.createbuiltin: tenantID[%s]: %w", tnt.ID, err)
+	}
+
+	return web.Respond(ctx, w, toAppTenant(tnt), http.StatusCreated)
+}
+
+// Query returns every tenant.
+func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tnts, err := h.tenant.Query(ctx)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	return web.Respond(ctx, w, toAppTenants(tnts), http.StatusOK)
+}
+
+// QueryByID returns a tenant by its ID.
+func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	tenantID, err := uuid.Parse(web.Param(r, "tenant_id"))
+	if err != nil {
+		return response.NewError(ErrInvalidID, http.StatusBadRequest)
+	}
+
+	tnt, err := h.tenant.QueryByID(ctx, tenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrNotFound):
+			return response.NewError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: tenantID[%s]: %w", tenantID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppTenant(tnt), http.StatusOK)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
@@ -17,6 +18,7 @@ func parseFilter(r *http.Request) (user.QueryFilter, error) {
 		filterByStartCreatedDate = "start_created_date"
 		filterByEndCreatedDate   = "end_created_date"
 		filterByName             = "name"
+		filterByDateCreated      = "date_created"
 	)
 
 	values := r.URL.Query()
@@ -59,6 +61,18 @@ func parseFilter(r *http.Request) (user.QueryFilter, error) {
 		filter.WithName(name)
 	}
 
+	userIDs, err := query.ParseList(values, filterByUserID, uuid.Parse)
+	if err != nil {
+		return user.QueryFilter{}, err
+	}
+	filter.WithUserIDs(userIDs)
+
+	dateCreated, err := query.ParseRange(values, filterByDateCreated, query.ParseTime)
+	if err != nil {
+		return user.QueryFilter{}, err
+	}
+	filter.WithDateCreatedRange(dateCreated)
+
 	if err := filter.Validate(); err != nil {
 		return user.QueryFilter{}, err
 	}
@@ -5,21 +5,27 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
 )
 
 // AppUser represents information about an individual user.
 type AppUser struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	Email        string   `json:"email"`
-	Roles        []string `json:"roles"`
-	PasswordHash []byte   `json:"-"`
-	Department   string   `json:"department"`
-	Enabled      bool     `json:"enabled"`
-	DateCreated  string   `json:"dateCreated"`
-	DateUpdated  string   `json:"dateUpdated"`
+	ID            string   `json:"id"`
+	TenantID      string   `json:"tenantID"`
+	Name          string   `json:"name"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"emailVerified"`
+	Roles         []string `json:"roles"`
+	PasswordHash  []byte   `json:"-"`
+	Department    string   `json:"department"`
+	Enabled       bool     `json:"enabled"`
+	FailedLogins  int      `json:"failedLogins"`
+	LockedUntil   string   `json:"lockedUntil,omitempty"`
+	DateCreated   string   `json:"dateCreated"`
+	DateUpdated   string   `json:"dateUpdated"`
 }
 
 func toAppUser(usr user.User) AppUser {
@@ -28,16 +34,25 @@ func toAppUser(usr user.User) AppUser {
 		roles[i] = role.Name()
 	}
 
+	var lockedUntil string
+	if !usr.LockedUntil.IsZero() {
+		lockedUntil = usr.LockedUntil.Format(time.RFC3339)
+	}
+
 	return AppUser{
-		ID:           usr.ID.String(),
-		Name:         usr.Name,
-		Email:        usr.Email.Address,
-		Roles:        roles,
-		PasswordHash: usr.PasswordHash,
-		Department:   usr.Department,
-		Enabled:      usr.Enabled,
-		DateCreated:  usr.DateCreated.Format(time.RFC3339),
-		DateUpdated:  usr.DateUpdated.Format(time.RFC3339),
+		ID:            usr.ID.String(),
+		TenantID:      usr.TenantID.String(),
+		Name:          usr.Name,
+		Email:         usr.Email.Address,
+		EmailVerified: usr.EmailVerified,
+		Roles:         roles,
+		PasswordHash:  usr.PasswordHash,
+		Department:    usr.Department,
+		Enabled:       usr.Enabled,
+		FailedLogins:  usr.FailedLogins,
+		LockedUntil:   lockedUntil,
+		DateCreated:   usr.DateCreated.Format(time.RFC3339),
+		DateUpdated:   usr.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -54,6 +69,7 @@ func toAppUsers(users []user.User) []AppUser {
 
 // AppNewUser contains information needed to create a new user.
 type AppNewUser struct {
+	TenantID        string   `json:"tenantID" validate:"omitempty,uuid"`
 	Name            string   `json:"name" validate:"required"`
 	Email           string   `json:"email" validate:"required,email"`
 	Roles           []string `json:"roles" validate:"required"`
@@ -77,7 +93,16 @@ func toCoreNewUser(app AppNewUser) (user.NewUser, error) {
 		return user.NewUser{}, fmt.Errorf("parsing email: %w", err)
 	}
 
+	var tenantID uuid.UUID
+	if app.TenantID != "" {
+		tenantID, err = uuid.Parse(app.TenantID)
+		if err != nil {
+			return user.NewUser{}, fmt.Errorf("parsing tenant: %w", err)
+		}
+	}
+
 	usr := user.NewUser{
+		TenantID:        tenantID,
 		Name:            app.Name,
 		Email:           *addr,
 		Roles:           roles,
@@ -158,11 +183,143 @@ func (app AppUpdateUser) Validate() error {
 // =============================================================================
 
 type token struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
 }
 
-func toToken(v string) token {
+func toToken(v string, refresh string) token {
 	return token{
-		Token: v,
+		Token:        v,
+		RefreshToken: refresh,
+	}
+}
+
+// AppMFAChallenge is returned in place of a token when the user has to
+// provide a second factor to complete the login.
+type AppMFAChallenge struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+	DateExpires string `json:"dateExpires"`
+}
+
+func toAppMFAChallenge(raw string, ch mfa.Challenge) AppMFAChallenge {
+	return AppMFAChallenge{
+		MFARequired: true,
+		MFAToken:    raw,
+		DateExpires: ch.DateExpires.Format(time.RFC3339),
+	}
+}
+
+// AppMFAVerify contains the second factor that completes a login. The code
+// can be a TOTP code or one of the recovery codes.
+type AppMFAVerify struct {
+	MFAToken string `json:"mfaToken" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppMFAVerify) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppMFAEnrollment contains the secret a user adds to their authenticator
+// app, along with the provisioning URI to render as a QR code.
+type AppMFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func toAppMFAEnrollment(enr mfa.Enrollment, issuer string, account string) AppMFAEnrollment {
+	return AppMFAEnrollment{
+		Secret: enr.Secret,
+		URI:    enr.URI(issuer, account),
+	}
+}
+
+// AppMFAConfirm contains the code that confirms an enrollment.
+type AppMFAConfirm struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppMFAConfirm) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppRecoveryCodes contains the recovery codes of a user. They are only
+// returned once.
+type AppRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// AppForgotPassword contains the email of the account to reset the password
+// of.
+type AppForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppForgotPassword) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppResetPassword contains the emailed token and the new password.
+type AppResetPassword struct {
+	Token           string `json:"token" validate:"required"`
+	Password        string `json:"password" validate:"required"`
+	PasswordConfirm string `json:"passwordConfirm" validate:"eqfield=Password"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppResetPassword) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppVerifyEmail contains the emailed token that verifies an email.
+type AppVerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppVerifyEmail) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppRefreshToken contains the refresh token to exchange for a new token.
+type AppRefreshToken struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (app AppRefreshToken) Validate() error {
+	if err := validate.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
 	}
+
+	return nil
+}
+
+// AppRevokeToken contains the optional refresh token to revoke on logout.
+type AppRevokeToken struct {
+	RefreshToken string `json:"refreshToken"`
 }
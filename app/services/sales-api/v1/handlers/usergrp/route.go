@@ -3,7 +3,22 @@ package usergrp
 import (
 	"net/http"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account/stores/accountdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey/stores/apikeydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa/stores/mfadb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role/stores/roledb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session/stores/sessiondb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant/stores/tenantdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/usercache"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
@@ -16,10 +31,14 @@ import (
 )
 
 type Config struct {
-	Build string
-	Log   *logger.Logger
-	DB    *sqlx.DB
-	Auth  *auth.Auth
+	Build     string
+	Log       *logger.Logger
+	DB        *sqlx.DB
+	Auth      *auth.Auth
+	Lockout   user.LockoutConfig
+	Mailer    account.Mailer
+	ResetURL  string
+	VerifyURL string
 }
 
 func Routes(app *web.App, cfg Config) {
@@ -28,16 +47,45 @@ func Routes(app *web.App, cfg Config) {
 	authen := mid.Authenticate(cfg.Auth)
 	ruleAdmin := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
 	ruleAdminOrSubject := mid.Authorize(cfg.Auth, auth.RuleAdminOrSubject)
+	ruleAdminMFA := mid.Authorize(cfg.Auth, auth.RuleAdminMFA)
+	ruleAny := mid.Authorize(cfg.Auth, auth.RuleAny)
+	permRead := mid.AuthorizePermission(cfg.Auth, auth.PermUserRead)
+	permWrite := mid.AuthorizePermission(cfg.Auth, auth.PermUserWrite)
+	unscoped := mid.Unscoped()
 	tran := mid.ExecuteInTransation(cfg.Log, db.NewBeginner(cfg.DB))
 
-	envCore := event.NewCore(cfg.Log)
-	usrCore := user.NewCore(cfg.Log, envCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)))
+	envCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+	audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(cfg.Log, envCore, audCore, usercache.NewStore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB)), user.WithLockout(cfg.Lockout))
+	sesCore := session.NewCore(cfg.Log, sessiondb.NewStore(cfg.Log, cfg.DB))
+	apkCore := apikey.NewCore(cfg.Log, usrCore, apikeydb.NewStore(cfg.Log, cfg.DB))
+	mfaCore := mfa.NewCore(cfg.Log, mfadb.NewStore(cfg.Log, cfg.DB))
+	accCore := account.NewCore(cfg.Log, envCore, usrCore, sesCore, apkCore, cfg.Mailer, accountdb.NewStore(cfg.Log, cfg.DB), account.WithLinks(cfg.ResetURL, cfg.VerifyURL))
 
-	hdl := New(usrCore, cfg.Auth)
-	app.Handle(http.MethodGet, version, "/users/token/:kid", hdl.Token)
-	app.Handle(http.MethodGet, version, "/users", hdl.Query, authen, ruleAdmin)
-	app.Handle(http.MethodGet, version, "/users/:user_id", hdl.QueryByID, authen, ruleAdminOrSubject)
-	app.Handle(http.MethodPost, version, "/users", hdl.Create, authen, ruleAdmin, tran)
-	app.Handle(http.MethodPut, version, "/users/:user_id", hdl.Update, authen, ruleAdminOrSubject, tran)
-	app.Handle(http.MethodDelete, version, "/users/:user_id", hdl.Delete, authen, ruleAdminOrSubject, tran)
+	rolCore := role.NewCore(cfg.Log, roledb.NewStore(cfg.Log, cfg.DB))
+	tntCore := tenant.NewCore(cfg.Log, tenantdb.NewStore(cfg.Log, cfg.DB))
+
+	hdl := New(usrCore, sesCore, mfaCore, accCore, rolCore, tntCore, cfg.Auth)
+	app.Handle(http.MethodGet, version, "/users/token", hdl.Token, unscoped)
+	app.Handle(http.MethodPost, version, "/users/token/mfa", hdl.TokenMFA, unscoped)
+	app.Handle(http.MethodPost, version, "/users/token/refresh", hdl.RefreshToken, unscoped, tran)
+	app.Handle(http.MethodPost, version, "/users/token/revoke", hdl.RevokeToken, authen)
+
+	// Tokens are signed with the active kid of the key ring. These routes are
+	// kept for clients that still provide a kid, which is ignored.
+	app.Handle(http.MethodGet, version, "/users/token/:kid", hdl.Token, unscoped)
+	app.Handle(http.MethodPost, version, "/users/token/:kid/refresh", hdl.RefreshToken, unscoped, tran)
+	app.Handle(http.MethodGet, version, "/users", hdl.Query, authen, ruleAdmin, permRead)
+	app.Handle(http.MethodGet, version, "/users/:user_id", hdl.QueryByID, authen, ruleAdminOrSubject, permRead)
+	app.Handle(http.MethodPost, version, "/users", hdl.Create, authen, ruleAdmin, permWrite, tran)
+	app.Handle(http.MethodPut, version, "/users/:user_id", hdl.Update, authen, ruleAdminOrSubject, permWrite, tran)
+	app.Handle(http.MethodDelete, version, "/users/:user_id", hdl.Delete, authen, ruleAdminOrSubject, permWrite, tran)
+	app.Handle(http.MethodPost, version, "/users/:user_id/unlock", hdl.Unlock, authen, ruleAdmin, permWrite, tran)
+	app.Handle(http.MethodPost, version, "/users/mfa/enroll", hdl.EnrollMFA, authen, ruleAny, tran)
+	app.Handle(http.MethodPost, version, "/users/mfa/confirm", hdl.ConfirmMFA, authen, ruleAny, tran)
+	app.Handle(http.MethodDelete, version, "/users/:user_id/mfa", hdl.DisableMFA, authen, ruleAdminMFA, permWrite, tran)
+	app.Handle(http.MethodPost, version, "/users/password/forgot", hdl.ForgotPassword, unscoped, tran)
+	app.Handle(http.MethodPost, version, "/users/password/reset", hdl.ResetPassword, unscoped, tran)
+	app.Handle(http.MethodPost, version, "/users/email/verification", hdl.RequestEmailVerification, authen, ruleAny, tran)
+	app.Handle(http.MethodPost, version, "/users/email/verify", hdl.VerifyEmail, unscoped, tran)
 }
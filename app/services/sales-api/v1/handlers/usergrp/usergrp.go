@@ -7,10 +7,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/mail"
+	"strconv"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
@@ -19,19 +26,30 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 // Handlers manages the set of user endpoints.
 type Handlers struct {
-	user *user.Core
-	auth *auth.Auth
+	user    *user.Core
+	session *session.Core
+	mfa     *mfa.Core
+	account *account.Core
+	role    *role.Core
+	tenant  *tenant.Core
+	auth    *auth.Auth
 }
 
 // New constructs a handlers for route access.
-func New(user *user.Core, auth *auth.Auth) *Handlers {
+func New(user *user.Core, session *session.Core, mfa *mfa.Core, account *account.Core, role *role.Core, tenant *tenant.Core, auth *auth.Auth) *Handlers {
 	return &Handlers{
-		user: user,
-		auth: auth,
+		user:    user,
+		session: session,
+		mfa:     mfa,
+		account: account,
+		role:    role,
+		tenant:  tenant,
+		auth:    auth,
 	}
 }
 
@@ -44,9 +62,39 @@ func (h *Handlers) executeUnderTransaction(ctx context.Context) (*Handlers, erro
 			return nil, err
 		}
 
+		session, err := h.session.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		mfa, err := h.mfa.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		account, err := h.account.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		role, err := h.role.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		tenant, err := h.tenant.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
 		h = &Handlers{
-			user: user,
-			auth: h.auth,
+			user:    user,
+			session: session,
+			mfa:     mfa,
+			account: account,
+			role:    role,
+			tenant:  tenant,
+			auth:    h.auth,
 		}
 
 		return h, nil
@@ -72,6 +120,23 @@ func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return response.NewError(err, http.StatusBadRequest)
 	}
 
+	if err := h.checkTenant(ctx, nc.TenantID); err != nil {
+		return err
+	}
+
+	// The roles are checked against the tenant the user is created in.
+	rolCtx := ctx
+	if nc.TenantID != uuid.Nil {
+		rolCtx = tenant.Set(ctx, nc.TenantID)
+	}
+
+	if err := h.role.Check(rolCtx, nc.Roles); err != nil {
+		if errors.Is(err, role.ErrUnknownRole) {
+			return response.NewError(err, http.StatusBadRequest)
+		}
+		return fmt.Errorf("role.check: %w", err)
+	}
+
 	usr, err := h.user.Create(ctx, nc)
 	if err != nil {
 		if errors.Is(err, user.ErrUniqueEmail) {
@@ -80,6 +145,8 @@ func (h *Handlers) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return fmt.Errorf("create: usr[%+v]: %w", usr, err)
 	}
 
+	w.Header().Set("ETag", response.ETag(usr.Version))
+
 	return web.Respond(ctx, w, toAppUser(usr), http.StatusCreated)
 }
 
@@ -107,16 +174,36 @@ func (h *Handlers) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	if !web.IfMatch(r, response.ETag(usr.Version)) {
+		return response.NewError(user.ErrConcurrentModification, http.StatusPreconditionFailed)
+	}
+
 	uu, err := toCoreUpdateUser(app)
 	if err != nil {
 		return response.NewError(err, http.StatusBadRequest)
 	}
 
+	if uu.Roles != nil {
+		if err := h.role.Check(ctx, uu.Roles); err != nil {
+			if errors.Is(err, role.ErrUnknownRole) {
+				return response.NewError(err, http.StatusBadRequest)
+			}
+			return fmt.Errorf("role.check: %w", err)
+		}
+	}
+
 	usr, err = h.user.Update(ctx, usr, uu)
 	if err != nil {
-		return fmt.Errorf("update: userID[%s] uu[%+v]: %w", userID, uu, err)
+		switch {
+		case errors.Is(err, user.ErrConcurrentModification):
+			return response.NewError(user.ErrConcurrentModification, http.StatusPreconditionFailed)
+		default:
+			return fmt.Errorf("update: userID[%s] uu[%+v]: %w", userID, uu, err)
+		}
 	}
 
+	w.Header().Set("ETag", response.ETag(usr.Version))
+
 	return web.Respond(ctx, w, toAppUser(usr), http.StatusOK)
 }
 
@@ -139,8 +226,153 @@ func (h *Handlers) Delete(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	if !web.IfMatch(r, response.ETag(usr.Version)) {
+		return response.NewError(user.ErrConcurrentModification, http.StatusPreconditionFailed)
+	}
+
 	if err := h.user.Delete(ctx, usr); err != nil {
-		return fmt.Errorf("delete: userID[%s]: %w", userID, err)
+		switch {
+		case errors.Is(err, user.ErrConcurrentModification):
+			return response.NewError(user.ErrConcurrentModification, http.StatusPreconditionFailed)
+		default:
+			return fmt.Errorf("delete: userID[%s]: %w", userID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// Unlock clears the failed logins of a user and lifts any lockout.
+func (h *Handlers) Unlock(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID := auth.GetUserID(ctx)
+
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return response.NewError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+		}
+	}
+
+	usr, err = h.user.Unlock(ctx, usr)
+	if err != nil {
+		return fmt.Errorf("unlock: userID[%s]: %w", userID, err)
+	}
+
+	return web.Respond(ctx, w, toAppUser(usr), http.StatusOK)
+}
+
+// ForgotPassword requests a password reset token to be emailed to the account
+// with the email. The email is sent in the background, so the response is the
+// same whether or not the email has an account.
+func (h *Handlers) ForgotPassword(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppForgotPassword
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	addr, err := mail.ParseAddress(app.Email)
+	if err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	if err := h.account.RequestPasswordReset(ctx, *addr); err != nil {
+		return fmt.Errorf("requestpasswordreset: %w", err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// ResetPassword sets a new password with an emailed reset token.
+func (h *Handlers) ResetPassword(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppResetPassword
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	if _, err := h.account.ResetPassword(ctx, app.Token, app.Password); err != nil {
+		switch {
+		case errors.Is(err, account.ErrInvalidToken), errors.Is(err, account.ErrTokenExpired):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("resetpassword: %w", err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// RequestEmailVerification emails a verification token to the authenticated
+// user.
+func (h *Handlers) RequestEmailVerification(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return response.NewError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+		}
+	}
+
+	if err := h.account.RequestEmailVerification(ctx, usr); err != nil {
+		switch {
+		case errors.Is(err, account.ErrAlreadyVerified):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("requestemailverification: userID[%s]: %w", userID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// VerifyEmail marks an email as verified with an emailed verification token.
+func (h *Handlers) VerifyEmail(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppVerifyEmail
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	if _, err := h.account.VerifyEmail(ctx, app.Token); err != nil {
+		switch {
+		case errors.Is(err, account.ErrInvalidToken), errors.Is(err, account.ErrTokenExpired):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("verifyemail: %w", err)
+		}
 	}
 
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
@@ -163,6 +395,10 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return err
 	}
 
+	if page.Cursor != nil {
+		return h.queryByCursor(ctx, w, r, filter, page.Cursor.Ordered(orderBy), page.RowsPerPage)
+	}
+
 	users, err := h.user.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
@@ -173,7 +409,33 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return fmt.Errorf("count: %w", err)
 	}
 
-	return web.Respond(ctx, w, response.NewPageDocument(toAppUsers(users), total, page.Number, page.RowsPerPage), http.StatusOK)
+	// A list has no Last-Modified since removing a user does not move the
+	// date of the users left, the ETag of the body tells them apart.
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+	}
+
+	return web.RespondConditional(ctx, w, r, response.NewPageDocument(toAppUsers(users), total, page.Number, page.RowsPerPage), cache)
+}
+
+// queryByCursor returns the page of users past the cursor.
+func (h *Handlers) queryByCursor(ctx context.Context, w http.ResponseWriter, r *http.Request, filter user.QueryFilter, cur page.Cursor, rowsPerPage int) error {
+	users, cursors, err := h.user.QueryByCursor(ctx, filter, cur, rowsPerPage)
+	if err != nil {
+		if errors.Is(err, page.ErrCursorOrder) {
+			return response.NewError(err, http.StatusBadRequest)
+		}
+		if errors.Is(err, page.ErrInvalidCursor) {
+			return validate.NewFieldsError("cursor", err)
+		}
+		return fmt.Errorf("querybycursor: %w", err)
+	}
+
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+	}
+
+	return web.RespondConditional(ctx, w, r, response.NewCursorDocument(toAppUsers(users), cursors, rowsPerPage), cache)
 }
 
 // QueryByID returns a user by its ID.
@@ -190,17 +452,20 @@ func (h *Handlers) QueryByID(ctx context.Context, w http.ResponseWriter, r *http
 		}
 	}
 
-	return web.Respond(ctx, w, toAppUser(usr), http.StatusOK)
-}
+	w.Header().Set("ETag", response.ETag(usr.Version))
 
-// Token provides an API token for the authenticated user.
-func (h *Handlers) Token(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	kid := web.Param(r, "kid")
-	fmt.Println(kid)
-	if kid == "" {
-		return validate.NewFieldsError("kid", errors.New("missing kid, afinal kid é"))
+	cache := web.CacheConfig{
+		CacheControl: response.CacheRevalidate,
+		LastModified: usr.DateUpdated,
 	}
 
+	return web.RespondConditional(ctx, w, r, toAppUser(usr), cache)
+}
+
+// Token provides an API token and a refresh token for the authenticated user.
+// The API token is signed with the active kid of the key ring. A user with a
+// second factor gets a challenge instead that is completed with TokenMFA.
+func (h *Handlers) Token(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	email, pass, ok := r.BasicAuth()
 	if !ok {
 		return auth.NewAuthError("must provide email and password in Basic auth")
@@ -213,7 +478,12 @@ func (h *Handlers) Token(ctx context.Context, w http.ResponseWriter, r *http.Req
 
 	usr, err := h.user.Authenticate(ctx, *addr, pass)
 	if err != nil {
+		var le *user.LockedError
 		switch {
+		case errors.As(err, &le):
+			retry := int(math.Ceil(time.Until(le.Until).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(max(retry, 1)))
+			return response.NewError(user.ErrAccountLocked, http.StatusLocked)
 		case errors.Is(err, user.ErrNotFound):
 			return response.NewError(err, http.StatusNotFound)
 		case errors.Is(err, user.ErrAuthenticationFailure):
@@ -223,20 +493,320 @@ func (h *Handlers) Token(ctx context.Context, w http.ResponseWriter, r *http.Req
 		}
 	}
 
+	enabled, err := h.mfa.Enabled(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("mfa.enabled: userID[%s]: %w", usr.ID, err)
+	}
+
+	if enabled {
+		raw, ch, err := h.mfa.Challenge(ctx, usr.ID)
+		if err != nil {
+			switch {
+			case errors.Is(err, mfa.ErrLocked):
+				return response.NewError(err, http.StatusLocked)
+			default:
+				return fmt.Errorf("mfa.challenge: userID[%s]: %w", usr.ID, err)
+			}
+		}
+
+		return web.Respond(ctx, w, toAppMFAChallenge(raw, ch), http.StatusOK)
+	}
+
+	return h.issueToken(ctx, w, usr, []string{auth.AMRPassword})
+}
+
+// TokenMFA completes a login that is waiting for the second factor and
+// provides an API token and a refresh token for the user.
+func (h *Handlers) TokenMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppMFAVerify
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	ch, err := h.mfa.Verify(ctx, app.MFAToken, app.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidChallenge), errors.Is(err, mfa.ErrChallengeExpired), errors.Is(err, mfa.ErrInvalidCode), errors.Is(err, mfa.ErrNotEnrolled):
+			return auth.NewAuthError(err.Error())
+		case errors.Is(err, mfa.ErrLocked):
+			return response.NewError(err, http.StatusLocked)
+		default:
+			return fmt.Errorf("mfa.verify: %w", err)
+		}
+	}
+
+	usr, err := h.user.QueryByID(ctx, ch.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return auth.NewAuthError(err.Error())
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", ch.UserID, err)
+		}
+	}
+
+	if !usr.Enabled {
+		return auth.NewAuthError("user disabled")
+	}
+
+	return h.issueToken(ctx, w, usr, []string{auth.AMRPassword, auth.AMROTP, auth.AMRMFA})
+}
+
+// EnrollMFA starts the enrollment of a TOTP second factor for the
+// authenticated user.
+func (h *Handlers) EnrollMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return response.NewError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+		}
+	}
+
+	enr, err := h.mfa.Enroll(ctx, usr.ID, usr.TenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrAlreadyEnrolled):
+			return response.NewError(err, http.StatusConflict)
+		default:
+			return fmt.Errorf("mfa.enroll: userID[%s]: %w", usr.ID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, toAppMFAEnrollment(enr, h.auth.Issuer(), usr.Email.Address), http.StatusCreated)
+}
+
+// ConfirmMFA confirms the enrollment of the authenticated user with a code
+// from their app and returns the recovery codes.
+func (h *Handlers) ConfirmMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppMFAConfirm
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	userID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return auth.NewAuthError("invalid subject in claims")
+	}
+
+	codes, err := h.mfa.Confirm(ctx, userID, app.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrNotEnrolled), errors.Is(err, mfa.ErrAlreadyEnrolled):
+			return response.NewError(err, http.StatusConflict)
+		case errors.Is(err, mfa.ErrInvalidCode):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("mfa.confirm: userID[%s]: %w", userID, err)
+		}
+	}
+
+	return web.Respond(ctx, w, AppRecoveryCodes{RecoveryCodes: codes}, http.StatusOK)
+}
+
+// DisableMFA removes the second factor of a user of the tenant, for when the
+// user lost access to their app and recovery codes.
+func (h *Handlers) DisableMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID := auth.GetUserID(ctx)
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return response.NewError(err, http.StatusNotFound)
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", userID, err)
+		}
+	}
+
+	if err := h.mfa.Disable(ctx, usr.ID); err != nil {
+		return fmt.Errorf("mfa.disable: userID[%s]: %w", usr.ID, err)
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// RefreshToken exchanges a refresh token for a new API token and refresh
+// token. Presenting a refresh token that was already exchanged revokes every
+// refresh token issued from the same login.
+func (h *Handlers) RefreshToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var app AppRefreshToken
+	if err := web.Decode(r, &app); err != nil {
+		return response.NewError(err, http.StatusBadRequest)
+	}
+
+	refresh, rt, err := h.session.Rotate(ctx, app.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrInvalidToken), errors.Is(err, session.ErrTokenExpired), errors.Is(err, session.ErrTokenReused):
+			return auth.NewAuthError(err.Error())
+		default:
+			return fmt.Errorf("rotate: %w", err)
+		}
+	}
+
+	usr, err := h.user.QueryByID(ctx, rt.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return auth.NewAuthError(err.Error())
+		default:
+			return fmt.Errorf("querybyid: userID[%s]: %w", rt.UserID, err)
+		}
+	}
+
+	if !usr.Enabled {
+		return auth.NewAuthError("user disabled")
+	}
+
+	tkn, err := h.generateToken(ctx, usr, refresh, rt.AMR)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
+
+// RevokeToken revokes the API token used on the request, along with the
+// refresh token when one is provided, so neither can be used again.
+func (h *Handlers) RevokeToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var app AppRevokeToken
+	if r.ContentLength != 0 {
+		if err := web.Decode(r, &app); err != nil {
+			return response.NewError(err, http.StatusBadRequest)
+		}
+	}
+
+	claims := auth.GetClaims(ctx)
+
+	// Only the tokens carrying a jti can be revoked, which leaves out the
+	// claims of an api key.
+	if claims.ID == "" {
+		return response.NewError(errors.New("token can't be revoked"), http.StatusBadRequest)
+	}
+
+	if claims.ExpiresAt != nil {
+		if err := h.session.RevokeAccess(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return fmt.Errorf("revokeaccess: %w", err)
+		}
+	}
+
+	if app.RefreshToken != "" {
+		if err := h.session.Revoke(ctx, app.RefreshToken); err != nil {
+			switch {
+			case errors.Is(err, session.ErrInvalidToken):
+				return response.NewError(err, http.StatusBadRequest)
+			default:
+				return fmt.Errorf("revoke: %w", err)
+			}
+		}
+	}
+
+	return web.Respond(ctx, w, nil, http.StatusNoContent)
+}
+
+// =============================================================================
+
+// issueToken starts a new login for the user that authenticated with the amr
+// methods and responds with the API token and refresh token.
+func (h *Handlers) issueToken(ctx context.Context, w http.ResponseWriter, usr user.User, amr []string) error {
+	refresh, _, err := h.session.Create(ctx, usr.ID, usr.TenantID, amr)
+	if err != nil {
+		return fmt.Errorf("session.create: userID[%s]: %w", usr.ID, err)
+	}
+
+	tkn, err := h.generateToken(ctx, usr, refresh, amr)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, w, tkn, http.StatusOK)
+}
+
+// checkTenant validates a user can be created in the specified tenant. Users
+// are created in the tenant of the caller unless one is specified, which
+// requires the permission to manage tenants.
+func (h *Handlers) checkTenant(ctx context.Context, tenantID uuid.UUID) error {
+	current, _, err := tenant.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if tenantID == uuid.Nil || tenantID == current {
+		return nil
+	}
+
+	claims := auth.GetClaims(ctx)
+
+	if err := h.auth.AuthorizePermission(ctx, claims, auth.PermTenantWrite); err != nil {
+		return auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] permission[%v]: %s", claims.Permissions, auth.PermTenantWrite, err)
+	}
+
+	if _, err := h.tenant.QueryByID(ctx, tenantID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrNotFound):
+			return response.NewError(err, http.StatusBadRequest)
+		default:
+			return fmt.Errorf("tenant.querybyid: tenantID[%s]: %w", tenantID, err)
+		}
+	}
+
+	return nil
+}
+
+// generateToken constructs the signed API token for the user.
+func (h *Handlers) generateToken(ctx context.Context, usr user.User, refresh string, amr []string) (token, error) {
+	perms, err := h.auth.Permissions(ctx, usr.TenantID, usr.Roles)
+	if err != nil {
+		return token{}, fmt.Errorf("permissions: userID[%s]: %w", usr.ID, err)
+	}
+
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   usr.ID.String(),
-			Issuer:    "service project",
+			Issuer:    h.auth.Issuer(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: usr.Roles,
+		TenantID:      usr.TenantID.String(),
+		Roles:         usr.Roles,
+		Permissions:   perms,
+		AMR:           amr,
+		EmailVerified: usr.EmailVerified,
 	}
 
-	token, err := h.auth.GenerateToken(kid, claims)
+	tkn, err := h.auth.GenerateToken(claims)
 	if err != nil {
-		return fmt.Errorf("generatetoken: %w", err)
+		return token{}, fmt.Errorf("generatetoken: %w", err)
 	}
 
-	return web.Respond(ctx, w, toToken(token), http.StatusOK)
+	return toToken(tkn, refresh), nil
 }
@@ -2,16 +2,20 @@ package usersummarygrp
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
 
 func parseFilter(r *http.Request) (usersummary.QueryFilter, error) {
 	const (
-		filterByUserID = "user_id"
-		filterByName   = "name"
+		filterByUserID     = "user_id"
+		filterByName       = "name"
+		filterByTotalCount = "total_count"
+		filterByTotalCost  = "total_cost"
 	)
 
 	values := r.URL.Query()
@@ -30,5 +34,23 @@ func parseFilter(r *http.Request) (usersummary.QueryFilter, error) {
 		filter.WithUserName(userName)
 	}
 
+	userIDs, err := query.ParseList(values, filterByUserID, uuid.Parse)
+	if err != nil {
+		return usersummary.QueryFilter{}, err
+	}
+	filter.WithUserIDs(userIDs)
+
+	totalCount, err := query.ParseRange(values, filterByTotalCount, strconv.Atoi)
+	if err != nil {
+		return usersummary.QueryFilter{}, err
+	}
+	filter.WithTotalCountRange(totalCount)
+
+	totalCost, err := query.ParseRange(values, filterByTotalCost, query.ParseFloat)
+	if err != nil {
+		return usersummary.QueryFilter{}, err
+	}
+	filter.WithTotalCostRange(totalCost)
+
 	return filter, nil
 }
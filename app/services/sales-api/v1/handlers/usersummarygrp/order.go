@@ -1,12 +1,10 @@
 package usersummarygrp
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
-	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 )
 
 func parseOrder(r *http.Request) (order.By, error) {
@@ -25,11 +23,5 @@ func parseOrder(r *http.Request) (order.By, error) {
 		return order.By{}, err
 	}
 
-	if _, exists := orderByFields[orderBy.Field]; !exists {
-		return order.By{}, validate.NewFieldsError(orderBy.Field, errors.New("order field does not exist"))
-	}
-
-	orderBy.Field = orderByFields[orderBy.Field]
-
-	return orderBy, nil
+	return orderBy.Map(orderByFields)
 }
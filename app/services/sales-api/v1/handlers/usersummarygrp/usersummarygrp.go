@@ -3,12 +3,14 @@ package usersummarygrp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
 	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 )
 
@@ -41,6 +43,10 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return err
 	}
 
+	if page.Cursor != nil {
+		return h.queryByCursor(ctx, w, filter, page.Cursor.Ordered(orderBy), page.RowsPerPage)
+	}
+
 	smms, err := h.summary.Query(ctx, filter, orderBy, page.Number, page.RowsPerPage)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
@@ -53,3 +59,19 @@ func (h *Handlers) Query(ctx context.Context, w http.ResponseWriter, r *http.Req
 
 	return web.Respond(ctx, w, response.NewPageDocument(toAppUsersSummary(smms), total, page.Number, page.RowsPerPage), http.StatusOK)
 }
+
+// queryByCursor returns the page of user summary data past the cursor.
+func (h *Handlers) queryByCursor(ctx context.Context, w http.ResponseWriter, filter usersummary.QueryFilter, cur page.Cursor, rowsPerPage int) error {
+	smms, cursors, err := h.summary.QueryByCursor(ctx, filter, cur, rowsPerPage)
+	if err != nil {
+		if errors.Is(err, page.ErrCursorOrder) {
+			return response.NewError(err, http.StatusBadRequest)
+		}
+		if errors.Is(err, page.ErrInvalidCursor) {
+			return validate.NewFieldsError("cursor", err)
+		}
+		return fmt.Errorf("querybycursor: %w", err)
+	}
+
+	return web.Respond(ctx, w, response.NewCursorDocument(toAppUsersSummary(smms), cursors, rowsPerPage), http.StatusOK)
+}
@@ -0,0 +1,13 @@
+package wellknowngrp
+
+// AppDiscovery represents the OpenID style discovery document describing
+// where our keys live and how our tokens are signed.
+type AppDiscovery struct {
+	Issuer                 string   `json:"issuer"`
+	JWKSURI                string   `json:"jwks_uri"`
+	TokenEndpoint          string   `json:"token_endpoint"`
+	ResponseTypes          []string `json:"response_types_supported"`
+	SubjectTypes           []string `json:"subject_types_supported"`
+	SigningAlgorithms      []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthTypes []string `json:"token_endpoint_auth_methods_supported"`
+}
@@ -0,0 +1,26 @@
+package wellknowngrp
+
+import (
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+// Config contains all the mandatory systems required by handlers.
+type Config struct {
+	Log       *logger.Logger
+	Auth      *auth.Auth
+	PublicURL string
+}
+
+// Routes adds specific routes for this group. These routes are not versioned
+// since their location is defined by the standards they implement.
+func Routes(app *web.App, cfg Config) {
+	const version = ""
+
+	hdl := New(cfg.Auth, cfg.PublicURL)
+	app.Handle(http.MethodGet, version, "/.well-known/jwks.json", hdl.JWKS)
+	app.Handle(http.MethodGet, version, "/.well-known/openid-configuration", hdl.Discovery)
+}
@@ -0,0 +1,58 @@
+// Package wellknowngrp maintains the group of handlers that publish the
+// public keys and metadata needed by other services to verify our tokens.
+package wellknowngrp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+// Handlers manages the set of well known endpoints.
+type Handlers struct {
+	auth      *auth.Auth
+	publicURL string
+}
+
+// New constructs a handlers for route access. The public url is the scheme
+// and host the service is reached at, used to build the endpoints published
+// in the discovery document.
+func New(auth *auth.Auth, publicURL string) *Handlers {
+	return &Handlers{
+		auth:      auth,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// JWKS returns the set of public keys that can verify our tokens.
+func (h *Handlers) JWKS(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	jwks, err := h.auth.JWKS()
+	if err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	return web.Respond(ctx, w, jwks, http.StatusOK)
+}
+
+// Discovery returns the OpenID style discovery document.
+func (h *Handlers) Discovery(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	doc := AppDiscovery{
+		Issuer:                 h.auth.Issuer(),
+		JWKSURI:                h.publicURL + "/.well-known/jwks.json",
+		TokenEndpoint:          h.publicURL + "/v1/users/token",
+		ResponseTypes:          []string{"token"},
+		SubjectTypes:           []string{"public"},
+		SigningAlgorithms:      h.auth.SigningAlgorithms(),
+		TokenEndpointAuthTypes: []string{"client_secret_basic"},
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	return web.Respond(ctx, w, doc, http.StatusOK)
+}
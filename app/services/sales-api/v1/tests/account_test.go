@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account/stores/accountdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/foundation/mailer"
+)
+
+// AccountTests holds methods for each account subtest. This type allows
+// passing dependencies for tests while still providing a convenient syntax
+// when subtests are registered.
+type AccountTests struct {
+	app       http.Handler
+	events    *event.Core
+	mailer    *mailer.Memory
+	userToken string
+}
+
+// Test_Account is the entry point for testing the password reset and email
+// verification apis.
+func Test_Account(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	// The reset emails are sent by the relay, which needs an account core to
+	// handle the events.
+	api := test.CoreAPIs
+	evnCore := event.NewCore(test.Log, eventdb.NewStore(test.Log, test.DB))
+	account.NewCore(test.Log, evnCore, api.User, api.Session, api.APIKey, test.Mailer, accountdb.NewStore(test.Log, test.DB), account.WithLinks("https://example.com/reset", "https://example.com/verify"))
+
+	shutdown := make(chan os.Signal, 1)
+	tests := AccountTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown:  shutdown,
+			Log:       test.Log,
+			Auth:      test.V1.Auth,
+			DB:        test.DB,
+			Mailer:    test.Mailer,
+			ResetURL:  "https://example.com/reset",
+			VerifyURL: "https://example.com/verify",
+		}, all.Routes()),
+		events:    evnCore,
+		mailer:    test.Mailer,
+		userToken: test.TokenV1("user@example.com", "gophers"),
+	}
+
+	t.Run("resetPassword", tests.resetPassword())
+	t.Run("verifyEmail", tests.verifyEmail())
+}
+
+func (at *AccountTests) send(method string, url string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	at.app.ServeHTTP(w, r)
+
+	return w
+}
+
+// deliver delivers the pending events so their emails are sent.
+func (at *AccountTests) deliver(t *testing.T) {
+	if _, err := at.events.ProcessPending(context.Background(), event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver the events : %s", err)
+	}
+}
+
+// token returns the token from the link in the last email sent to the address.
+func (at *AccountTests) token(t *testing.T, to string) string {
+	msgs := at.mailer.Messages(to)
+	if len(msgs) == 0 {
+		t.Fatalf("Should have sent an email to %s", to)
+	}
+
+	link := regexp.MustCompile(`https://example\.com/\S+`).FindString(msgs[len(msgs)-1].Body)
+
+	u, err := url.Parse(link)
+	if err != nil || u.Query().Get("token") == "" {
+		t.Fatalf("Should get a link with the token in the email : %s", msgs[len(msgs)-1].Body)
+	}
+
+	return u.Query().Get("token")
+}
+
+func (at *AccountTests) resetPassword() func(t *testing.T) {
+	return func(t *testing.T) {
+		w := at.send(http.MethodPost, "/v1/users/password/forgot", `{"email": "nobody@example.com"}`, "")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for an unknown email : %d", w.Code)
+		}
+
+		w = at.send(http.MethodPost, "/v1/users/password/forgot", `{"email": "user@example.com"}`, "")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the request : %d", w.Code)
+		}
+
+		at.deliver(t)
+
+		if msgs := at.mailer.Messages("nobody@example.com"); len(msgs) != 0 {
+			t.Fatalf("Should NOT send an email for an unknown email : %+v", msgs)
+		}
+
+		token := at.token(t, "user@example.com")
+
+		body := fmt.Sprintf(`{"token": %q, "password": "new-gophers", "passwordConfirm": "other"}`, token)
+		if w := at.send(http.MethodPost, "/v1/users/password/reset", body, ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 when the passwords don't match : %d", w.Code)
+		}
+
+		body = fmt.Sprintf(`{"token": %q, "password": "new-gophers", "passwordConfirm": "new-gophers"}`, token)
+		if w := at.send(http.MethodPost, "/v1/users/password/reset", body, ""); w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the reset : %d", w.Code)
+		}
+
+		if w := at.send(http.MethodPost, "/v1/users/password/reset", body, ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should NOT be able to use a reset token twice : %d", w.Code)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/users/token", nil)
+		w = httptest.NewRecorder()
+
+		r.SetBasicAuth("user@example.com", "new-gophers")
+		at.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should be able to login with the new password : %d", w.Code)
+		}
+	}
+}
+
+func (at *AccountTests) verifyEmail() func(t *testing.T) {
+	return func(t *testing.T) {
+		if w := at.send(http.MethodPost, "/v1/users/email/verification", "", ""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to request a verification without a token : %d", w.Code)
+		}
+
+		if w := at.send(http.MethodPost, "/v1/users/email/verification", "", at.userToken); w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the request : %d", w.Code)
+		}
+
+		body := fmt.Sprintf(`{"token": %q}`, at.token(t, "user@example.com"))
+		if w := at.send(http.MethodPost, "/v1/users/email/verify", body, ""); w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the verification : %d", w.Code)
+		}
+
+		if w := at.send(http.MethodPost, "/v1/users/email/verification", "", at.userToken); w.Code != http.StatusConflict {
+			t.Fatalf("Should receive a status code of 409 for a verified email : %d", w.Code)
+		}
+	}
+}
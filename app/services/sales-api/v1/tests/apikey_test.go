@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/apikeygrp"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+)
+
+// APIKeyTests holds methods for each api key subtest. This type allows
+// passing dependencies for tests while still providing a convenient syntax
+// when subtests are registered.
+type APIKeyTests struct {
+	app        http.Handler
+	userToken  string
+	adminToken string
+}
+
+// Test_APIKeys is the entry point for testing api key management apis.
+func Test_APIKeys(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := APIKeyTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	t.Run("postAPIKey403", tests.postAPIKey403())
+	t.Run("getUserWithAPIKey401", tests.getUserWithAPIKey401())
+	t.Run("crudAPIKey", tests.crudAPIKey())
+}
+
+func (at *APIKeyTests) postAPIKey403() func(t *testing.T) {
+	return func(t *testing.T) {
+		body := `{"name": "Escalation", "roles": ["ADMIN"]}`
+
+		r := httptest.NewRequest(http.MethodPost, "/v1/apikeys", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+at.userToken)
+		at.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("Should receive a status code of 403 for a role the user doesn't have : %d", w.Code)
+		}
+	}
+}
+
+func (at *APIKeyTests) getUserWithAPIKey401() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/users/45b5fbd3-755f-4379-8f07-a58d4a30fa2f", nil)
+		w := httptest.NewRecorder()
+
+		r.Header.Set("X-API-Key", "sk_000000000000_unknown")
+		at.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 for an unknown api key : %d", w.Code)
+		}
+	}
+}
+
+func (at *APIKeyTests) crudAPIKey() func(t *testing.T) {
+	return func(t *testing.T) {
+		send := func(method string, url string, body string, header string, value string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(method, url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			r.Header.Set(header, value)
+			at.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		body := `{"name": "Integration", "roles": ["USER"]}`
+
+		w := send(http.MethodPost, "/v1/apikeys", body, "Authorization", "Bearer "+at.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for the response : %d", w.Code)
+		}
+
+		var created apikeygrp.AppCreatedAPIKey
+		if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if created.Key == "" || len(created.APIKey.Roles) != 1 || created.APIKey.Roles[0] != "USER" {
+			t.Fatalf("Should get back the raw key and its roles : %+v", created)
+		}
+
+		// ---------------------------------------------------------------------
+
+		w = send(http.MethodGet, "/v1/users/5cf37266-3473-4006-984f-9325122678b7", "", "X-API-Key", created.Key)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should be able to read the owner with the api key : %d", w.Code)
+		}
+
+		w = send(http.MethodGet, "/v1/users", "", "X-API-Key", created.Key)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to use admin routes with a USER api key : %d", w.Code)
+		}
+
+		w = send(http.MethodPost, "/v1/apikeys", body, "X-API-Key", created.Key)
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("Should NOT be able to create an api key with an api key : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+
+		w = send(http.MethodGet, "/v1/apikeys", "", "Authorization", "Bearer "+at.adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the list : %d", w.Code)
+		}
+
+		var keys []apikeygrp.AppAPIKey
+		if err := json.NewDecoder(w.Body).Decode(&keys); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if len(keys) != 1 || keys[0].ID != created.APIKey.ID || keys[0].DateLastUsed == "" {
+			t.Fatalf("Should get back the key with its last used date : %+v", keys)
+		}
+
+		// ---------------------------------------------------------------------
+
+		w = send(http.MethodDelete, "/v1/apikeys/"+created.APIKey.ID, "", "Authorization", "Bearer "+at.userToken)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to revoke the key of another user : %d", w.Code)
+		}
+
+		w = send(http.MethodDelete, "/v1/apikeys/"+created.APIKey.ID, "", "Authorization", "Bearer "+at.adminToken)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the revoke : %d", w.Code)
+		}
+
+		w = send(http.MethodGet, "/v1/users/5cf37266-3473-4006-984f-9325122678b7", "", "X-API-Key", created.Key)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to use a revoked api key : %d", w.Code)
+		}
+	}
+}
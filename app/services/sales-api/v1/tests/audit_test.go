@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/auditgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/productgrp"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+)
+
+// AuditTests holds methods for each audit subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type AuditTests struct {
+	app        http.Handler
+	userToken  string
+	adminToken string
+}
+
+// Test_Audit is the entry point for testing the audit log.
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := AuditTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	t.Run("mutations", tests.mutations())
+	t.Run("permission", tests.permission())
+}
+
+func (at *AuditTests) send(method string, url string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+token)
+	at.app.ServeHTTP(w, r)
+
+	return w
+}
+
+func (at *AuditTests) mutations() func(t *testing.T) {
+	return func(t *testing.T) {
+		const adminID = "5cf37266-3473-4006-984f-9325122678b7"
+
+		body := `{"name": "Comic Books", "cost": 10, "quantity": 5, "userID": "` + adminID + `"}`
+
+		w := at.send(http.MethodPost, "/v1/products", body, at.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 creating a product : %d : %s", w.Code, w.Body)
+		}
+
+		var prd productgrp.AppProduct
+		if err := json.NewDecoder(w.Body).Decode(&prd); err != nil {
+			t.Fatalf("Should be able to unmarshal the product : %s", err)
+		}
+
+		w = at.send(http.MethodPut, "/v1/products/"+prd.ID, `{"quantity": 2}`, at.adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 updating the product : %d : %s", w.Code, w.Body)
+		}
+
+		w = at.send(http.MethodGet, "/v1/audit?entity=product&entity_id="+prd.ID+"&orderBy=date_created,ASC", "", at.adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 querying the audit log : %d : %s", w.Code, w.Body)
+		}
+
+		var doc response.PageDocument[auditgrp.AppEntry]
+		if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+			t.Fatalf("Should be able to unmarshal the audit log : %s", err)
+		}
+
+		if doc.Total != 2 || len(doc.Items) != 2 {
+			t.Fatalf("Should get an entry for every mutation of the product : %+v", doc)
+		}
+
+		upd := doc.Items[1]
+
+		if upd.Action != "update" || upd.Actor != adminID {
+			t.Fatalf("Should record the action and the actor : %+v", upd)
+		}
+
+		if upd.TraceID == "" || upd.TraceID == "00000000-0000-0000-0000-000000000000" {
+			t.Fatalf("Should record the trace id of the request : %q", upd.TraceID)
+		}
+
+		chg, exists := upd.Diff["quantity"]
+		if !exists || len(upd.Diff) != 1 {
+			t.Fatalf("Should only record the changed fields : %+v", upd.Diff)
+		}
+
+		if chg.Before != float64(5) || chg.After != float64(2) {
+			t.Fatalf("Should record the values before and after : %+v", chg)
+		}
+
+		if w := at.send(http.MethodGet, "/v1/audit?action=unknown", "", at.adminToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for an unknown action : %d", w.Code)
+		}
+	}
+}
+
+func (at *AuditTests) permission() func(t *testing.T) {
+	return func(t *testing.T) {
+		if w := at.send(http.MethodGet, "/v1/audit", "", at.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 querying the audit log without the permission : %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/foundation/totp"
+)
+
+// MFATests holds methods for each mfa subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type MFATests struct {
+	app        http.Handler
+	auth       *auth.Auth
+	userToken  string
+	adminToken string
+}
+
+// Test_MFA is the entry point for testing the second factor of users.
+func Test_MFA(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := MFATests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		auth:       test.V1.Auth,
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	t.Run("mfaLogin", tests.mfaLogin())
+	t.Run("disableMFA401", tests.disableMFA401())
+}
+
+func (mt *MFATests) send(method string, url string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	mt.app.ServeHTTP(w, r)
+
+	return w
+}
+
+func (mt *MFATests) mfaLogin() func(t *testing.T) {
+	return func(t *testing.T) {
+		w := mt.send(http.MethodPost, "/v1/users/mfa/enroll", "", mt.userToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for the enrollment : %d", w.Code)
+		}
+
+		var enr usergrp.AppMFAEnrollment
+		if err := json.NewDecoder(w.Body).Decode(&enr); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if enr.Secret == "" || !strings.HasPrefix(enr.URI, "otpauth://totp/") {
+			t.Fatalf("Should get back the secret and the provisioning uri : %+v", enr)
+		}
+
+		// Confirm with the code of the previous step so the current step is
+		// still available for the login.
+		code, err := totp.Code(enr.Secret, totp.Step(time.Now())-1)
+		if err != nil {
+			t.Fatalf("Should be able to generate a code : %s", err)
+		}
+
+		w = mt.send(http.MethodPost, "/v1/users/mfa/confirm", fmt.Sprintf(`{"code": %q}`, code), mt.userToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the confirmation : %d", w.Code)
+		}
+
+		var rc usergrp.AppRecoveryCodes
+		if err := json.NewDecoder(w.Body).Decode(&rc); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if len(rc.RecoveryCodes) == 0 {
+			t.Fatalf("Should get back the recovery codes")
+		}
+
+		// ---------------------------------------------------------------------
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/users/token", nil)
+		w = httptest.NewRecorder()
+
+		r.SetBasicAuth("user@example.com", "gophers")
+		mt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the challenge : %d", w.Code)
+		}
+
+		var ch usergrp.AppMFAChallenge
+		if err := json.NewDecoder(w.Body).Decode(&ch); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if !ch.MFARequired || ch.MFAToken == "" {
+			t.Fatalf("Should get a challenge instead of a token : %+v", ch)
+		}
+
+		body := fmt.Sprintf(`{"mfaToken": %q, "code": "000000"}`, ch.MFAToken)
+		if w := mt.send(http.MethodPost, "/v1/users/token/mfa", body, ""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to complete the login with a wrong code : %d", w.Code)
+		}
+
+		code, err = totp.Code(enr.Secret, totp.Step(time.Now()))
+		if err != nil {
+			t.Fatalf("Should be able to generate a code : %s", err)
+		}
+
+		body = fmt.Sprintf(`{"mfaToken": %q, "code": %q}`, ch.MFAToken, code)
+		w = mt.send(http.MethodPost, "/v1/users/token/mfa", body, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the login : %d", w.Code)
+		}
+
+		var got struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		claims, err := mt.auth.Authenticate(context.Background(), "Bearer "+got.Token)
+		if err != nil {
+			t.Fatalf("Should be able to authenticate the token : %s", err)
+		}
+
+		if !slices.Contains(claims.AMR, auth.AMRMFA) {
+			t.Fatalf("Should get the mfa amr in the token : %v", claims.AMR)
+		}
+	}
+}
+
+func (mt *MFATests) disableMFA401() func(t *testing.T) {
+	return func(t *testing.T) {
+		url := "/v1/users/45b5fbd3-755f-4379-8f07-a58d4a30fa2f/mfa"
+
+		if w := mt.send(http.MethodDelete, url, "", mt.adminToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to disable mfa with an admin token without mfa : %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc/oidctest"
+)
+
+const oidcRedirectURL = "http://localhost:3000/v1/oidc/callback"
+
+// OIDCTests holds methods for each oidc subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type OIDCTests struct {
+	app  http.Handler
+	idp  *oidctest.Server
+	auth *auth.Auth
+}
+
+// Test_OIDC is the entry point for testing the login through an OpenID
+// Connect provider.
+func Test_OIDC(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	idp, err := oidctest.NewServer("sales")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider : %s", err)
+	}
+	defer idp.Close()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := OIDCTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+			OIDC: oidc.New(oidc.Config{
+				IssuerURL:   idp.URL,
+				ClientID:    "sales",
+				RedirectURL: oidcRedirectURL,
+			}),
+		}, all.Routes()),
+		idp:  idp,
+		auth: test.V1.Auth,
+	}
+
+	t.Run("provision", tests.provision())
+	t.Run("link", tests.link())
+	t.Run("state", tests.state())
+}
+
+// login runs the login against the app and the provider and returns the
+// response of the callback.
+func (ot *OIDCTests) login(t *testing.T) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/v1/oidc/login", nil)
+	w := httptest.NewRecorder()
+	ot.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("Should receive a status code of 302 for the login : %d", w.Code)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 || cookies[0].Name != "oidc_state" {
+		t.Fatalf("Should receive the state cookie : %v", cookies)
+	}
+
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Should be able to call the provider : %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Should be sent back by the provider : %d", resp.StatusCode)
+	}
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Should be able to parse the callback : %s", err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	r.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	ot.app.ServeHTTP(w, r)
+
+	return w
+}
+
+func (ot *OIDCTests) claims(t *testing.T, w *httptest.ResponseRecorder) auth.Claims {
+	var tkn struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &tkn); err != nil {
+		t.Fatalf("Should be able to unmarshal the response : %s", err)
+	}
+
+	if tkn.RefreshToken == "" {
+		t.Fatalf("Should receive a refresh token")
+	}
+
+	claims, err := ot.auth.Authenticate(context.Background(), "Bearer "+tkn.Token)
+	if err != nil {
+		t.Fatalf("Should receive a valid token : %s", err)
+	}
+
+	return claims
+}
+
+func (ot *OIDCTests) provision() func(t *testing.T) {
+	return func(t *testing.T) {
+		ot.idp.SetUser(oidctest.User{
+			Subject:       "subject-1",
+			Email:         "jane@example.com",
+			EmailVerified: true,
+			Name:          "Jane Doe",
+		})
+
+		w := ot.login(t)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the callback : %d : %s", w.Code, w.Body)
+		}
+
+		claims := ot.claims(t, w)
+
+		if len(claims.Roles) != 1 || claims.Roles[0] != user.RoleUser {
+			t.Fatalf("Should get the default role : %v", claims.Roles)
+		}
+
+		if len(claims.AMR) != 1 || claims.AMR[0] != auth.AMRFederated {
+			t.Fatalf("Should get the federated amr : %v", claims.AMR)
+		}
+
+		if !claims.EmailVerified {
+			t.Fatalf("Should get the email verified by the provider")
+		}
+
+		w = ot.login(t)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the second login : %d : %s", w.Code, w.Body)
+		}
+
+		if got := ot.claims(t, w); got.Subject != claims.Subject {
+			t.Fatalf("Should get the same user for the subject : got %s, exp %s", got.Subject, claims.Subject)
+		}
+	}
+}
+
+func (ot *OIDCTests) link() func(t *testing.T) {
+	return func(t *testing.T) {
+		ot.idp.SetUser(oidctest.User{
+			Subject: "subject-2",
+			Email:   "admin@example.com",
+		})
+
+		if w := ot.login(t); w.Code != http.StatusConflict {
+			t.Fatalf("Should NOT link a user through an unverified email : %d", w.Code)
+		}
+
+		ot.idp.SetUser(oidctest.User{
+			Subject:       "subject-2",
+			Email:         "admin@example.com",
+			EmailVerified: true,
+			AMR:           []string{"pwd", "mfa"},
+		})
+
+		w := ot.login(t)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the callback : %d : %s", w.Code, w.Body)
+		}
+
+		claims := ot.claims(t, w)
+
+		if claims.Subject != "5cf37266-3473-4006-984f-9325122678b7" {
+			t.Fatalf("Should get the existing admin : %s", claims.Subject)
+		}
+
+		if len(claims.AMR) != 2 || claims.AMR[1] != auth.AMRMFA {
+			t.Fatalf("Should keep the mfa asserted by the provider : %v", claims.AMR)
+		}
+	}
+}
+
+func (ot *OIDCTests) state() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/oidc/login", nil)
+		w := httptest.NewRecorder()
+		ot.app.ServeHTTP(w, r)
+
+		loc, err := url.Parse(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Should be able to parse the redirect : %s", err)
+		}
+
+		q := loc.Query()
+		if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" || q.Get("nonce") == "" {
+			t.Fatalf("Should send a PKCE challenge and nonce to the provider : %s", loc)
+		}
+
+		// A callback without the cookie of the browser that started the login
+		// is rejected.
+		r = httptest.NewRequest(http.MethodGet, "/v1/oidc/callback?code=x&state="+url.QueryEscape(q.Get("state")), nil)
+		w = httptest.NewRecorder()
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the state cookie : %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/ordergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/google/uuid"
+)
+
+// OrderTests holds methods for each order subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type OrderTests struct {
+	app        http.Handler
+	userToken  string
+	adminToken string
+}
+
+// Test_Orders is the entry point for testing order apis.
+func Test_Orders(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	shutdown := make(chan os.Signal, 1)
+	tests := OrderTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	// -------------------------------------------------------------------------
+
+	t.Log("Go seeding ...")
+
+	np := product.NewProduct{
+		Name:     "Comic Books",
+		Cost:     25,
+		Quantity: 10,
+		UserID:   uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7"),
+	}
+
+	prd, err := api.Product.Create(tenant.Unscoped(context.Background()), np)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	t.Run("postOrder400", tests.postOrder400())
+	t.Run("postOrder401", tests.postOrder401())
+	t.Run("postOrder409", tests.postOrder409(prd.ID))
+	t.Run("getOrder404", tests.getOrder404())
+	t.Run("getOrders401", tests.getOrders401())
+	t.Run("crudOrder", tests.crudOrder(prd.ID))
+}
+
+func (ot *OrderTests) postOrder400() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(`{"items": []}`))
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ot.userToken)
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for the response : %d", w.Code)
+		}
+
+		var got response.ErrorDocument
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response to an error type : %s", err)
+		}
+
+		if got.Error != "data validation error" {
+			t.Fatalf("Should get a data validation error : %q", got.Error)
+		}
+	}
+}
+
+func (ot *OrderTests) postOrder401() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 for the response : %d", w.Code)
+		}
+	}
+}
+
+func (ot *OrderTests) postOrder409(productID uuid.UUID) func(t *testing.T) {
+	return func(t *testing.T) {
+		body := fmt.Sprintf(`{"items": [{"productID": %q, "quantity": 1000}]}`, productID)
+
+		r := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ot.userToken)
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Should receive a status code of 409 for the response : %d", w.Code)
+		}
+	}
+}
+
+func (ot *OrderTests) getOrder404() func(t *testing.T) {
+	return func(t *testing.T) {
+		url := fmt.Sprintf("/v1/orders/%s", uuid.NewString())
+
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ot.adminToken)
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for the response : %d", w.Code)
+		}
+	}
+}
+
+func (ot *OrderTests) getOrders401() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/orders", nil)
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ot.userToken)
+		ot.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 for the response : %d", w.Code)
+		}
+	}
+}
+
+func (ot *OrderTests) crudOrder(productID uuid.UUID) func(t *testing.T) {
+	return func(t *testing.T) {
+		ord := ot.postOrder201(t, productID)
+
+		ot.getOrder200(t, ord.ID)
+		ot.cancelOrder200(t, ord.ID)
+		ot.cancelOrder409(t, ord.ID)
+	}
+}
+
+// postOrder201 validates an order can be placed with the endpoint.
+func (ot *OrderTests) postOrder201(t *testing.T, productID uuid.UUID) ordergrp.AppOrder {
+	body := fmt.Sprintf(`{"items": [{"productID": %q, "quantity": 2}]}`, productID)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/orders", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+ot.userToken)
+	ot.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Should receive a status code of 201 for the response : %d", w.Code)
+	}
+
+	var got ordergrp.AppOrder
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Should be able to unmarshal the response : %s", err)
+	}
+
+	if got.UserID != "45b5fbd3-755f-4379-8f07-a58d4a30fa2f" {
+		t.Fatalf("Should place the order for the caller : got %s", got.UserID)
+	}
+
+	if got.Total != 50 {
+		t.Fatalf("Should get back the right total : got %v want %v", got.Total, 50)
+	}
+
+	return got
+}
+
+// getOrder200 validates the owner can retrieve their order.
+func (ot *OrderTests) getOrder200(t *testing.T, id string) {
+	url := fmt.Sprintf("/v1/orders/%s", id)
+
+	r := httptest.NewRequest(http.MethodGet, url, nil)
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+ot.userToken)
+	ot.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Should receive a status code of 200 for the response : %d", w.Code)
+	}
+
+	var got ordergrp.AppOrder
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Should be able to unmarshal the response : %s", err)
+	}
+
+	if got.ID != id || len(got.Items) != 1 {
+		t.Fatalf("Should get back the placed order : %+v", got)
+	}
+}
+
+// cancelOrder200 validates the owner can cancel their order.
+func (ot *OrderTests) cancelOrder200(t *testing.T, id string) {
+	url := fmt.Sprintf("/v1/orders/%s/cancel", id)
+
+	r := httptest.NewRequest(http.MethodPost, url, nil)
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+ot.userToken)
+	ot.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Should receive a status code of 200 for the response : %d", w.Code)
+	}
+
+	var got ordergrp.AppOrder
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Should be able to unmarshal the response : %s", err)
+	}
+
+	if got.Status != "CANCELLED" {
+		t.Fatalf("Should get back a cancelled order : got %s", got.Status)
+	}
+}
+
+// cancelOrder409 validates an order can't be cancelled twice.
+func (ot *OrderTests) cancelOrder409(t *testing.T, id string) {
+	url := fmt.Sprintf("/v1/orders/%s/cancel", id)
+
+	r := httptest.NewRequest(http.MethodPost, url, nil)
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+ot.adminToken)
+	ot.app.ServeHTTP(w, r)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Should receive a status code of 409 for the response : %d", w.Code)
+	}
+}
@@ -15,9 +15,11 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/productgrp"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
@@ -30,8 +32,9 @@ import (
 // passing dependencies for tests while still providing a convenient syntax
 // when subtests are registered.
 type ProductTests struct {
-	app       http.Handler
-	userToken string
+	app        http.Handler
+	userToken  string
+	adminToken string
 }
 
 // Test_Products is the entry point for testing product apis.
@@ -57,7 +60,8 @@ func Test_Products(t *testing.T) {
 			Auth:     test.V1.Auth,
 			DB:       test.DB,
 		}, all.Routes()),
-		userToken: test.TokenV1("admin@example.com", "gophers"),
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
 	}
 
 	// -------------------------------------------------------------------------
@@ -87,7 +91,7 @@ func Test_Products(t *testing.T) {
 
 	t.Log("Go seeding ...")
 
-	prds, err := seed(context.Background(), api.User, api.Product)
+	prds, err := seed(tenant.Unscoped(context.Background()), api.User, api.Product)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
@@ -102,6 +106,13 @@ func Test_Products(t *testing.T) {
 	t.Run("putProduct404", tests.putProduct404())
 	t.Run("crudProducts", tests.crudProduct())
 	t.Run("getProducts200", tests.getProducts200(prds))
+	t.Run("getProductsCursor", tests.getProductsCursor())
+	t.Run("getProductsFilter", tests.getProductsFilter(prds))
+	t.Run("crossUser", tests.crossUser(prds))
+	t.Run("ifMatch", tests.ifMatch(prds))
+	t.Run("ifNoneMatch", tests.ifNoneMatch())
+	t.Run("batch", tests.batch())
+	t.Run("getProductsExport", tests.getProductsExport())
 }
 
 func (pt *ProductTests) postProduct400() func(t *testing.T) {
@@ -111,7 +122,7 @@ func (pt *ProductTests) postProduct400() func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{}`))
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusBadRequest {
@@ -179,7 +190,7 @@ func (pt *ProductTests) getProduct400() func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, url, nil)
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusBadRequest {
@@ -203,7 +214,7 @@ func (pt *ProductTests) getProduct404() func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodGet, url, nil)
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusNotFound {
@@ -227,7 +238,7 @@ func (pt *ProductTests) deleteProductNotFound() func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodDelete, url, nil)
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusNoContent {
@@ -251,7 +262,7 @@ func (pt *ProductTests) putProduct404() func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusNotFound {
@@ -276,7 +287,7 @@ func (pt *ProductTests) getProducts200(prds []product.Product) func(t *testing.T
 		r := httptest.NewRequest(http.MethodGet, url, nil)
 		w := httptest.NewRecorder()
 
-		r.Header.Set("Authorization", "Bearer "+pt.userToken)
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 		pt.app.ServeHTTP(w, r)
 
 		if w.Code != http.StatusOK {
@@ -345,7 +356,7 @@ func (pt *ProductTests) postProduct201(t *testing.T) productgrp.AppProduct {
 	r := httptest.NewRequest(http.MethodPost, "/v1/products", bytes.NewBuffer(body))
 	w := httptest.NewRecorder()
 
-	r.Header.Set("Authorization", "Bearer "+pt.userToken)
+	r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 	pt.app.ServeHTTP(w, r)
 
 	if w.Code != http.StatusCreated {
@@ -376,7 +387,7 @@ func (pt *ProductTests) deleteProduct204(t *testing.T, id string) {
 	r := httptest.NewRequest(http.MethodDelete, url, nil)
 	w := httptest.NewRecorder()
 
-	r.Header.Set("Authorization", "Bearer "+pt.userToken)
+	r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 	pt.app.ServeHTTP(w, r)
 
 	if w.Code != http.StatusNoContent {
@@ -390,7 +401,7 @@ func (pt *ProductTests) getProduct200(t *testing.T, id string) {
 	r := httptest.NewRequest(http.MethodGet, url, nil)
 	w := httptest.NewRecorder()
 
-	r.Header.Set("Authorization", "Bearer "+pt.userToken)
+	r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 	pt.app.ServeHTTP(w, r)
 
 	if w.Code != http.StatusOK {
@@ -422,7 +433,7 @@ func (pt *ProductTests) putProduct200(t *testing.T, id string) {
 	r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(body))
 	w := httptest.NewRecorder()
 
-	r.Header.Set("Authorization", "Bearer "+pt.userToken)
+	r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 	pt.app.ServeHTTP(w, r)
 
 	if w.Code != http.StatusOK {
@@ -432,7 +443,7 @@ func (pt *ProductTests) putProduct200(t *testing.T, id string) {
 	r = httptest.NewRequest(http.MethodGet, "/v1/products/"+id, nil)
 	w = httptest.NewRecorder()
 
-	r.Header.Set("Authorization", "Bearer "+pt.userToken)
+	r.Header.Set("Authorization", "Bearer "+pt.adminToken)
 	pt.app.ServeHTTP(w, r)
 
 	if w.Code != http.StatusOK {
@@ -448,3 +459,496 @@ func (pt *ProductTests) putProduct200(t *testing.T, id string) {
 		t.Fatalf("Should see an updated Name : got %q want %q", ru.Name, "Graphic Novels")
 	}
 }
+
+// crossUser validates a user can only change the products they own while an
+// admin can change any product.
+func (pt *ProductTests) crossUser(prds []product.Product) func(t *testing.T) {
+	return func(t *testing.T) {
+		adminID := uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7")
+		userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+		var adminPrd, userPrd product.Product
+		for _, prd := range prds {
+			switch prd.UserID {
+			case adminID:
+				adminPrd = prd
+			case userID:
+				userPrd = prd
+			}
+		}
+
+		if adminPrd.ID == uuid.Nil || userPrd.ID == uuid.Nil {
+			t.Fatalf("Should have seeded products for the admin and the user")
+		}
+
+		send := func(method string, url string, body string, token string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(method, url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+token)
+			pt.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		// ---------------------------------------------------------------------
+
+		url := fmt.Sprintf("/v1/products/%s", adminPrd.ID)
+
+		if w := send(http.MethodPut, url, `{"name": "Stolen"}`, pt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 updating a product of another user : %d", w.Code)
+		}
+
+		if w := send(http.MethodDelete, url, "", pt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 deleting a product of another user : %d", w.Code)
+		}
+
+		if w := send(http.MethodGet, url, "", pt.adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Should still find the product of the admin : %d", w.Code)
+		}
+
+		body := fmt.Sprintf(`{"name": "Stolen", "cost": 10, "quantity": 1, "userID": %q}`, adminID)
+
+		if w := send(http.MethodPost, "/v1/products", body, pt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 creating a product for another user : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+
+		url = fmt.Sprintf("/v1/products/%s", userPrd.ID)
+
+		if w := send(http.MethodPut, url, `{"name": "Owned"}`, pt.userToken); w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 updating an owned product : %d", w.Code)
+		}
+
+		if w := send(http.MethodPut, url, `{"name": "Moderated"}`, pt.adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for an admin updating any product : %d", w.Code)
+		}
+	}
+}
+
+func (pt *ProductTests) ifMatch(prds []product.Product) func(t *testing.T) {
+	return func(t *testing.T) {
+		send := func(method string, url string, body string, etag string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(method, url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+			if etag != "" {
+				r.Header.Set("If-Match", etag)
+			}
+			pt.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		url := fmt.Sprintf("/v1/products/%s", prds[0].ID)
+
+		w := send(http.MethodGet, url, "", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the product : %d", w.Code)
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("Should receive an ETag for the product")
+		}
+
+		if w := send(http.MethodPut, url, `{"name": "Comics"}`, `"0"`); w.Code != http.StatusPreconditionFailed {
+			t.Fatalf("Should receive a status code of 412 updating with another ETag : %d", w.Code)
+		}
+
+		w = send(http.MethodPut, url, `{"name": "Comics"}`, etag)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 updating with the current ETag : %d : %s", w.Code, w.Body)
+		}
+
+		next := w.Header().Get("ETag")
+		if next == "" || next == etag {
+			t.Fatalf("Should receive a new ETag after the update : got %q, previous %q", next, etag)
+		}
+
+		if w := send(http.MethodPut, url, `{"name": "Novels"}`, etag); w.Code != http.StatusPreconditionFailed {
+			t.Fatalf("Should receive a status code of 412 updating with a stale ETag : %d", w.Code)
+		}
+
+		if w := send(http.MethodDelete, url, "", etag); w.Code != http.StatusPreconditionFailed {
+			t.Fatalf("Should receive a status code of 412 deleting with a stale ETag : %d", w.Code)
+		}
+
+		if w := send(http.MethodDelete, url, "", next); w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 deleting with the current ETag : %d", w.Code)
+		}
+	}
+}
+
+func (pt *ProductTests) ifNoneMatch() func(t *testing.T) {
+	return func(t *testing.T) {
+		send := func(url string, etag string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+			if etag != "" {
+				r.Header.Set("If-None-Match", etag)
+			}
+			pt.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		const url = "/v1/products?page=1&rows=10"
+
+		w := send(url, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the products : %d", w.Code)
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("Should receive an ETag for the products")
+		}
+
+		if cc := w.Header().Get("Cache-Control"); cc != response.CacheRevalidate {
+			t.Fatalf("Should receive the Cache-Control of the products : %q", cc)
+		}
+
+		w = send(url, etag)
+		if w.Code != http.StatusNotModified {
+			t.Fatalf("Should receive a status code of 304 for unchanged products : %d", w.Code)
+		}
+
+		if w.Body.Len() != 0 {
+			t.Fatalf("Should NOT receive a body for unchanged products : %s", w.Body)
+		}
+
+		if w := send(url, `"stale"`); w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for another ETag : %d", w.Code)
+		}
+	}
+}
+
+func (pt *ProductTests) getProductsCursor() func(t *testing.T) {
+	return func(t *testing.T) {
+		get := func(url string, doc any) {
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+			pt.app.ServeHTTP(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Should receive a status code of 200 for %s : %d : %s", url, w.Code, w.Body)
+			}
+
+			if err := json.Unmarshal(w.Body.Bytes(), doc); err != nil {
+				t.Fatalf("Should be able to unmarshal the response : %s", err)
+			}
+		}
+
+		var all response.PageDocument[productgrp.AppProductDetails]
+		get("/v1/products?page=1&rows=100&orderBy=product_id", &all)
+
+		// ---------------------------------------------------------------------
+		// Walk the products forward with the cursors.
+
+		var pages []response.CursorDocument[productgrp.AppProductDetails]
+		var ids []string
+
+		cursor := ""
+		for {
+			var doc response.CursorDocument[productgrp.AppProductDetails]
+			get("/v1/products?rows=4&orderBy=product_id&cursor="+cursor, &doc)
+
+			for _, prd := range doc.Items {
+				ids = append(ids, prd.ID)
+			}
+			pages = append(pages, doc)
+
+			if doc.Next == "" {
+				break
+			}
+			cursor = doc.Next
+
+			if len(pages) > len(all.Items) {
+				t.Fatalf("Should reach the last page")
+			}
+		}
+
+		if len(ids) != len(all.Items) {
+			t.Fatalf("Should get every product with the cursors : got %d, exp %d", len(ids), len(all.Items))
+		}
+
+		for i, prd := range all.Items {
+			if ids[i] != prd.ID {
+				t.Fatalf("Should get the products in the same order : got %s, exp %s", ids[i], prd.ID)
+			}
+		}
+
+		if pages[0].Prev != "" {
+			t.Fatalf("Should NOT get a previous cursor on the first page")
+		}
+
+		// ---------------------------------------------------------------------
+		// Walk back one page from the last one.
+
+		if len(pages) < 2 {
+			t.Fatalf("Should get more than one page : got %d", len(pages))
+		}
+
+		last := pages[len(pages)-1]
+
+		var prev response.CursorDocument[productgrp.AppProductDetails]
+		get("/v1/products?rows=4&orderBy=product_id&cursor="+last.Prev, &prev)
+
+		exp := pages[len(pages)-2]
+		if len(prev.Items) != len(exp.Items) || prev.Items[0].ID != exp.Items[0].ID {
+			t.Fatalf("Should get back the page before the last one : got %+v, exp %+v", prev.Items, exp.Items)
+		}
+
+		// ---------------------------------------------------------------------
+		// A tampered cursor is rejected.
+
+		tampered := page.Cursor{
+			OrderBy: order.NewBy(product.OrderByProdID, order.ASC),
+			Key:     &page.Key{Value: "not-a-uuid", ID: uuid.NewString()},
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/products?rows=4&cursor="+tampered.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+		pt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for a tampered cursor : %d : %s", w.Code, w.Body)
+		}
+	}
+}
+
+func (pt *ProductTests) getProductsFilter(prds []product.Product) func(t *testing.T) {
+	return func(t *testing.T) {
+		minCost := prds[0].Cost
+		for _, prd := range prds {
+			minCost = min(minCost, prd.Cost)
+		}
+
+		url := fmt.Sprintf("/v1/products?rows=100&cost%%5Bgt%%5D=%v&orderBy=cost,DESC;name,ASC", minCost)
+
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+		pt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the response : %d : %s", w.Code, w.Body)
+		}
+
+		var pr response.PageDocument[productgrp.AppProductDetails]
+		if err := json.Unmarshal(w.Body.Bytes(), &pr); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		for i, prd := range pr.Items {
+			if prd.Cost <= minCost {
+				t.Fatalf("Should only get the products above the cost : got %v, exp > %v", prd.Cost, minCost)
+			}
+
+			if i == 0 {
+				continue
+			}
+
+			if prev := pr.Items[i-1]; prev.Cost < prd.Cost {
+				t.Fatalf("Should get the products ordered by cost : %+v before %+v", prev, prd)
+			}
+		}
+
+		// ---------------------------------------------------------------------
+
+		r = httptest.NewRequest(http.MethodGet, "/v1/products?cost%5Bgte%5D=abc", nil)
+		w = httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+		pt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for an invalid bound : %d", w.Code)
+		}
+	}
+}
+
+func (pt *ProductTests) batch() func(t *testing.T) {
+	return func(t *testing.T) {
+		send := func(body string) (*httptest.ResponseRecorder, productgrp.AppBatchResults) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/products:batch", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+			pt.app.ServeHTTP(w, r)
+
+			var got productgrp.AppBatchResults
+			if w.Code == http.StatusOK || w.Code == http.StatusUnprocessableEntity {
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatalf("Should be able to unmarshal the response : %s", err)
+				}
+			}
+
+			return w, got
+		}
+
+		statuses := func(res productgrp.AppBatchResults) []int {
+			var got []int
+			for _, r := range res.Results {
+				got = append(got, r.Status)
+			}
+			return got
+		}
+
+		const userID = "5cf37266-3473-4006-984f-9325122678b7"
+
+		// An atomic batch with a failing operation applies none of them.
+		body := fmt.Sprintf(`{"mode": "atomic", "operations": [
+			{"op": "create", "product": {"userID": %[1]q, "name": "Batch Atomic", "cost": 10, "quantity": 1}},
+			{"op": "create", "product": {"userID": %[1]q, "cost": 10, "quantity": 1}}
+		]}`, userID)
+
+		w, got := send(body)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Should receive a status code of 422 for a failing atomic batch : %d : %s", w.Code, w.Body)
+		}
+
+		if diff := cmp.Diff(statuses(got), []int{http.StatusFailedDependency, http.StatusBadRequest}); diff != "" || got.Committed {
+			t.Fatalf("Should get the results of the aborted batch. Diff:\n%s", diff)
+		}
+
+		if len(got.Results[1].Fields) == 0 {
+			t.Fatalf("Should get the field errors of the failing operation : %+v", got.Results[1])
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/products?page=1&rows=10&name=Batch%20Atomic", nil)
+		w = httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+		pt.app.ServeHTTP(w, r)
+
+		var page response.PageDocument[productgrp.AppProductDetails]
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if page.Total != 0 {
+			t.Fatalf("Should NOT create the products of an aborted batch : got %d", page.Total)
+		}
+
+		// A best effort batch applies the operations that pass their checks.
+		body = fmt.Sprintf(`{"mode": "bestEffort", "operations": [
+			{"op": "create", "product": {"userID": %[1]q, "name": "Batch Effort", "cost": 10, "quantity": 1}},
+			{"op": "update", "id": %[2]q, "product": {"name": "Missing"}}
+		]}`, userID, uuid.NewString())
+
+		w, got = send(body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for a best effort batch : %d : %s", w.Code, w.Body)
+		}
+
+		if diff := cmp.Diff(statuses(got), []int{http.StatusCreated, http.StatusNotFound}); diff != "" || !got.Committed {
+			t.Fatalf("Should get the results of the batch. Diff:\n%s", diff)
+		}
+
+		created := got.Results[0].Product
+		if created == nil || created.Name != "Batch Effort" {
+			t.Fatalf("Should get the created product : %+v", got.Results[0])
+		}
+
+		// The created product can be changed through a batch as well.
+		body = fmt.Sprintf(`{"mode": "atomic", "operations": [
+			{"op": "update", "id": %[1]q, "version": 1, "product": {"name": "Batch Updated"}},
+			{"op": "delete", "id": %[1]q}
+		]}`, created.ID)
+
+		w, got = send(body)
+		if w.Code != http.StatusUnprocessableEntity || got.Results[1].Status != http.StatusBadRequest {
+			t.Fatalf("Should NOT be able to change a product twice in a batch : %d : %s", w.Code, w.Body)
+		}
+
+		body = fmt.Sprintf(`{"mode": "atomic", "operations": [
+			{"op": "update", "id": %[1]q, "version": 1, "product": {"name": "Batch Updated"}}
+		]}`, created.ID)
+
+		w, got = send(body)
+		if w.Code != http.StatusOK || got.Results[0].Product == nil || got.Results[0].Product.Name != "Batch Updated" {
+			t.Fatalf("Should be able to update the product in a batch : %d : %s", w.Code, w.Body)
+		}
+
+		body = fmt.Sprintf(`{"mode": "atomic", "operations": [
+			{"op": "delete", "id": %[1]q, "version": 1}
+		]}`, created.ID)
+
+		if w, got := send(body); w.Code != http.StatusUnprocessableEntity || got.Results[0].Status != http.StatusPreconditionFailed {
+			t.Fatalf("Should NOT be able to delete a stale product in a batch : %d : %s", w.Code, w.Body)
+		}
+	}
+}
+
+func (pt *ProductTests) getProductsExport() func(t *testing.T) {
+	return func(t *testing.T) {
+		send := func(url string, accept string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			w := httptest.NewRecorder()
+
+			r.Header.Set("Authorization", "Bearer "+pt.adminToken)
+			r.Header.Set("Accept", accept)
+			pt.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		w := send("/v1/products?page=1&rows=1", "application/json")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the products : %d", w.Code)
+		}
+
+		var page response.PageDocument[productgrp.AppProductDetails]
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		w = send("/v1/products/export", "application/json")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the export : %d : %s", w.Code, w.Body)
+		}
+
+		var prds []productgrp.AppProduct
+		if err := json.Unmarshal(w.Body.Bytes(), &prds); err != nil {
+			t.Fatalf("Should be able to unmarshal the export : %s", err)
+		}
+
+		if len(prds) != page.Total {
+			t.Fatalf("Should export every product : got %d want %d", len(prds), page.Total)
+		}
+
+		w = send("/v1/products/export?orderBy=cost,DESC", "application/x-ndjson")
+		if ct := w.Header().Get("Content-Type"); w.Code != http.StatusOK || ct != "application/x-ndjson" {
+			t.Fatalf("Should receive the export as NDJSON : %d : %q", w.Code, ct)
+		}
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != page.Total {
+			t.Fatalf("Should export a line per product : got %d want %d", len(lines), page.Total)
+		}
+
+		w = send("/v1/products/export", "text/csv")
+		if ct := w.Header().Get("Content-Type"); w.Code != http.StatusOK || ct != "text/csv" {
+			t.Fatalf("Should receive the export as CSV : %d : %q", w.Code, ct)
+		}
+
+		if !strings.HasPrefix(w.Body.String(), "id,userID,name,cost,quantity,active,dateCreated,dateUpdated\n") {
+			t.Fatalf("Should receive the header of the CSV : %s", w.Body)
+		}
+
+		if w := send("/v1/products/export", "application/xml"); w.Code != http.StatusNotAcceptable {
+			t.Fatalf("Should receive a status code of 406 for an unsupported media type : %d", w.Code)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/rolegrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/google/uuid"
+)
+
+// RoleTests holds methods for each role subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type RoleTests struct {
+	app        http.Handler
+	auth       *auth.Auth
+	test       *dbtest.Test
+	userToken  string
+	adminToken string
+}
+
+// Test_Roles is the entry point for testing role management and permission
+// based authorization.
+func Test_Roles(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := RoleTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		auth:       test.V1.Auth,
+		test:       test,
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	t.Run("crud", tests.crud())
+	t.Run("permission", tests.permission())
+	t.Run("assign", tests.assign())
+}
+
+func (rt *RoleTests) send(method string, url string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+token)
+	rt.app.ServeHTTP(w, r)
+
+	return w
+}
+
+func (rt *RoleTests) crud() func(t *testing.T) {
+	return func(t *testing.T) {
+		body := `{"name": "EDITOR", "description": "Edits the catalog", "permissions": ["product:*"]}`
+
+		w := rt.send(http.MethodPost, "/v1/roles", body, rt.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for the response : %d : %s", w.Code, w.Body)
+		}
+
+		var got rolegrp.AppRole
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if got.Name != "EDITOR" || len(got.Permissions) != 1 || got.Permissions[0] != "product:*" {
+			t.Fatalf("Should get back the role : %+v", got)
+		}
+
+		if w := rt.send(http.MethodPost, "/v1/roles", body, rt.adminToken); w.Code != http.StatusConflict {
+			t.Fatalf("Should receive a status code of 409 for a duplicated role : %d", w.Code)
+		}
+
+		body = `{"name": "BROKEN", "permissions": ["product write"]}`
+		if w := rt.send(http.MethodPost, "/v1/roles", body, rt.adminToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for a malformed permission : %d", w.Code)
+		}
+
+		body = `{"permissions": ["product:read"]}`
+		if w := rt.send(http.MethodPut, "/v1/roles/EDITOR", body, rt.adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the update : %d : %s", w.Code, w.Body)
+		}
+
+		w = rt.send(http.MethodGet, "/v1/roles", "", rt.adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the query : %d", w.Code)
+		}
+
+		var roles []rolegrp.AppRole
+		if err := json.Unmarshal(w.Body.Bytes(), &roles); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if len(roles) != 3 {
+			t.Fatalf("Should get the built in roles and the new one : %+v", roles)
+		}
+
+		if w := rt.send(http.MethodDelete, "/v1/roles/ADMIN", "", rt.adminToken); w.Code != http.StatusConflict {
+			t.Fatalf("Should receive a status code of 409 deleting a built in role : %d", w.Code)
+		}
+
+		if w := rt.send(http.MethodDelete, "/v1/roles/EDITOR", "", rt.adminToken); w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the delete : %d", w.Code)
+		}
+
+		if w := rt.send(http.MethodGet, "/v1/roles/EDITOR", "", rt.adminToken); w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for a deleted role : %d", w.Code)
+		}
+	}
+}
+
+func (rt *RoleTests) permission() func(t *testing.T) {
+	return func(t *testing.T) {
+		claims, err := rt.auth.Authenticate(context.Background(), "Bearer "+rt.userToken)
+		if err != nil {
+			t.Fatalf("Should be able to authenticate the user : %s", err)
+		}
+
+		if len(claims.Permissions) == 0 {
+			t.Fatalf("Should get the permissions of the user roles in the token")
+		}
+
+		if w := rt.send(http.MethodGet, "/v1/roles", "", rt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the role:read permission : %d", w.Code)
+		}
+
+		body := `{"name": "EDITOR", "permissions": ["*"]}`
+		if w := rt.send(http.MethodPost, "/v1/roles", body, rt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the role:write permission : %d", w.Code)
+		}
+	}
+}
+
+func (rt *RoleTests) assign() func(t *testing.T) {
+	return func(t *testing.T) {
+		body := `{"name": "Bill Kennedy", "email": "bill@example.com", "roles": ["MISSING"], "password": "gophers", "passwordConfirm": "gophers"}`
+		if w := rt.send(http.MethodPost, "/v1/users", body, rt.adminToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for a role that doesn't exist : %d", w.Code)
+		}
+
+		body = `{"name": "AUDITOR", "permissions": ["user:read"]}`
+		if w := rt.send(http.MethodPost, "/v1/roles", body, rt.adminToken); w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for the role : %d", w.Code)
+		}
+
+		body = `{"name": "Bill Kennedy", "email": "bill@example.com", "roles": ["AUDITOR"], "password": "gophers", "passwordConfirm": "gophers"}`
+		w := rt.send(http.MethodPost, "/v1/users", body, rt.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for a stored role : %d : %s", w.Code, w.Body)
+		}
+
+		var usr usergrp.AppUser
+		if err := json.Unmarshal(w.Body.Bytes(), &usr); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		// The user only gets the permissions of the role.
+
+		token := rt.test.TokenV1("bill@example.com", "gophers")
+
+		if w := rt.send(http.MethodGet, "/v1/products", "", token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the product:read permission : %d", w.Code)
+		}
+
+		body = `{"name": "Comic Books", "cost": 10, "quantity": 5}`
+		if w := rt.send(http.MethodPost, "/v1/products", body, token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the product:write permission : %d", w.Code)
+		}
+
+		if w := rt.send(http.MethodGet, "/v1/orders/"+uuid.NewString(), "", token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the order:read permission : %d", w.Code)
+		}
+
+		body = `{"items": [{"productID": "` + uuid.NewString() + `", "quantity": 1}]}`
+		if w := rt.send(http.MethodPost, "/v1/orders", body, token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the order:write permission : %d", w.Code)
+		}
+
+		if w := rt.send(http.MethodPost, "/v1/orders/"+uuid.NewString()+"/cancel", "", token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the order:write permission : %d", w.Code)
+		}
+
+		body = `{"name": "William Kennedy"}`
+		if w := rt.send(http.MethodPut, "/v1/users/"+usr.ID, body, token); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 without the user:write permission : %d", w.Code)
+		}
+	}
+}
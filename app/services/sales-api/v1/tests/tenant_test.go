@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/productgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/rolegrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/tenantgrp"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
+	"github.com/google/uuid"
+)
+
+// TenantTests holds methods for each tenant subtest. This type allows passing
+// dependencies for tests while still providing a convenient syntax when
+// subtests are registered.
+type TenantTests struct {
+	app        http.Handler
+	test       *dbtest.Test
+	userToken  string
+	adminToken string
+}
+
+// Test_Tenants is the entry point for testing tenant management and the
+// isolation of the tenants.
+func Test_Tenants(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	shutdown := make(chan os.Signal, 1)
+	tests := TenantTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown: shutdown,
+			Log:      test.Log,
+			Auth:     test.V1.Auth,
+			DB:       test.DB,
+		}, all.Routes()),
+		test:       test,
+		userToken:  test.TokenV1("user@example.com", "gophers"),
+		adminToken: test.TokenV1("admin@example.com", "gophers"),
+	}
+
+	// -------------------------------------------------------------------------
+
+	t.Log("Go seeding ...")
+
+	prd, err := api.Product.Create(tenant.Unscoped(context.Background()), product.NewProduct{
+		Name:     "Comic Books",
+		Cost:     25,
+		Quantity: 10,
+		UserID:   uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7"),
+	})
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	t.Run("permission", tests.permission())
+	t.Run("isolation", tests.isolation(prd))
+}
+
+func (tt *TenantTests) send(method string, url string, body string, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, url, strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.Header.Set("Authorization", "Bearer "+token)
+	tt.app.ServeHTTP(w, r)
+
+	return w
+}
+
+func (tt *TenantTests) permission() func(t *testing.T) {
+	return func(t *testing.T) {
+		if w := tt.send(http.MethodPost, "/v1/tenants", `{"name": "Retail"}`, tt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 creating a tenant without the permission : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/tenants", "", tt.userToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 listing the tenants without the permission : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodPost, "/v1/tenants", `{}`, tt.adminToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 for a tenant without a name : %d", w.Code)
+		}
+	}
+}
+
+func (tt *TenantTests) isolation(prd product.Product) func(t *testing.T) {
+	return func(t *testing.T) {
+		w := tt.send(http.MethodPost, "/v1/tenants", `{"name": "Wholesale"}`, tt.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 creating a tenant : %d : %s", w.Code, w.Body)
+		}
+
+		var tnt tenantgrp.AppTenant
+		if err := json.NewDecoder(w.Body).Decode(&tnt); err != nil {
+			t.Fatalf("Should be able to unmarshal the tenant : %s", err)
+		}
+
+		if w := tt.send(http.MethodPost, "/v1/tenants", `{"name": "Wholesale"}`, tt.adminToken); w.Code != http.StatusConflict {
+			t.Fatalf("Should receive a status code of 409 for a duplicated tenant : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+		// An admin with the permission to manage tenants creates the first
+		// user of the tenant.
+
+		body := fmt.Sprintf(`{"tenantID": %q, "name": "Wholesale Admin", "email": "wholesale@example.com", "roles": ["ADMIN", "USER"], "password": "gophers", "passwordConfirm": "gophers"}`, tnt.ID)
+
+		w = tt.send(http.MethodPost, "/v1/users", body, tt.adminToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 creating a user in the tenant : %d : %s", w.Code, w.Body)
+		}
+
+		var usr usergrp.AppUser
+		if err := json.NewDecoder(w.Body).Decode(&usr); err != nil {
+			t.Fatalf("Should be able to unmarshal the user : %s", err)
+		}
+
+		if usr.TenantID != tnt.ID {
+			t.Fatalf("Should create the user in the tenant : got %s, exp %s", usr.TenantID, tnt.ID)
+		}
+
+		unknown := strings.Replace(body, tnt.ID, uuid.NewString(), 1)
+		unknown = strings.Replace(unknown, "wholesale@example.com", "unknown@example.com", 1)
+
+		if w := tt.send(http.MethodPost, "/v1/users", unknown, tt.adminToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 creating a user in an unknown tenant : %d", w.Code)
+		}
+
+		tenantToken := tt.test.TokenV1("wholesale@example.com", "gophers")
+
+		// ---------------------------------------------------------------------
+		// The admin of the tenant manages the roles of the tenant only and
+		// can't manage the tenants.
+
+		if w := tt.send(http.MethodGet, "/v1/tenants", "", tenantToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 listing the tenants as the admin of a tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodPost, "/v1/tenants", `{"name": "Retail"}`, tenantToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 creating a tenant as the admin of a tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodPut, "/v1/roles/ADMIN", `{"permissions": ["*", "platform:*"]}`, tenantToken); w.Code != http.StatusBadRequest {
+			t.Fatalf("Should receive a status code of 400 granting a platform permission in a tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodPut, "/v1/roles/USER", `{"permissions": ["*"]}`, tenantToken); w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 updating a role of the tenant : %d : %s", w.Code, w.Body)
+		}
+
+		w = tt.send(http.MethodGet, "/v1/roles/USER", "", tt.adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 retrieving the role : %d : %s", w.Code, w.Body)
+		}
+
+		var rol rolegrp.AppRole
+		if err := json.NewDecoder(w.Body).Decode(&rol); err != nil {
+			t.Fatalf("Should be able to unmarshal the role : %s", err)
+		}
+
+		if slices.Contains(rol.Permissions, "*") {
+			t.Fatalf("Should NOT change the role of another tenant : %v", rol.Permissions)
+		}
+
+		// ---------------------------------------------------------------------
+		// The admin of the tenant only sees the rows of the tenant.
+
+		w = tt.send(http.MethodGet, "/v1/users?page=1&rows=10", "", tenantToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 listing the users : %d : %s", w.Code, w.Body)
+		}
+
+		var users response.PageDocument[usergrp.AppUser]
+		if err := json.NewDecoder(w.Body).Decode(&users); err != nil {
+			t.Fatalf("Should be able to unmarshal the users : %s", err)
+		}
+
+		if users.Total != 1 || len(users.Items) != 1 || users.Items[0].ID != usr.ID {
+			t.Fatalf("Should only list the users of the tenant : %+v", users)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/users/5cf37266-3473-4006-984f-9325122678b7", "", tenantToken); w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for a user of another tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/products/"+prd.ID.String(), "", tenantToken); w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for a product of another tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodDelete, "/v1/products/"+prd.ID.String(), "", tenantToken); w.Code != http.StatusNoContent {
+			t.Fatalf("Should not reveal a product of another tenant on delete : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/products/"+prd.ID.String(), "", tt.adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Should NOT be able to delete a product of another tenant : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+		// The rows of the tenant are not visible from the other tenants.
+
+		body = fmt.Sprintf(`{"name": "Crates", "cost": 10, "quantity": 5, "userID": %q}`, usr.ID)
+
+		w = tt.send(http.MethodPost, "/v1/products", body, tenantToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 creating a product in the tenant : %d : %s", w.Code, w.Body)
+		}
+
+		var tenantPrd productgrp.AppProduct
+		if err := json.NewDecoder(w.Body).Decode(&tenantPrd); err != nil {
+			t.Fatalf("Should be able to unmarshal the product : %s", err)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/products/"+tenantPrd.ID, "", tt.adminToken); w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for a product of another tenant : %d", w.Code)
+		}
+
+		if w := tt.send(http.MethodGet, "/v1/users/"+usr.ID, "", tt.adminToken); w.Code != http.StatusNotFound {
+			t.Fatalf("Should receive a status code of 404 for a user of another tenant : %d", w.Code)
+		}
+	}
+}
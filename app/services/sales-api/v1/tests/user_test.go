@@ -16,6 +16,7 @@ import (
 
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
@@ -57,6 +58,11 @@ func Test_Users(t *testing.T) {
 			Log:      test.Log,
 			Auth:     test.V1.Auth,
 			DB:       test.DB,
+			Lockout: user.LockoutConfig{
+				Threshold: 3,
+				BaseDelay: time.Minute,
+				MaxDelay:  time.Hour,
+			},
 		}, all.Routes()),
 		userToken:  test.TokenV1("user@example.com", "gophers"),
 		adminToken: test.TokenV1("admin@example.com", "gophers"),
@@ -73,7 +79,7 @@ func Test_Users(t *testing.T) {
 
 	t.Log("Go seeding ...")
 
-	usrs, err := seed(context.Background(), api.User)
+	usrs, err := seed(tenant.Unscoped(context.Background()), api.User)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
@@ -89,6 +95,8 @@ func Test_Users(t *testing.T) {
 	t.Run("putUser404", tests.putUser404())
 	t.Run("getUsers200", tests.getUsers200(usrs))
 	t.Run("crudUsers", tests.crudUser())
+	t.Run("refreshToken", tests.refreshToken())
+	t.Run("lockout", tests.lockout())
 }
 
 func (ut *UserTests) getToken404() func(t *testing.T) {
@@ -122,11 +130,162 @@ func (ut *UserTests) getToken200() func(t *testing.T) {
 		}
 
 		var got struct {
-			Token string `json:"token"`
+			Token        string `json:"token"`
+			RefreshToken string `json:"refreshToken"`
 		}
 		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
 			t.Fatalf("Should be able to unmarshal the response : %s", err)
 		}
+
+		if got.Token == "" || got.RefreshToken == "" {
+			t.Fatalf("Should receive a token and a refresh token : %+v", got)
+		}
+	}
+}
+
+func (ut *UserTests) lockout() func(t *testing.T) {
+	return func(t *testing.T) {
+		token := func(password string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, "/v1/users/token", nil)
+			w := httptest.NewRecorder()
+
+			r.SetBasicAuth("user@example.com", password)
+			ut.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		for i := 0; i < 2; i++ {
+			if w := token("wrong-password"); w.Code != http.StatusUnauthorized {
+				t.Fatalf("Should receive a status code of 401 before the threshold : %d", w.Code)
+			}
+		}
+
+		w := token("wrong-password")
+		if w.Code != http.StatusLocked {
+			t.Fatalf("Should receive a status code of 423 once the threshold is reached : %d", w.Code)
+		}
+
+		if w.Header().Get("Retry-After") == "" {
+			t.Fatalf("Should receive a Retry-After header with the lockout")
+		}
+
+		if w := token("gophers"); w.Code != http.StatusLocked {
+			t.Fatalf("Should NOT be able to login with the right password while locked : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+
+		url := "/v1/users/45b5fbd3-755f-4379-8f07-a58d4a30fa2f/unlock"
+
+		r := httptest.NewRequest(http.MethodPost, url, nil)
+		w = httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ut.userToken)
+		ut.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should NOT be able to unlock without the admin role : %d", w.Code)
+		}
+
+		r = httptest.NewRequest(http.MethodPost, url, nil)
+		w = httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+ut.adminToken)
+		ut.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the unlock : %d", w.Code)
+		}
+
+		var got usergrp.AppUser
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if got.FailedLogins != 0 || got.LockedUntil != "" {
+			t.Fatalf("Should get back the user without a lockout : %+v", got)
+		}
+
+		if w := token("gophers"); w.Code != http.StatusOK {
+			t.Fatalf("Should be able to login after the unlock : %d", w.Code)
+		}
+	}
+}
+
+func (ut *UserTests) refreshToken() func(t *testing.T) {
+	return func(t *testing.T) {
+		type tokens struct {
+			Token        string `json:"token"`
+			RefreshToken string `json:"refreshToken"`
+		}
+
+		send := func(method string, url string, body string, bearer string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(method, url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			if bearer != "" {
+				r.Header.Set("Authorization", "Bearer "+bearer)
+			}
+			ut.app.ServeHTTP(w, r)
+
+			return w
+		}
+
+		const url = "/v1/users/token"
+
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		r.SetBasicAuth("user@example.com", "gophers")
+		ut.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the token : %d", w.Code)
+		}
+
+		var login tokens
+		if err := json.NewDecoder(w.Body).Decode(&login); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		// ---------------------------------------------------------------------
+
+		w = send(http.MethodPost, url+"/refresh", fmt.Sprintf(`{"refreshToken": %q}`, login.RefreshToken), "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the refresh : %d", w.Code)
+		}
+
+		var refreshed tokens
+		if err := json.NewDecoder(w.Body).Decode(&refreshed); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if refreshed.RefreshToken == login.RefreshToken {
+			t.Fatal("Should receive a rotated refresh token")
+		}
+
+		w = send(http.MethodPost, url+"/refresh", fmt.Sprintf(`{"refreshToken": %q}`, login.RefreshToken), "")
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 when reusing a refresh token : %d", w.Code)
+		}
+
+		// ---------------------------------------------------------------------
+
+		w = send(http.MethodGet, "/v1/users/45b5fbd3-755f-4379-8f07-a58d4a30fa2f", "", refreshed.Token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 before the revoke : %d", w.Code)
+		}
+
+		w = send(http.MethodPost, "/v1/users/token/revoke", "", refreshed.Token)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Should receive a status code of 204 for the revoke : %d", w.Code)
+		}
+
+		w = send(http.MethodGet, "/v1/users/45b5fbd3-755f-4379-8f07-a58d4a30fa2f", "", refreshed.Token)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("Should receive a status code of 401 after the revoke : %d", w.Code)
+		}
 	}
 }
 
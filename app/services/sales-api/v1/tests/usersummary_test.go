@@ -13,6 +13,7 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usersummarygrp"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
@@ -83,7 +84,7 @@ func Test_UserSummary(t *testing.T) {
 
 	t.Log("Go seeding ...")
 
-	usrs, _, err := seed(context.Background(), api.User, api.Product)
+	usrs, _, err := seed(tenant.Unscoped(context.Background()), api.User, api.Product)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
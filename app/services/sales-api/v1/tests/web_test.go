@@ -12,6 +12,7 @@ import (
 
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
 	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/usergrp"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
@@ -77,7 +78,7 @@ func Test_Web(t *testing.T) {
 
 	t.Log("Seeding data ...")
 
-	sd, err := seed(context.Background(), api)
+	sd, err := seed(tenant.Unscoped(context.Background()), api)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
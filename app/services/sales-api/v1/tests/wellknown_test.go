@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/cmd/all"
+	"github.com/diegomagalhaes-dev/go-service/app/services/sales-api/v1/handlers/wellknowngrp"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	v1 "github.com/diegomagalhaes-dev/go-service/business/web/v1"
+	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// WellKnownTests holds methods for each well known subtest. This type allows
+// passing dependencies for tests while still providing a convenient syntax
+// when subtests are registered.
+type WellKnownTests struct {
+	app http.Handler
+}
+
+// Test_WellKnown is the entry point for testing the well known documents.
+func Test_WellKnown(t *testing.T) {
+	t.Parallel()
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	shutdown := make(chan os.Signal, 1)
+	tests := WellKnownTests{
+		app: v1.APIMux(v1.APIMuxConfig{
+			Shutdown:  shutdown,
+			Log:       test.Log,
+			Auth:      test.V1.Auth,
+			DB:        test.DB,
+			PublicURL: "https://sales.example.com",
+		}, all.Routes()),
+	}
+
+	t.Run("getJWKS200", tests.getJWKS200())
+	t.Run("getDiscovery200", tests.getDiscovery200())
+}
+
+func (wt *WellKnownTests) getJWKS200() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+
+		wt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the response : %d", w.Code)
+		}
+
+		var got auth.JWKS
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if len(got.Keys) != 1 {
+			t.Fatalf("Should get back a single key : %d", len(got.Keys))
+		}
+
+		key := got.Keys[0]
+		if key.Kid != "s4sKIjD9kIRjxs2tulPqGLdxSfgPErRN1Mu3Hd9k9NQ" || key.Kty != "RSA" || key.Alg != jwt.SigningMethodRS256.Alg() {
+			t.Fatalf("Should get back the public key of the test kid : %+v", key)
+		}
+
+		if key.N == "" || key.E != "AQAB" {
+			t.Fatalf("Should get back the modulus and exponent of the key : %+v", key)
+		}
+	}
+}
+
+func (wt *WellKnownTests) getDiscovery200() func(t *testing.T) {
+	return func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+		w := httptest.NewRecorder()
+
+		r.Host = "attacker.example.com"
+		r.Header.Set("X-Forwarded-Host", "attacker.example.com")
+		r.Header.Set("X-Forwarded-Proto", "http")
+		wt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Should receive a status code of 200 for the response : %d", w.Code)
+		}
+
+		var got wellknowngrp.AppDiscovery
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("Should be able to unmarshal the response : %s", err)
+		}
+
+		if got.Issuer != "service project" {
+			t.Fatalf("Should get back the issuer : %q", got.Issuer)
+		}
+
+		if got.JWKSURI != "https://sales.example.com/.well-known/jwks.json" {
+			t.Fatalf("Should get back the location of the jwks from the configured url : %q", got.JWKSURI)
+		}
+
+		if got.TokenEndpoint != "https://sales.example.com/v1/users/token" {
+			t.Fatalf("Should get back the token endpoint from the configured url : %q", got.TokenEndpoint)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
+	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary/stores/usersummarydb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+)
+
+// Set of formats rows can be exported in.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// exportRowsPerPage is the number of rows read from the database at a time.
+const exportRowsPerPage = 500
+
+// Export writes every row of the specified entity to stdout in the format.
+func Export(log *logger.Logger, cfg db.Config, entity string, format string) error {
+	if format != FormatCSV && format != FormatNDJSON {
+		fmt.Println("help: export <users|products|summary> --format <csv|ndjson>")
+		return ErrHelp
+	}
+
+	db, err := db.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer db.Close()
+
+	ctx := tenant.Unscoped(context.Background())
+
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db))
+	usrCore := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
+
+	w := bufio.NewWriter(os.Stdout)
+
+	switch entity {
+	case "users":
+		query := func(pageNumber int) ([]user.User, error) {
+			return usrCore.Query(ctx, user.QueryFilter{}, user.DefaultOrderBy, pageNumber, exportRowsPerPage)
+		}
+		err = export(w, format, exportUserHeader, query, toExportUser)
+
+	case "products":
+		prdCore := product.NewCore(log, evnCore, audCore, usrCore, productdb.NewStore(log, db))
+		query := func(pageNumber int) ([]product.Product, error) {
+			return prdCore.Query(ctx, product.QueryFilter{}, product.DefaultOrderBy, pageNumber, exportRowsPerPage)
+		}
+		err = export(w, format, exportProductHeader, query, toExportProduct)
+
+	case "summary":
+		smmCore := usersummary.NewCore(usersummarydb.NewStore(log, db))
+		query := func(pageNumber int) ([]usersummary.Summary, error) {
+			return smmCore.Query(ctx, usersummary.QueryFilter{}, usersummary.DefaultOrderBy, pageNumber, exportRowsPerPage)
+		}
+		err = export(w, format, exportSummaryHeader, query, toExportSummary)
+
+	default:
+		fmt.Println("help: export <users|products|summary> --format <csv|ndjson>")
+		return ErrHelp
+	}
+
+	if err != nil {
+		return fmt.Errorf("export %s: %w", entity, err)
+	}
+
+	return nil
+}
+
+// exportRow is a row of an export. It's written as a document in NDJSON and
+// as a record under the header of the entity in CSV.
+type exportRow interface {
+	record() []string
+}
+
+// export reads the rows a page at a time and writes them out in the format.
+// The output is flushed after every page, so the rows are streamed instead of
+// held in memory.
+func export[T any](w *bufio.Writer, format string, header []string, query func(pageNumber int) ([]T, error), toRow func(T) exportRow) error {
+	var write func(row exportRow) error
+	var flush func() error
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+
+		write = func(row exportRow) error {
+			return cw.Write(row.record())
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+
+	default:
+		enc := json.NewEncoder(w)
+
+		write = func(row exportRow) error {
+			return enc.Encode(row)
+		}
+		flush = func() error {
+			return nil
+		}
+	}
+
+	for pageNumber := 1; ; pageNumber++ {
+		rows, err := query(pageNumber)
+		if err != nil {
+			return fmt.Errorf("query: page[%d]: %w", pageNumber, err)
+		}
+
+		for _, row := range rows {
+			if err := write(toRow(row)); err != nil {
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+
+		if err := flush(); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("flush: %w", err)
+		}
+
+		if len(rows) < exportRowsPerPage {
+			return nil
+		}
+	}
+}
+
+// =============================================================================
+
+var exportUserHeader = []string{"id", "name", "email", "roles", "department", "enabled", "dateCreated", "dateUpdated"}
+
+type exportUser struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Email       string   `json:"email"`
+	Roles       []string `json:"roles"`
+	Department  string   `json:"department"`
+	Enabled     bool     `json:"enabled"`
+	DateCreated string   `json:"dateCreated"`
+	DateUpdated string   `json:"dateUpdated"`
+}
+
+func toExportUser(usr user.User) exportRow {
+	roles := make([]string, len(usr.Roles))
+	for i, role := range usr.Roles {
+		roles[i] = role.Name()
+	}
+
+	return exportUser{
+		ID:          usr.ID.String(),
+		Name:        usr.Name,
+		Email:       usr.Email.Address,
+		Roles:       roles,
+		Department:  usr.Department,
+		Enabled:     usr.Enabled,
+		DateCreated: usr.DateCreated.Format(time.RFC3339),
+		DateUpdated: usr.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func (e exportUser) record() []string {
+	return []string{e.ID, e.Name, e.Email, strings.Join(e.Roles, ","), e.Department, strconv.FormatBool(e.Enabled), e.DateCreated, e.DateUpdated}
+}
+
+var exportProductHeader = []string{"id", "userID", "name", "cost", "quantity", "dateCreated", "dateUpdated"}
+
+type exportProduct struct {
+	ID          string  `json:"id"`
+	UserID      string  `json:"userID"`
+	Name        string  `json:"name"`
+	Cost        float64 `json:"cost"`
+	Quantity    int     `json:"quantity"`
+	DateCreated string  `json:"dateCreated"`
+	DateUpdated string  `json:"dateUpdated"`
+}
+
+func toExportProduct(prd product.Product) exportRow {
+	return exportProduct{
+		ID:          prd.ID.String(),
+		UserID:      prd.UserID.String(),
+		Name:        prd.Name,
+		Cost:        prd.Cost,
+		Quantity:    prd.Quantity,
+		DateCreated: prd.DateCreated.Format(time.RFC3339),
+		DateUpdated: prd.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func (e exportProduct) record() []string {
+	return []string{e.ID, e.UserID, e.Name, strconv.FormatFloat(e.Cost, 'f', -1, 64), strconv.Itoa(e.Quantity), e.DateCreated, e.DateUpdated}
+}
+
+var exportSummaryHeader = []string{"userID", "userName", "totalCount", "totalCost"}
+
+type exportSummary struct {
+	UserID     string  `json:"userID"`
+	UserName   string  `json:"userName"`
+	TotalCount int     `json:"totalCount"`
+	TotalCost  float64 `json:"totalCost"`
+}
+
+func toExportSummary(smm usersummary.Summary) exportRow {
+	return exportSummary{
+		UserID:     smm.UserID.String(),
+		UserName:   smm.UserName,
+		TotalCount: smm.TotalCount,
+		TotalCost:  smm.TotalCost,
+	}
+}
+
+func (e exportSummary) record() []string {
+	return []string{e.UserID, e.UserName, strconv.Itoa(e.TotalCount), strconv.FormatFloat(e.TotalCost, 'f', -1, 64)}
+}
@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -9,13 +13,14 @@ import (
 	"os"
 )
 
-// GenKey creates an x509 private/public key for auth tokens.
-func GenKey() error {
+// GenKey creates an x509 private/public key for auth tokens. The algorithm
+// can be RS256, ES256 or EdDSA and defaults to RS256.
+func GenKey(alg string) error {
 
-	// Generate a new private key.
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	// Generate a new private key and the PEM block that holds it.
+	privateKey, privateBlock, err := genPrivateKey(alg)
 	if err != nil {
-		return fmt.Errorf("generating key: %w", err)
+		return err
 	}
 
 	// Create a file for the private key information in PEM form.
@@ -25,12 +30,6 @@ func GenKey() error {
 	}
 	defer privateFile.Close()
 
-	// Construct a PEM block for the private key.
-	privateBlock := pem.Block{
-		Type:  "PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
-	}
-
 	// Write the private key to the private key file.
 	if err := pem.Encode(privateFile, &privateBlock); err != nil {
 		return fmt.Errorf("encoding to private file: %w", err)
@@ -44,7 +43,7 @@ func GenKey() error {
 	defer publicFile.Close()
 
 	// Marshal the public key from the private key to PKIX.
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
 	if err != nil {
 		return fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -60,6 +59,71 @@ func GenKey() error {
 		return fmt.Errorf("encoding to public file: %w", err)
 	}
 
-	fmt.Println("private and public key files generated")
+	fmt.Printf("%s private and public key files generated\n", keyAlg(alg))
 	return nil
 }
+
+// genPrivateKey generates a private key for the specified algorithm and
+// constructs the PEM block for it.
+func genPrivateKey(alg string) (crypto.Signer, pem.Block, error) {
+	switch keyAlg(alg) {
+	case "RS256":
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, pem.Block{}, fmt.Errorf("generating key: %w", err)
+		}
+
+		block := pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}
+
+		return privateKey, block, nil
+
+	case "ES256":
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, pem.Block{}, fmt.Errorf("generating key: %w", err)
+		}
+
+		der, err := x509.MarshalECPrivateKey(privateKey)
+		if err != nil {
+			return nil, pem.Block{}, fmt.Errorf("marshaling private key: %w", err)
+		}
+
+		block := pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: der,
+		}
+
+		return privateKey, block, nil
+
+	case "EdDSA":
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, pem.Block{}, fmt.Errorf("generating key: %w", err)
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, pem.Block{}, fmt.Errorf("marshaling private key: %w", err)
+		}
+
+		block := pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: der,
+		}
+
+		return privateKey, block, nil
+	}
+
+	return nil, pem.Block{}, fmt.Errorf("unsupported algorithm %q, use RS256, ES256 or EdDSA", alg)
+}
+
+// keyAlg returns the algorithm to use, defaulting to RS256.
+func keyAlg(alg string) string {
+	if alg == "" {
+		return "RS256"
+	}
+	return alg
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
@@ -16,24 +20,21 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenToken generates a JWT for the specified user.
-func GenToken(log *logger.Logger, dbConfig db.Config, vaultConfig vault.Config, userID uuid.UUID, kid string) error {
-	if kid == "" {
-		fmt.Println("help: gentoken <user_id> <kid>")
-		return ErrHelp
-	}
-
+// GenToken generates a JWT for the specified user. The token is signed with
+// the active kid of the key ring.
+func GenToken(log *logger.Logger, dbConfig db.Config, vaultConfig vault.Config, userID uuid.UUID) error {
 	db, err := db.Open(dbConfig)
 	if err != nil {
 		return fmt.Errorf("connect database: %w", err)
 	}
 	defer db.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 5*time.Second)
 	defer cancel()
 
-	evnCore := event.NewCore(log)
-	core := user.NewCore(log, evnCore, userdb.NewStore(log, db))
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db))
+	core := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
 
 	usr, err := core.QueryByID(ctx, userID)
 	if err != nil {
@@ -56,9 +57,16 @@ func GenToken(log *logger.Logger, dbConfig db.Config, vaultConfig vault.Config,
 		return fmt.Errorf("constructing auth: %w", err)
 	}
 
+	perms, err := a.Permissions(ctx, usr.TenantID, usr.Roles)
+	if err != nil {
+		return fmt.Errorf("retrieve permissions: %w", err)
+	}
+
 	// Generating a token requires defining a set of claims. In this applications
 	// case, we only care about defining the subject and the user in question and
-	// the roles they have on the database. This token will expire in a year.
+	// the roles they have on the database along with the permissions the roles
+	// grant and the tenant the user belongs to. This token will expire in a
+	// year.
 	//
 	// iss (issuer): Issuer of the JWT
 	// sub (subject): Subject of the JWT (the user)
@@ -69,19 +77,22 @@ func GenToken(log *logger.Logger, dbConfig db.Config, vaultConfig vault.Config,
 	// jti (JWT ID): Unique identifier; can be used to prevent the JWT from being replayed (allows a token to be used only once)
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   usr.ID.String(),
 			Issuer:    "service project",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(8760 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: usr.Roles,
+		TenantID:    usr.TenantID.String(),
+		Roles:       usr.Roles,
+		Permissions: perms,
 	}
 
 	// This will generate a JWT with the claims embedded in them. The database
 	// with need to be configured with the information found in the public key
 	// file to validate these claims. Dgraph does not support key rotate at
 	// this time.
-	token, err := a.GenerateToken(kid, claims)
+	token, err := a.GenerateToken(claims)
 	if err != nil {
 		return fmt.Errorf("generating token: %w", err)
 	}
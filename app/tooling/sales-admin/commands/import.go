@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidLines is returned when lines of an import fail their checks.
+var ErrInvalidLines = errors.New("invalid lines")
+
+// importRowsPerInsert is the number of rows added with a single insert, which
+// keeps the insert well under the limit of parameters of a statement.
+const importRowsPerInsert = 1000
+
+// importProduct represents a line of a products import.
+type importProduct struct {
+	UserID   string  `json:"userID" validate:"required,uuid"`
+	Name     string  `json:"name" validate:"required"`
+	Cost     float64 `json:"cost" validate:"gte=0"`
+	Quantity int     `json:"quantity" validate:"gte=1"`
+}
+
+// importLine is a line of an import that passed its checks.
+type importLine struct {
+	line int
+	np   product.NewProduct
+}
+
+// Import adds the rows of a CSV file to the database. Every line is checked
+// before any is added, the rows are only added when all the lines are valid
+// and they are added within a single transaction.
+func Import(log *logger.Logger, cfg db.Config, entity string, path string) error {
+	if entity != "products" || path == "" {
+		fmt.Println("help: import products <file.csv>")
+		return ErrHelp
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	lines, failed, err := readProducts(f)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	sqlDB, err := db.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer sqlDB.Close()
+
+	ctx := tenant.Unscoped(context.Background())
+
+	evnCore := event.NewCore(log, eventdb.NewStore(log, sqlDB))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, sqlDB))
+	usrCore := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, sqlDB))
+	prdCore := product.NewCore(log, evnCore, audCore, usrCore, productdb.NewStore(log, sqlDB))
+
+	nps := make([]product.NewProduct, len(lines))
+	for i, l := range lines {
+		nps[i] = l.np
+	}
+
+	prds, errs, err := prdCore.PrepareBatch(ctx, nps)
+	if err != nil {
+		return fmt.Errorf("prepare products: %w", err)
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			reportLine(lines[i].line, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d %w, nothing imported", failed, ErrInvalidLines)
+	}
+
+	create := func(tx transaction.Transaction) error {
+		prdCore, err := prdCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return err
+		}
+
+		ctx := transaction.Set(ctx, tx)
+
+		for start := 0; start < len(prds); start += importRowsPerInsert {
+			end := min(start+importRowsPerInsert, len(prds))
+
+			if err := prdCore.CreateBatch(ctx, prds[start:end]); err != nil {
+				return fmt.Errorf("create products: lines[%d-%d]: %w", lines[start].line, lines[end-1].line, err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := transaction.ExecuteUnderTransaction(ctx, log, db.NewBeginner(sqlDB), create); err != nil {
+		return err
+	}
+
+	fmt.Println("products imported:", len(prds))
+	return nil
+}
+
+// readProducts reads the lines of a products import, which starts with a
+// header naming its columns. The lines failing their checks are reported and
+// counted, the others are returned.
+func readProducts(r io.Reader) ([]importLine, int, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{"userID", "name", "cost", "quantity"} {
+		if _, exists := columns[name]; !exists {
+			return nil, 0, fmt.Errorf("header: missing column %q", name)
+		}
+	}
+
+	var lines []importLine
+	var failed int
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return nil, 0, err
+			}
+
+			reportLine(perr.StartLine, perr.Err)
+			failed++
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		np, err := toImportProduct(record, columns)
+		if err != nil {
+			reportLine(line, err)
+			failed++
+			continue
+		}
+
+		lines = append(lines, importLine{line: line, np: np})
+	}
+
+	return lines, failed, nil
+}
+
+// toImportProduct checks a record of a products import and returns the new
+// product it holds.
+func toImportProduct(record []string, columns map[string]int) (product.NewProduct, error) {
+	field := func(name string) string {
+		return strings.TrimSpace(record[columns[name]])
+	}
+
+	var fields validate.FieldErrors
+
+	cost, err := strconv.ParseFloat(field("cost"), 64)
+	if err != nil {
+		fields = append(fields, validate.FieldError{Field: "cost", Err: "cost must be a number"})
+	}
+
+	quantity, err := strconv.Atoi(field("quantity"))
+	if err != nil {
+		fields = append(fields, validate.FieldError{Field: "quantity", Err: "quantity must be a whole number"})
+	}
+
+	imp := importProduct{
+		UserID:   field("userID"),
+		Name:     field("name"),
+		Cost:     cost,
+		Quantity: quantity,
+	}
+
+	if err := validate.Check(imp); err != nil {
+		// A field that could not be parsed is already reported.
+		parsed := fields.Fields()
+		for _, fe := range validate.GetFieldErrors(err) {
+			if _, exists := parsed[fe.Field]; !exists {
+				fields = append(fields, fe)
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return product.NewProduct{}, fields
+	}
+
+	np := product.NewProduct{
+		UserID:   uuid.MustParse(imp.UserID),
+		Name:     imp.Name,
+		Cost:     imp.Cost,
+		Quantity: imp.Quantity,
+	}
+
+	return np, nil
+}
+
+// reportLine prints the error of a line, one field at a time for the errors
+// of a validation.
+func reportLine(line int, err error) {
+	if !validate.IsFieldErrors(err) {
+		fmt.Printf("line %d: %s\n", line, err)
+		return
+	}
+
+	for _, fe := range validate.GetFieldErrors(err) {
+		fmt.Printf("line %d: %s: %s\n", line, fe.Field, fe.Err)
+	}
+}
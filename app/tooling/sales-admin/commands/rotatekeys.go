@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/vault"
+	"github.com/google/uuid"
+)
+
+// RotateKeys mints a new private key into vault and promotes it to be the
+// active signing kid. The kid that was active before keeps verifying tokens
+// until the grace period is over, so tokens already issued remain valid.
+func RotateKeys(vaultConfig vault.Config, alg string, grace time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	vaultSrv, err := vault.New(vault.Config{
+		Address:   vaultConfig.Address,
+		Token:     vaultConfig.Token,
+		MountPath: vaultConfig.MountPath,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing vault: %w", err)
+	}
+
+	_, privateBlock, err := genPrivateKey(alg)
+	if err != nil {
+		return err
+	}
+
+	kid := uuid.NewString()
+
+	if err := vaultSrv.AddPrivateKey(ctx, kid, pem.EncodeToMemory(&privateBlock)); err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+
+	retireAt := time.Now().Add(grace)
+
+	if err := vaultSrv.Promote(ctx, kid, retireAt); err != nil {
+		return fmt.Errorf("promote: %w", err)
+	}
+
+	fmt.Printf("%s kid %s is active, previous kid retires at %s\n", keyAlg(alg), kid, retireAt.Format(time.RFC3339))
+	return nil
+}
@@ -6,7 +6,11 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
@@ -26,11 +30,12 @@ func UserAdd(log *logger.Logger, cfg db.Config, name, email, password string) er
 	}
 	defer db.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 5*time.Second)
 	defer cancel()
 
-	evnCore := event.NewCore(log)
-	core := user.NewCore(log, evnCore, userdb.NewStore(log, db))
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db))
+	core := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
 
 	addr, err := mail.ParseAddress(email)
 	if err != nil {
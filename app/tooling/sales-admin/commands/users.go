@@ -8,7 +8,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
@@ -23,7 +27,7 @@ func Users(log *logger.Logger, cfg db.Config, pageNumber string, rowsPerPage str
 	}
 	defer db.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 5*time.Second)
 	defer cancel()
 
 	page, err := strconv.Atoi(pageNumber)
@@ -36,8 +40,9 @@ func Users(log *logger.Logger, cfg db.Config, pageNumber string, rowsPerPage str
 		return fmt.Errorf("converting rows per page: %w", err)
 	}
 
-	evnCore := event.NewCore(log)
-	core := user.NewCore(log, evnCore, userdb.NewStore(log, db))
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db))
+	core := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
 
 	users, err := core.Query(ctx, user.QueryFilter{}, user.DefaultOrderBy, page, rows)
 	if err != nil {
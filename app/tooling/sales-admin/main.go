@@ -4,9 +4,11 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/ardanlabs/conf/v3"
 	"github.com/diegomagalhaes-dev/go-service/app/tooling/sales-admin/commands"
@@ -122,8 +124,30 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 			return fmt.Errorf("getting users: %w", err)
 		}
 
+	case "export":
+		entity := args.Num(1)
+		format := commands.FormatNDJSON
+		if len(args) > 2 {
+			fs := flag.NewFlagSet("export", flag.ContinueOnError)
+			fs.StringVar(&format, "format", format, "csv or ndjson")
+			if err := fs.Parse(args[2:]); err != nil {
+				return fmt.Errorf("parsing export flags: %w", err)
+			}
+		}
+		if err := commands.Export(log, dbConfig, entity, format); err != nil {
+			return fmt.Errorf("exporting %s: %w", entity, err)
+		}
+
+	case "import":
+		entity := args.Num(1)
+		path := args.Num(2)
+		if err := commands.Import(log, dbConfig, entity, path); err != nil {
+			return fmt.Errorf("importing %s: %w", entity, err)
+		}
+
 	case "genkey":
-		if err := commands.GenKey(); err != nil {
+		alg := args.Num(1)
+		if err := commands.GenKey(alg); err != nil {
 			return fmt.Errorf("key generation: %w", err)
 		}
 
@@ -132,8 +156,7 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		if err != nil {
 			return fmt.Errorf("generating token: %w", err)
 		}
-		kid := args.Num(2)
-		if err := commands.GenToken(log, dbConfig, vaultConfig, userID, kid); err != nil {
+		if err := commands.GenToken(log, dbConfig, vaultConfig, userID); err != nil {
 			return fmt.Errorf("generating token: %w", err)
 		}
 
@@ -142,6 +165,20 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 			return fmt.Errorf("setting private key: %w", err)
 		}
 
+	case "rotate-keys":
+		alg := args.Num(1)
+		grace := 24 * time.Hour
+		if v := args.Num(2); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("parsing grace period: %w", err)
+			}
+			grace = d
+		}
+		if err := commands.RotateKeys(vaultConfig, alg, grace); err != nil {
+			return fmt.Errorf("rotating keys: %w", err)
+		}
+
 	case "vault-init":
 		if err := commands.VaultInit(vaultConfig); err != nil {
 			return fmt.Errorf("initializing vault instance: %w", err)
@@ -152,10 +189,13 @@ func processCommands(args conf.Args, log *logger.Logger, cfg config) error {
 		fmt.Println("seed:       add data to the database")
 		fmt.Println("useradd:    add a new user to the database")
 		fmt.Println("users:      get a list of users from the database")
-		fmt.Println("genkey:     generate a set of private/public key files")
+		fmt.Println("export:     write every row of <users|products|summary> to stdout --format <csv|ndjson>")
+		fmt.Println("import:     add the products of a CSV file <products> <file.csv>")
+		fmt.Println("genkey:     generate a set of private/public key files <RS256|ES256|EdDSA>")
 		fmt.Println("gentoken:   generate a JWT for a user with claims")
 		fmt.Println("vault:      load private keys into vault system")
 		fmt.Println("vault-init: initialize a new vault instance")
+		fmt.Println("rotate-keys: mint and promote a new signing key <RS256|ES256|EdDSA> <grace>")
 		fmt.Println("provide a command to get more help.")
 		return commands.ErrHelp
 	}
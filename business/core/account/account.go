@@ -0,0 +1,328 @@
+// Package account provides the core business API for the flows users
+// complete through their email: resetting a forgotten password and verifying
+// their email address.
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for account operations.
+var (
+	ErrNotFound        = errors.New("token not found")
+	ErrInvalidToken    = errors.New("token not valid")
+	ErrTokenExpired    = errors.New("token expired")
+	ErrAlreadyVerified = errors.New("email already verified")
+)
+
+// Amount of time the emailed tokens can be used for.
+const (
+	ResetPasswordTTL = time.Hour
+	VerifyEmailTTL   = 24 * time.Hour
+)
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, tkn Token) error
+	Use(ctx context.Context, tkn Token) error
+	UseAll(ctx context.Context, userID uuid.UUID, purpose string, now time.Time) error
+	QueryByHash(ctx context.Context, hash string) (Token, error)
+}
+
+// Mailer interface declares the behavior this package needs to send email.
+type Mailer interface {
+	Send(ctx context.Context, to mail.Address, subject string, body string) error
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for account access.
+type Core struct {
+	log       *logger.Logger
+	evnCore   *event.Core
+	usrCore   *user.Core
+	sesCore   *session.Core
+	keyCore   *apikey.Core
+	mailer    Mailer
+	storer    Storer
+	resetURL  string
+	verifyURL string
+}
+
+// NewCore constructs a core for account api access.
+func NewCore(log *logger.Logger, evnCore *event.Core, usrCore *user.Core, sesCore *session.Core, keyCore *apikey.Core, mailer Mailer, storer Storer, options ...func(c *Core)) *Core {
+	c := Core{
+		log:     log,
+		evnCore: evnCore,
+		usrCore: usrCore,
+		sesCore: sesCore,
+		keyCore: keyCore,
+		mailer:  mailer,
+		storer:  storer,
+	}
+
+	for _, option := range options {
+		option(&c)
+	}
+
+	c.registerEventHandlers()
+
+	return &c
+}
+
+// WithLinks sets the pages of the frontend the emailed tokens are sent to.
+// The token is added to the links as the token query parameter. Without
+// links the email only contains the token.
+func WithLinks(resetURL string, verifyURL string) func(c *Core) {
+	return func(c *Core) {
+		c.resetURL = resetURL
+		c.verifyURL = verifyURL
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	evnCore, err := c.evnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	sesCore, err := c.sesCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyCore, err := c.keyCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:       c.log,
+		evnCore:   evnCore,
+		usrCore:   usrCore,
+		sesCore:   sesCore,
+		keyCore:   keyCore,
+		mailer:    c.mailer,
+		storer:    storer,
+		resetURL:  c.resetURL,
+		verifyURL: c.verifyURL,
+	}
+
+	return c, nil
+}
+
+// RequestPasswordReset records the request for a password reset token to be
+// emailed to the user with the email. The email is sent when the event is
+// delivered, so the call does the same work and returns no error whether or
+// not the email has an account.
+func (c *Core) RequestPasswordReset(ctx context.Context, email mail.Address) error {
+	if err := c.evnCore.SendEvent(ctx, passwordResetRequestedEvent(email)); err != nil {
+		return fmt.Errorf("sendevent: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password for the user the token was issued to.
+// Every other outstanding reset token of the user can no longer be used, the
+// sessions and api keys of the user are revoked and any lockout of the user
+// is lifted.
+func (c *Core) ResetPassword(ctx context.Context, raw string, password string) (user.User, error) {
+	tkn, err := c.use(ctx, raw, PurposeResetPassword)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	usr, err := c.usrCore.QueryByID(ctx, tkn.UserID)
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.querybyid: userID[%s]: %w", tkn.UserID, err)
+	}
+
+	usr, err = c.usrCore.Update(ctx, usr, user.UpdateUser{Password: &password})
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.update: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := c.storer.UseAll(ctx, usr.ID, PurposeResetPassword, time.Now()); err != nil {
+		return user.User{}, fmt.Errorf("useall: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := c.sesCore.RevokeByUserID(ctx, usr.ID); err != nil {
+		return user.User{}, fmt.Errorf("session.revokebyuserid: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := c.keyCore.RevokeByUserID(ctx, usr.ID); err != nil {
+		return user.User{}, fmt.Errorf("apikey.revokebyuserid: userID[%s]: %w", usr.ID, err)
+	}
+
+	usr, err = c.usrCore.Unlock(ctx, usr)
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.unlock: userID[%s]: %w", usr.ID, err)
+	}
+
+	return usr, nil
+}
+
+// RequestEmailVerification emails a verification token to the current email
+// of the user.
+func (c *Core) RequestEmailVerification(ctx context.Context, usr user.User) error {
+	if usr.EmailVerified {
+		return ErrAlreadyVerified
+	}
+
+	raw, err := c.issue(ctx, usr, PurposeVerifyEmail, VerifyEmailTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use the following to verify your email address within %s:\n\n%s", VerifyEmailTTL, link(c.verifyURL, raw))
+
+	if err := c.mailer.Send(ctx, usr.Email, "Verify your email address", body); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email of the user the token was issued to as
+// verified.
+func (c *Core) VerifyEmail(ctx context.Context, raw string) (user.User, error) {
+	tkn, err := c.use(ctx, raw, PurposeVerifyEmail)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	usr, err := c.usrCore.QueryByID(ctx, tkn.UserID)
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.querybyid: userID[%s]: %w", tkn.UserID, err)
+	}
+
+	// The email could have changed since the token was sent.
+	if tkn.Email != usr.Email.Address {
+		return user.User{}, ErrInvalidToken
+	}
+
+	usr, err = c.usrCore.VerifyEmail(ctx, usr)
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.verifyemail: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := c.storer.UseAll(ctx, usr.ID, PurposeVerifyEmail, time.Now()); err != nil {
+		return user.User{}, fmt.Errorf("useall: userID[%s]: %w", usr.ID, err)
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+func (c *Core) issue(ctx context.Context, usr user.User, purpose string, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+
+	tkn := Token{
+		ID:          uuid.New(),
+		UserID:      usr.ID,
+		Email:       usr.Email.Address,
+		Purpose:     purpose,
+		Hash:        hash(raw),
+		DateCreated: now,
+		DateExpires: now.Add(ttl),
+	}
+
+	if err := c.storer.Create(ctx, tkn); err != nil {
+		return "", fmt.Errorf("create: userID[%s]: %w", usr.ID, err)
+	}
+
+	return raw, nil
+}
+
+// use finds the token for the raw value and marks it as used. A token can
+// only be used once and only for the purpose it was issued for.
+func (c *Core) use(ctx context.Context, raw string, purpose string) (Token, error) {
+	tkn, err := c.storer.QueryByHash(ctx, hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Token{}, ErrInvalidToken
+		}
+		return Token{}, fmt.Errorf("querybyhash: %w", err)
+	}
+
+	now := time.Now()
+
+	if tkn.Purpose != purpose || tkn.Used() {
+		return Token{}, ErrInvalidToken
+	}
+
+	if now.After(tkn.DateExpires) {
+		return Token{}, ErrTokenExpired
+	}
+
+	tkn.DateUsed = now
+
+	if err := c.storer.Use(ctx, tkn); err != nil {
+		return Token{}, fmt.Errorf("use: %w", err)
+	}
+
+	return tkn, nil
+}
+
+func link(base string, raw string) string {
+	if base == "" {
+		return raw
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return raw
+	}
+
+	q := u.Query()
+	q.Set("token", raw)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
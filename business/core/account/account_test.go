@@ -0,0 +1,208 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/diegomagalhaes-dev/go-service/foundation/mailer"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Account(t *testing.T) {
+	t.Run("resetPassword", resetPassword)
+	t.Run("verifyEmail", verifyEmail)
+}
+
+// =============================================================================
+
+func resetPassword(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	if err := api.Account.RequestPasswordReset(ctx, mail.Address{Address: "unknown@example.com"}); err != nil {
+		t.Fatalf("Should NOT get an error for an unknown email : %s", err)
+	}
+
+	if _, err := api.Event.ProcessPending(ctx, event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver the events : %s", err)
+	}
+
+	if msgs := test.Mailer.Messages("unknown@example.com"); len(msgs) != 0 {
+		t.Fatalf("Should NOT send an email for an unknown email : %+v", msgs)
+	}
+
+	email := mail.Address{Address: "user@example.com"}
+
+	if err := api.Account.RequestPasswordReset(ctx, email); err != nil {
+		t.Fatalf("Should be able to request a password reset : %s", err)
+	}
+
+	if msgs := test.Mailer.Messages(email.Address); len(msgs) != 0 {
+		t.Fatalf("Should NOT send the email before the event is delivered : %+v", msgs)
+	}
+
+	if _, err := api.Event.ProcessPending(ctx, event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver the events : %s", err)
+	}
+
+	raw := lastToken(t, test.Mailer, email.Address)
+
+	usr, err := api.User.QueryByEmail(ctx, email)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the user : %s", err)
+	}
+
+	refresh, _, err := api.Session.Create(ctx, usr.ID, usr.TenantID, nil)
+	if err != nil {
+		t.Fatalf("Should be able to create a session : %s", err)
+	}
+
+	key, _, err := api.APIKey.Create(tenant.Set(ctx, usr.TenantID), apikey.NewAPIKey{
+		UserID:       usr.ID,
+		Name:         "Reset",
+		Roles:        []user.Role{user.RoleUser},
+		GrantorRoles: usr.Roles,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create an api key : %s", err)
+	}
+
+	if _, err := api.Account.VerifyEmail(ctx, raw); !errors.Is(err, account.ErrInvalidToken) {
+		t.Fatalf("Should NOT be able to use a reset token to verify an email : %v", err)
+	}
+
+	if _, err := api.Account.ResetPassword(ctx, raw, "new-gophers"); err != nil {
+		t.Fatalf("Should be able to reset the password : %s", err)
+	}
+
+	if _, err := api.Account.ResetPassword(ctx, raw, "other-gophers"); !errors.Is(err, account.ErrInvalidToken) {
+		t.Fatalf("Should NOT be able to use a reset token twice : %v", err)
+	}
+
+	if _, _, err := api.Session.Rotate(ctx, refresh); !errors.Is(err, session.ErrTokenReused) {
+		t.Fatalf("Should NOT be able to refresh a session created before the reset : %v", err)
+	}
+
+	if _, err := api.APIKey.Authenticate(ctx, key); !errors.Is(err, apikey.ErrKeyRevoked) {
+		t.Fatalf("Should NOT be able to use an api key created before the reset : %v", err)
+	}
+
+	if _, err := api.User.Authenticate(ctx, email, "new-gophers"); err != nil {
+		t.Fatalf("Should be able to authenticate with the new password : %s", err)
+	}
+
+	if _, err := api.User.Authenticate(ctx, email, "gophers"); !errors.Is(err, user.ErrAuthenticationFailure) {
+		t.Fatalf("Should NOT be able to authenticate with the old password : %v", err)
+	}
+}
+
+func verifyEmail(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	usr, err := api.User.QueryByEmail(ctx, mail.Address{Address: "user@example.com"})
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by email : %s", err)
+	}
+
+	if err := api.Account.RequestEmailVerification(ctx, usr); err != nil {
+		t.Fatalf("Should be able to request an email verification : %s", err)
+	}
+
+	raw := lastToken(t, test.Mailer, usr.Email.Address)
+
+	// -------------------------------------------------------------------------
+
+	email := mail.Address{Address: "user2@example.com"}
+	changed, err := api.User.Update(ctx, usr, user.UpdateUser{Email: &email})
+	if err != nil {
+		t.Fatalf("Should be able to update the email : %s", err)
+	}
+
+	if _, err := api.Account.VerifyEmail(ctx, raw); !errors.Is(err, account.ErrInvalidToken) {
+		t.Fatalf("Should NOT be able to verify an email that changed since the token was sent : %v", err)
+	}
+
+	if err := api.Account.RequestEmailVerification(ctx, changed); err != nil {
+		t.Fatalf("Should be able to request an email verification : %s", err)
+	}
+
+	raw = lastToken(t, test.Mailer, email.Address)
+
+	verified, err := api.Account.VerifyEmail(ctx, raw)
+	if err != nil {
+		t.Fatalf("Should be able to verify the email : %s", err)
+	}
+
+	if !verified.EmailVerified {
+		t.Fatalf("Should get back the user with a verified email : %+v", verified)
+	}
+
+	if err := api.Account.RequestEmailVerification(ctx, verified); !errors.Is(err, account.ErrAlreadyVerified) {
+		t.Fatalf("Should NOT be able to verify an email twice : %v", err)
+	}
+}
+
+// lastToken returns the token of the last email sent to the address.
+func lastToken(t *testing.T, m *mailer.Memory, to string) string {
+	msgs := m.Messages(to)
+	if len(msgs) == 0 {
+		t.Fatalf("Should have sent an email to %s", to)
+	}
+
+	parts := strings.Split(msgs[len(msgs)-1].Body, "\n\n")
+	if len(parts) < 2 {
+		t.Fatalf("Should get the token in the email : %s", msgs[len(msgs)-1].Body)
+	}
+
+	return parts[1]
+}
@@ -0,0 +1,109 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+)
+
+// EventSource represents the source of the given event.
+const EventSource = "account"
+
+// Set of account related events.
+const (
+	EventPasswordResetRequested = "PasswordResetRequested"
+)
+
+// =============================================================================
+
+// EventParamsPasswordResetRequested is the event parameters for the password
+// reset requested event.
+type EventParamsPasswordResetRequested struct {
+	Email string
+}
+
+// String returns a string representation of the event parameters.
+func (p *EventParamsPasswordResetRequested) String() string {
+	return fmt.Sprintf("&EventParamsPasswordResetRequested{Email:%v}", p.Email)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (p *EventParamsPasswordResetRequested) Marshal() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// UnmarshalPasswordResetRequested parses the event parameters from JSON.
+func UnmarshalPasswordResetRequested(rawParams []byte) (*EventParamsPasswordResetRequested, error) {
+	var params EventParamsPasswordResetRequested
+	err := json.Unmarshal(rawParams, &params)
+	if err != nil {
+		return nil, fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	return &params, nil
+}
+
+// passwordResetRequestedEvent constructs an event for when a password reset
+// is requested for an email.
+func passwordResetRequestedEvent(email mail.Address) event.Event {
+	params := EventParamsPasswordResetRequested{
+		Email: email.Address,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return event.Event{
+		Source:    EventSource,
+		Type:      EventPasswordResetRequested,
+		RawParams: rawParams,
+	}
+}
+
+// =============================================================================
+
+func (c *Core) registerEventHandlers() {
+	c.evnCore.AddHandler(EventSource, EventPasswordResetRequested, c.handlePasswordResetRequestedEvent)
+}
+
+// handlePasswordResetRequestedEvent emails a password reset token to the user
+// with the email of the event. Nothing is sent for an unknown email or a
+// disabled user. Every delivery of the event sends a new token.
+func (c *Core) handlePasswordResetRequestedEvent(ctx context.Context, ev event.Event) error {
+	params, err := UnmarshalPasswordResetRequested(ev.RawParams)
+	if err != nil {
+		return err
+	}
+
+	usr, err := c.usrCore.QueryByEmail(ctx, mail.Address{Address: params.Email})
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("user.querybyemail: %w", err)
+	}
+
+	if !usr.Enabled {
+		return nil
+	}
+
+	raw, err := c.issue(ctx, usr, PurposeResetPassword, ResetPasswordTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("A password reset was requested for your account. Use the following to choose a new password within %s:\n\n%s\n\nIf you didn't request it, you can ignore this email.", ResetPasswordTTL, link(c.resetURL, raw))
+
+	if err := c.mailer.Send(ctx, usr.Email, "Reset your password", body); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package account
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of purposes a token can be issued for.
+const (
+	PurposeResetPassword = "reset_password"
+	PurposeVerifyEmail   = "verify_email"
+)
+
+// Token represents a single use token that was emailed to a user. Only the
+// hash of the token is kept, the raw value is only part of the email. Email
+// is the address the token was sent to.
+type Token struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Email       string
+	Purpose     string
+	Hash        string
+	DateCreated time.Time
+	DateExpires time.Time
+	DateUsed    time.Time
+}
+
+// Used reports whether the token was already used.
+func (t Token) Used() bool {
+	return !t.DateUsed.IsZero()
+}
@@ -0,0 +1,142 @@
+// Package accountdb contains account token related CRUD functionality.
+package accountdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for account token database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (account.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds a token to the database.
+func (s *Store) Create(ctx context.Context, tkn account.Token) error {
+	const q = `
+	INSERT INTO user_tokens
+		(token_id, user_id, email, purpose, token_hash, date_created, date_expires, date_used)
+	VALUES
+		(:token_id, :user_id, :email, :purpose, :token_hash, :date_created, :date_expires, :date_used)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBToken(tkn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks a token as used. The update only succeeds for a token that is not
+// used yet, so a token can't be used twice. The loser of a race gets
+// account.ErrInvalidToken.
+func (s *Store) Use(ctx context.Context, tkn account.Token) error {
+	const q = `
+	UPDATE
+		user_tokens
+	SET
+		"date_used" = :date_used
+	WHERE
+		token_id = :token_id AND date_used IS NULL
+	RETURNING
+		token_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"token_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, toDBToken(tkn), &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", account.ErrInvalidToken)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// UseAll marks every unused token of a user for the purpose as used.
+func (s *Store) UseAll(ctx context.Context, userID uuid.UUID, purpose string, now time.Time) error {
+	data := struct {
+		UserID   uuid.UUID `db:"user_id"`
+		Purpose  string    `db:"purpose"`
+		DateUsed time.Time `db:"date_used"`
+	}{
+		UserID:   userID,
+		Purpose:  purpose,
+		DateUsed: now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		user_tokens
+	SET
+		"date_used" = :date_used
+	WHERE
+		user_id = :user_id AND purpose = :purpose AND date_used IS NULL`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByHash finds the token with the specified hash.
+func (s *Store) QueryByHash(ctx context.Context, hash string) (account.Token, error) {
+	data := struct {
+		Hash string `db:"token_hash"`
+	}{
+		Hash: hash,
+	}
+
+	const q = `
+	SELECT
+		token_id, user_id, email, purpose, token_hash, date_created, date_expires, date_used
+	FROM
+		user_tokens
+	WHERE
+		token_hash = :token_hash`
+
+	var dbTkn dbToken
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTkn); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return account.Token{}, fmt.Errorf("namedquerystruct: %w", account.ErrNotFound)
+		}
+		return account.Token{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreToken(dbTkn), nil
+}
@@ -0,0 +1,55 @@
+package accountdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/google/uuid"
+)
+
+// dbToken represents a stored emailed token.
+type dbToken struct {
+	ID          uuid.UUID    `db:"token_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	Email       string       `db:"email"`
+	Purpose     string       `db:"purpose"`
+	Hash        string       `db:"token_hash"`
+	DateCreated time.Time    `db:"date_created"`
+	DateExpires time.Time    `db:"date_expires"`
+	DateUsed    sql.NullTime `db:"date_used"`
+}
+
+func toDBToken(tkn account.Token) dbToken {
+	return dbToken{
+		ID:          tkn.ID,
+		UserID:      tkn.UserID,
+		Email:       tkn.Email,
+		Purpose:     tkn.Purpose,
+		Hash:        tkn.Hash,
+		DateCreated: tkn.DateCreated.UTC(),
+		DateExpires: tkn.DateExpires.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  tkn.DateUsed.UTC(),
+			Valid: !tkn.DateUsed.IsZero(),
+		},
+	}
+}
+
+func toCoreToken(dbTkn dbToken) account.Token {
+	tkn := account.Token{
+		ID:          dbTkn.ID,
+		UserID:      dbTkn.UserID,
+		Email:       dbTkn.Email,
+		Purpose:     dbTkn.Purpose,
+		Hash:        dbTkn.Hash,
+		DateCreated: dbTkn.DateCreated.In(time.Local),
+		DateExpires: dbTkn.DateExpires.In(time.Local),
+	}
+
+	if dbTkn.DateUsed.Valid {
+		tkn.DateUsed = dbTkn.DateUsed.Time.In(time.Local)
+	}
+
+	return tkn
+}
@@ -0,0 +1,268 @@
+// Package apikey provides the core business API for managing the API keys
+// machine clients use to authenticate.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound    = errors.New("api key not found")
+	ErrInvalidKey  = errors.New("api key not valid")
+	ErrKeyExpired  = errors.New("api key expired")
+	ErrKeyRevoked  = errors.New("api key revoked")
+	ErrInvalidRole = errors.New("role not allowed for the api key")
+)
+
+// The raw key is formed as keyScheme + prefix + "_" + secret.
+const (
+	keyScheme = "sk_"
+	prefixLen = 12
+)
+
+// lastUsedInterval limits how often the last used date of a key is written,
+// so a busy client doesn't cause a write on every request.
+const lastUsedInterval = time.Minute
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, key APIKey) error
+	Update(ctx context.Context, key APIKey) error
+	UpdateLastUsed(ctx context.Context, key APIKey) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID, now time.Time) error
+	QueryByID(ctx context.Context, keyID uuid.UUID) (APIKey, error)
+	QueryByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for api key access.
+type Core struct {
+	log     *logger.Logger
+	usrCore *user.Core
+	storer  Storer
+}
+
+// NewCore constructs a core for api key api access.
+func NewCore(log *logger.Logger, usrCore *user.Core, storer Storer) *Core {
+	return &Core{
+		log:     log,
+		usrCore: usrCore,
+		storer:  storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:     c.log,
+		usrCore: usrCore,
+		storer:  storer,
+	}
+
+	return c, nil
+}
+
+// Create adds a new api key for the user, in the tenant of the user. The raw
+// key is returned and is not stored anywhere.
+func (c *Core) Create(ctx context.Context, nk NewAPIKey) (string, APIKey, error) {
+	usr, err := c.usrCore.QueryByID(ctx, nk.UserID)
+	if err != nil {
+		return "", APIKey{}, fmt.Errorf("user.querybyid: %s: %w", nk.UserID, err)
+	}
+
+	if len(nk.Roles) == 0 {
+		return "", APIKey{}, ErrInvalidRole
+	}
+
+	for _, role := range nk.Roles {
+		if !hasRole(usr.Roles, role) || !hasRole(nk.GrantorRoles, role) {
+			return "", APIKey{}, fmt.Errorf("role[%s]: %w", role.Name(), ErrInvalidRole)
+		}
+	}
+
+	prefix, err := randomString(prefixLen / 2)
+	if err != nil {
+		return "", APIKey{}, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", APIKey{}, fmt.Errorf("generating secret: %w", err)
+	}
+
+	raw := keyScheme + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := APIKey{
+		ID:          uuid.New(),
+		TenantID:    usr.TenantID,
+		UserID:      nk.UserID,
+		Name:        nk.Name,
+		Prefix:      prefix,
+		Hash:        hash(raw),
+		Roles:       nk.Roles,
+		DateCreated: time.Now(),
+		DateExpires: nk.DateExpires,
+	}
+
+	if err := c.storer.Create(ctx, key); err != nil {
+		return "", APIKey{}, fmt.Errorf("create: %w", err)
+	}
+
+	return raw, key, nil
+}
+
+// Revoke revokes the api key so it can't be used anymore. Revoking a key that
+// is already revoked is not an error.
+func (c *Core) Revoke(ctx context.Context, key APIKey) (APIKey, error) {
+	if key.Revoked() {
+		return key, nil
+	}
+
+	key.DateRevoked = time.Now()
+
+	if err := c.storer.Update(ctx, key); err != nil {
+		return APIKey{}, fmt.Errorf("update: %w", err)
+	}
+
+	return key, nil
+}
+
+// RevokeByUserID revokes every api key of the user.
+func (c *Core) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := c.storer.RevokeByUserID(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("revokebyuserid: userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// QueryByID finds the api key by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, keyID uuid.UUID) (APIKey, error) {
+	key, err := c.storer.QueryByID(ctx, keyID)
+	if err != nil {
+		return APIKey{}, fmt.Errorf("query: keyID[%s]: %w", keyID, err)
+	}
+
+	return key, nil
+}
+
+// QueryByUserID finds the api keys of the specified user.
+func (c *Core) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	keys, err := c.storer.QueryByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", userID, err)
+	}
+
+	return keys, nil
+}
+
+// Authenticate finds the api key for the raw key and validates it can still
+// be used. The last used date of the key is recorded.
+func (c *Core) Authenticate(ctx context.Context, raw string) (APIKey, error) {
+	prefix, ok := parsePrefix(raw)
+	if !ok {
+		return APIKey{}, ErrInvalidKey
+	}
+
+	key, err := c.storer.QueryByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return APIKey{}, ErrInvalidKey
+		}
+		return APIKey{}, fmt.Errorf("querybyprefix: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash(raw)), []byte(key.Hash)) != 1 {
+		return APIKey{}, ErrInvalidKey
+	}
+
+	now := time.Now()
+
+	if key.Revoked() {
+		return APIKey{}, ErrKeyRevoked
+	}
+
+	if key.Expired(now) {
+		return APIKey{}, ErrKeyExpired
+	}
+
+	if now.Sub(key.DateLastUsed) >= lastUsedInterval {
+		key.DateLastUsed = now
+
+		if err := c.storer.UpdateLastUsed(ctx, key); err != nil {
+			if errors.Is(err, ErrKeyRevoked) {
+				return APIKey{}, ErrKeyRevoked
+			}
+			return APIKey{}, fmt.Errorf("updatelastused: %w", err)
+		}
+	}
+
+	return key, nil
+}
+
+// =============================================================================
+
+func parsePrefix(raw string) (string, bool) {
+	rest, ok := strings.CutPrefix(raw, keyScheme)
+	if !ok || len(rest) <= prefixLen+1 || rest[prefixLen] != '_' {
+		return "", false
+	}
+
+	return rest[:prefixLen], true
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating prefix: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hasRole(roles []user.Role, role user.Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
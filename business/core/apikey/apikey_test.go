@@ -0,0 +1,161 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_APIKey(t *testing.T) {
+	t.Run("crud", crud)
+	t.Run("roles", roles)
+}
+
+// =============================================================================
+
+func crud(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	nk := apikey.NewAPIKey{
+		UserID:       userID,
+		Name:         "Integration",
+		Roles:        []user.Role{user.RoleUser},
+		GrantorRoles: []user.Role{user.RoleUser},
+	}
+
+	raw, key, err := api.APIKey.Create(ctx, nk)
+	if err != nil {
+		t.Fatalf("Should be able to create an api key : %s", err)
+	}
+
+	if key.Hash == raw || key.Prefix == "" {
+		t.Fatalf("Should only store the hash and prefix of the key : %+v", key)
+	}
+
+	got, err := api.APIKey.Authenticate(ctx, raw)
+	if err != nil {
+		t.Fatalf("Should be able to authenticate with the api key : %s", err)
+	}
+
+	if got.ID != key.ID || got.DateLastUsed.IsZero() {
+		t.Fatalf("Should get back the key with the last used date : %+v", got)
+	}
+
+	if _, err := api.APIKey.Authenticate(ctx, raw+"x"); !errors.Is(err, apikey.ErrInvalidKey) {
+		t.Fatalf("Should NOT be able to authenticate with a tampered key : %v", err)
+	}
+
+	keys, err := api.APIKey.QueryByUserID(ctx, userID)
+	if err != nil {
+		t.Fatalf("Should be able to query the api keys of the user : %s", err)
+	}
+
+	if len(keys) != 1 || keys[0].ID != key.ID {
+		t.Fatalf("Should get back the key of the user : %+v", keys)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if _, err := api.APIKey.Revoke(ctx, key); err != nil {
+		t.Fatalf("Should be able to revoke the api key : %s", err)
+	}
+
+	if _, err := api.APIKey.Authenticate(ctx, raw); !errors.Is(err, apikey.ErrKeyRevoked) {
+		t.Fatalf("Should NOT be able to authenticate with a revoked key : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	nk.DateExpires = time.Now().Add(-time.Minute)
+
+	raw, _, err = api.APIKey.Create(ctx, nk)
+	if err != nil {
+		t.Fatalf("Should be able to create an api key : %s", err)
+	}
+
+	if _, err := api.APIKey.Authenticate(ctx, raw); !errors.Is(err, apikey.ErrKeyExpired) {
+		t.Fatalf("Should NOT be able to authenticate with an expired key : %v", err)
+	}
+}
+
+func roles(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	nk := apikey.NewAPIKey{
+		UserID:       uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f"),
+		Name:         "Escalation",
+		Roles:        []user.Role{user.RoleAdmin},
+		GrantorRoles: []user.Role{user.RoleAdmin},
+	}
+
+	if _, _, err := api.APIKey.Create(ctx, nk); !errors.Is(err, apikey.ErrInvalidRole) {
+		t.Fatalf("Should NOT be able to create a key with a role the user doesn't have : %v", err)
+	}
+
+	nk.Roles = nil
+
+	if _, _, err := api.APIKey.Create(ctx, nk); !errors.Is(err, apikey.ErrInvalidRole) {
+		t.Fatalf("Should NOT be able to create a key without roles : %v", err)
+	}
+
+	nk = apikey.NewAPIKey{
+		UserID:       uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7"),
+		Name:         "Escalation",
+		Roles:        []user.Role{user.RoleAdmin},
+		GrantorRoles: []user.Role{user.RoleUser},
+	}
+
+	if _, _, err := api.APIKey.Create(ctx, nk); !errors.Is(err, apikey.ErrInvalidRole) {
+		t.Fatalf("Should NOT be able to create a key with a role the caller doesn't act with : %v", err)
+	}
+}
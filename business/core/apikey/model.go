@@ -0,0 +1,49 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/google/uuid"
+)
+
+// APIKey represents a key a machine client uses to call the API on behalf of
+// a user. Only the hash of the key is kept, the raw value is handed to the
+// client once when it's created. The prefix is stored in the clear so the key
+// can be found without scanning every hash.
+type APIKey struct {
+	ID           uuid.UUID
+	TenantID     uuid.UUID
+	UserID       uuid.UUID
+	Name         string
+	Prefix       string
+	Hash         string
+	Roles        []user.Role
+	DateCreated  time.Time
+	DateExpires  time.Time
+	DateLastUsed time.Time
+	DateRevoked  time.Time
+}
+
+// Revoked reports whether the key has been revoked.
+func (k APIKey) Revoked() bool {
+	return !k.DateRevoked.IsZero()
+}
+
+// Expired reports whether the key is past its expiration date. Keys without
+// an expiration date never expire.
+func (k APIKey) Expired(now time.Time) bool {
+	return !k.DateExpires.IsZero() && now.After(k.DateExpires)
+}
+
+// NewAPIKey is what we require from clients when adding an APIKey. The roles
+// must be a subset of the roles of the user the key belongs to and of the
+// grantor roles, the roles the caller creating the key acts with, so a caller
+// can't hand a key more than it holds itself.
+type NewAPIKey struct {
+	UserID       uuid.UUID
+	Name         string
+	Roles        []user.Role
+	GrantorRoles []user.Role
+	DateExpires  time.Time
+}
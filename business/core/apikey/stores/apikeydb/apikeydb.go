@@ -0,0 +1,248 @@
+// Package apikeydb contains api key related CRUD functionality.
+package apikeydb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for api key database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (apikey.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds an api key to the database.
+func (s *Store) Create(ctx context.Context, key apikey.APIKey) error {
+	const q = `
+	INSERT INTO api_keys
+		(key_id, tenant_id, user_id, name, prefix, key_hash, roles, date_created, date_expires, date_last_used, date_revoked)
+	VALUES
+		(:key_id, :tenant_id, :user_id, :name, :prefix, :key_hash, :roles, :date_created, :date_expires, :date_last_used, :date_revoked)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBAPIKey(key)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the usage and revocation dates of an api key.
+func (s *Store) Update(ctx context.Context, key apikey.APIKey) error {
+	const q = `
+	UPDATE
+		api_keys
+	SET
+		"date_last_used" = :date_last_used,
+		"date_revoked" = :date_revoked
+	WHERE
+		key_id = :key_id AND tenant_id = :tenant_id`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBAPIKey(key)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records when an api key was last used. Only the last used
+// date is written and only for a key that is not revoked, so a key revoked
+// while it's being used stays revoked. Otherwise apikey.ErrKeyRevoked is
+// returned.
+func (s *Store) UpdateLastUsed(ctx context.Context, key apikey.APIKey) error {
+	const q = `
+	UPDATE
+		api_keys
+	SET
+		"date_last_used" = :date_last_used
+	WHERE
+		key_id = :key_id AND tenant_id = :tenant_id AND date_revoked IS NULL`
+
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, toDBAPIKey(key))
+	if err != nil {
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", apikey.ErrKeyRevoked)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every api key of the user that is not revoked yet.
+func (s *Store) RevokeByUserID(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	data := map[string]interface{}{
+		"user_id":      userID.String(),
+		"date_revoked": now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		api_keys
+	SET
+		"date_revoked" = :date_revoked`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id", "date_revoked IS NULL")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByID gets the specified api key from the database.
+func (s *Store) QueryByID(ctx context.Context, keyID uuid.UUID) (apikey.APIKey, error) {
+	data := map[string]interface{}{
+		"key_id": keyID.String(),
+	}
+
+	const q = `
+	SELECT
+		key_id, tenant_id, user_id, name, prefix, key_hash, roles, date_created, date_expires, date_last_used, date_revoked
+	FROM
+		api_keys`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "key_id = :key_id")
+	if err != nil {
+		return apikey.APIKey{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbKey dbAPIKey
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbKey); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return apikey.APIKey{}, fmt.Errorf("namedquerystruct: %w", apikey.ErrNotFound)
+		}
+		return apikey.APIKey{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreAPIKey(dbKey)
+}
+
+// QueryByPrefix gets the api key with the specified prefix from the database.
+func (s *Store) QueryByPrefix(ctx context.Context, prefix string) (apikey.APIKey, error) {
+	data := map[string]interface{}{
+		"prefix": prefix,
+	}
+
+	const q = `
+	SELECT
+		key_id, tenant_id, user_id, name, prefix, key_hash, roles, date_created, date_expires, date_last_used, date_revoked
+	FROM
+		api_keys`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "prefix = :prefix")
+	if err != nil {
+		return apikey.APIKey{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbKey dbAPIKey
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbKey); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return apikey.APIKey{}, fmt.Errorf("namedquerystruct: %w", apikey.ErrNotFound)
+		}
+		return apikey.APIKey{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreAPIKey(dbKey)
+}
+
+// QueryByUserID gets the api keys of the specified user from the database.
+func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]apikey.APIKey, error) {
+	data := map[string]interface{}{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	SELECT
+		key_id, tenant_id, user_id, name, prefix, key_hash, roles, date_created, date_expires, date_last_used, date_revoked
+	FROM
+		api_keys`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" ORDER BY date_created")
+
+	var dbKeys []dbAPIKey
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbKeys); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreAPIKeySlice(dbKeys)
+}
+
+// =============================================================================
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
@@ -0,0 +1,104 @@
+package apikeydb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	"github.com/google/uuid"
+)
+
+// dbAPIKey represents a stored api key.
+type dbAPIKey struct {
+	ID           uuid.UUID      `db:"key_id"`
+	TenantID     uuid.UUID      `db:"tenant_id"`
+	UserID       uuid.UUID      `db:"user_id"`
+	Name         string         `db:"name"`
+	Prefix       string         `db:"prefix"`
+	Hash         string         `db:"key_hash"`
+	Roles        dbarray.String `db:"roles"`
+	DateCreated  time.Time      `db:"date_created"`
+	DateExpires  sql.NullTime   `db:"date_expires"`
+	DateLastUsed sql.NullTime   `db:"date_last_used"`
+	DateRevoked  sql.NullTime   `db:"date_revoked"`
+}
+
+func toDBAPIKey(key apikey.APIKey) dbAPIKey {
+	roles := make([]string, len(key.Roles))
+	for i, role := range key.Roles {
+		roles[i] = role.Name()
+	}
+
+	return dbAPIKey{
+		ID:           key.ID,
+		TenantID:     key.TenantID,
+		UserID:       key.UserID,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		Hash:         key.Hash,
+		Roles:        roles,
+		DateCreated:  key.DateCreated.UTC(),
+		DateExpires:  toNullTime(key.DateExpires),
+		DateLastUsed: toNullTime(key.DateLastUsed),
+		DateRevoked:  toNullTime(key.DateRevoked),
+	}
+}
+
+func toCoreAPIKey(dbKey dbAPIKey) (apikey.APIKey, error) {
+	roles := make([]user.Role, len(dbKey.Roles))
+	for i, value := range dbKey.Roles {
+		var err error
+		roles[i], err = user.ParseRole(value)
+		if err != nil {
+			return apikey.APIKey{}, fmt.Errorf("parse role: %w", err)
+		}
+	}
+
+	key := apikey.APIKey{
+		ID:          dbKey.ID,
+		TenantID:    dbKey.TenantID,
+		UserID:      dbKey.UserID,
+		Name:        dbKey.Name,
+		Prefix:      dbKey.Prefix,
+		Hash:        dbKey.Hash,
+		Roles:       roles,
+		DateCreated: dbKey.DateCreated.In(time.Local),
+	}
+
+	if dbKey.DateExpires.Valid {
+		key.DateExpires = dbKey.DateExpires.Time.In(time.Local)
+	}
+
+	if dbKey.DateLastUsed.Valid {
+		key.DateLastUsed = dbKey.DateLastUsed.Time.In(time.Local)
+	}
+
+	if dbKey.DateRevoked.Valid {
+		key.DateRevoked = dbKey.DateRevoked.Time.In(time.Local)
+	}
+
+	return key, nil
+}
+
+func toCoreAPIKeySlice(dbKeys []dbAPIKey) ([]apikey.APIKey, error) {
+	keys := make([]apikey.APIKey, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		var err error
+		keys[i], err = toCoreAPIKey(dbKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func toNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{
+		Time:  t.UTC(),
+		Valid: !t.IsZero(),
+	}
+}
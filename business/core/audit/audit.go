@@ -0,0 +1,146 @@
+// Package audit provides business access to the audit log of the system.
+//
+// Every mutation of an entity is recorded with the actor that performed it,
+// the trace of the request and the fields that changed. Like events, entries
+// are written using the transaction found in the context, if any, so an entry
+// is only ever stored when the change it describes is committed.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// ActorSystem is recorded as the actor of the mutations that are not performed
+// on behalf of an authenticated user, like the ones made by the tooling.
+const ActorSystem = "system"
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, entry Entry) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Entry, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+}
+
+// WithActor sets the function used to identify who performs a mutation from
+// the context. The business layer knows nothing about the claims of a request
+// so the application provides it.
+func WithActor(actor func(ctx context.Context) string) func(c *Core) {
+	return func(c *Core) {
+		c.actor = actor
+	}
+}
+
+// WithTraceID sets the function used to retrieve the trace id of the request
+// performing a mutation from the context.
+func WithTraceID(traceID func(ctx context.Context) string) func(c *Core) {
+	return func(c *Core) {
+		c.traceID = traceID
+	}
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for audit access.
+type Core struct {
+	log     *logger.Logger
+	storer  Storer
+	actor   func(ctx context.Context) string
+	traceID func(ctx context.Context) string
+}
+
+// NewCore constructs a core for audit api access.
+func NewCore(log *logger.Logger, storer Storer, options ...func(c *Core)) *Core {
+	c := Core{
+		log:     log,
+		storer:  storer,
+		actor:   func(context.Context) string { return "" },
+		traceID: func(context.Context) string { return "" },
+	}
+
+	for _, option := range options {
+		option(&c)
+	}
+
+	return &c
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store-related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:     c.log,
+		storer:  storer,
+		actor:   c.actor,
+		traceID: c.traceID,
+	}
+
+	return c, nil
+}
+
+// Record adds an entry for the mutation to the audit log. If the context
+// carries a transaction, the entry is written as part of that transaction.
+func (c *Core) Record(ctx context.Context, ne NewEntry) error {
+	diff, err := NewDiff(ne.Before, ne.After)
+	if err != nil {
+		return fmt.Errorf("newdiff: %w", err)
+	}
+
+	storer := c.storer
+	if tx, ok := transaction.Get(ctx); ok {
+		if storer, err = storer.ExecuteUnderTransaction(tx); err != nil {
+			return fmt.Errorf("executeundertransaction: %w", err)
+		}
+	}
+
+	actor := c.actor(ctx)
+	if actor == "" {
+		actor = ActorSystem
+	}
+
+	entry := Entry{
+		ID:          uuid.New(),
+		TenantID:    ne.TenantID,
+		Actor:       actor,
+		TraceID:     c.traceID(ctx),
+		Entity:      ne.Entity,
+		EntityID:    ne.EntityID,
+		Action:      ne.Action,
+		Diff:        diff,
+		DateCreated: time.Now(),
+	}
+
+	if err := storer.Create(ctx, entry); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing entries.
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Entry, error) {
+	entries, err := c.storer.Query(ctx, filter, orderBy, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Count returns the total number of entries.
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.storer.Count(ctx, filter)
+}
@@ -0,0 +1,190 @@
+package audit_test
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Diff(t *testing.T) {
+	type entity struct {
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+	}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		exp    audit.Diff
+	}{
+		{
+			name:   "create",
+			before: nil,
+			after:  entity{Name: "Comic Books", Quantity: 10},
+			exp: audit.Diff{
+				"name":     {After: "Comic Books"},
+				"quantity": {After: float64(10)},
+			},
+		},
+		{
+			name:   "update",
+			before: entity{Name: "Comic Books", Quantity: 10},
+			after:  entity{Name: "Comic Books", Quantity: 5},
+			exp: audit.Diff{
+				"quantity": {Before: float64(10), After: float64(5)},
+			},
+		},
+		{
+			name:   "delete",
+			before: entity{Name: "Comic Books", Quantity: 10},
+			after:  nil,
+			exp: audit.Diff{
+				"name":     {Before: "Comic Books"},
+				"quantity": {Before: float64(10)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := audit.NewDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("Should be able to build the diff : %s", err)
+			}
+
+			if d := cmp.Diff(tt.exp, diff); d != "" {
+				t.Fatalf("Should get back the changed fields : %s", d)
+			}
+		})
+	}
+}
+
+func Test_Audit(t *testing.T) {
+	t.Run("record", record)
+}
+
+// =============================================================================
+
+func record(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	usr, err := api.User.Create(ctx, user.NewUser{
+		Name:            "Audit Gopher",
+		Email:           mail.Address{Address: "audit@example.com"},
+		Roles:           []user.Role{user.RoleUser},
+		Password:        "gophers",
+		PasswordConfirm: "gophers",
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a user : %s", err)
+	}
+
+	prd, err := api.Product.Create(ctx, product.NewProduct{
+		UserID:   usr.ID,
+		Name:     "Comic Books",
+		Cost:     10,
+		Quantity: 5,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a product : %s", err)
+	}
+
+	quantity := 2
+	prd, err = api.Product.Update(ctx, prd, product.UpdateProduct{Quantity: &quantity})
+	if err != nil {
+		t.Fatalf("Should be able to update the product : %s", err)
+	}
+
+	if err := api.Product.Delete(ctx, prd); err != nil {
+		t.Fatalf("Should be able to delete the product : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	var filter audit.QueryFilter
+	filter.WithEntityID(prd.ID)
+
+	entries, err := api.Audit.Query(ctx, filter, audit.DefaultOrderBy, 1, 10)
+	if err != nil {
+		t.Fatalf("Should be able to query the audit log : %s", err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("Should get an entry for every mutation of the product : got %d", len(entries))
+	}
+
+	actions := []string{entries[2].Action, entries[1].Action, entries[0].Action}
+	if d := cmp.Diff([]string{audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete}, actions); d != "" {
+		t.Fatalf("Should get the mutations in order : %s", d)
+	}
+
+	upd := entries[1]
+
+	if upd.Entity != product.AuditEntity || upd.Actor != audit.ActorSystem || upd.TenantID != prd.TenantID {
+		t.Fatalf("Should record the entity, actor and tenant : %+v", upd)
+	}
+
+	exp := audit.Diff{
+		"quantity": {Before: float64(5), After: float64(2)},
+	}
+	if d := cmp.Diff(exp, upd.Diff); d != "" {
+		t.Fatalf("Should only record the changed fields : %s", d)
+	}
+
+	// -------------------------------------------------------------------------
+
+	filter = audit.QueryFilter{}
+	filter.WithEntity(user.AuditEntity)
+	filter.WithEntityID(usr.ID)
+
+	entries, err = api.Audit.Query(ctx, filter, audit.DefaultOrderBy, 1, 10)
+	if err != nil {
+		t.Fatalf("Should be able to query the audit log : %s", err)
+	}
+
+	if len(entries) != 1 || entries[0].Action != audit.ActionCreate {
+		t.Fatalf("Should get the creation of the user : %+v", entries)
+	}
+
+	if _, exists := entries[0].Diff["passwordHash"]; exists {
+		t.Fatalf("Should NOT record the password hash")
+	}
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Change holds the value of a field before and after a mutation.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Diff maps the name of every field that changed to its change.
+type Diff map[string]Change
+
+// NewDiff compares the JSON representation of the two values and returns the
+// fields that differ. A nil value is treated as an entity without fields.
+func NewDiff(before any, after any) (Diff, error) {
+	b, err := toFields(before)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+
+	a, err := toFields(after)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+
+	diff := Diff{}
+
+	for k, bv := range b {
+		if av, exists := a[k]; !exists || !reflect.DeepEqual(bv, av) {
+			diff[k] = Change{Before: bv, After: a[k]}
+		}
+	}
+
+	for k, av := range a {
+		if _, exists := b[k]; !exists {
+			diff[k] = Change{After: av}
+		}
+	}
+
+	return diff, nil
+}
+
+func toFields(v any) (map[string]any, error) {
+	if v == nil {
+		return map[string]any{}, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return fields, nil
+}
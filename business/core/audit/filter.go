@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+	"github.com/google/uuid"
+)
+
+// QueryFilter holds the available fields a query can be filtered on.
+type QueryFilter struct {
+	Entity           *string    `validate:"omitempty"`
+	EntityID         *uuid.UUID `validate:"omitempty"`
+	Action           *string    `validate:"omitempty,oneof=create update delete"`
+	Actor            *string    `validate:"omitempty"`
+	TraceID          *string    `validate:"omitempty"`
+	StartCreatedDate *time.Time `validate:"omitempty"`
+	EndCreatedDate   *time.Time `validate:"omitempty"`
+}
+
+// Validate checks the data in the model is considered clean.
+func (qf *QueryFilter) Validate() error {
+	if err := validate.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+	return nil
+}
+
+// WithEntity sets the Entity field of the QueryFilter value.
+func (qf *QueryFilter) WithEntity(entity string) {
+	qf.Entity = &entity
+}
+
+// WithEntityID sets the EntityID field of the QueryFilter value.
+func (qf *QueryFilter) WithEntityID(entityID uuid.UUID) {
+	qf.EntityID = &entityID
+}
+
+// WithAction sets the Action field of the QueryFilter value.
+func (qf *QueryFilter) WithAction(action string) {
+	qf.Action = &action
+}
+
+// WithActor sets the Actor field of the QueryFilter value.
+func (qf *QueryFilter) WithActor(actor string) {
+	qf.Actor = &actor
+}
+
+// WithTraceID sets the TraceID field of the QueryFilter value.
+func (qf *QueryFilter) WithTraceID(traceID string) {
+	qf.TraceID = &traceID
+}
+
+// WithStartDateCreated sets the StartCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithStartDateCreated(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartCreatedDate = &d
+}
+
+// WithEndCreatedDate sets the EndCreatedDate field of the QueryFilter value.
+func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndCreatedDate = &d
+}
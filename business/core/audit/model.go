@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Set of actions recorded in the audit log.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Entry represents a mutation recorded in the audit log.
+type Entry struct {
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	Actor       string
+	TraceID     string
+	Entity      string
+	EntityID    uuid.UUID
+	Action      string
+	Diff        Diff
+	DateCreated time.Time
+}
+
+// NewEntry is what we require to record a mutation. Before and After hold
+// the state of the entity around the mutation and are nil when the entity
+// did not exist.
+type NewEntry struct {
+	TenantID uuid.UUID
+	Entity   string
+	EntityID uuid.UUID
+	Action   string
+	Before   any
+	After    any
+}
@@ -0,0 +1,16 @@
+package audit
+
+import "github.com/diegomagalhaes-dev/go-service/business/data/order"
+
+// DefaultOrderBy represents the default way we sort, most recent first.
+var DefaultOrderBy = order.NewBy(OrderByDateCreated, order.DESC)
+
+// Set of fields that the results can be ordered by. These are the names
+// that should be used by the application layer.
+const (
+	OrderByID          = "audit_id"
+	OrderByEntity      = "entity"
+	OrderByAction      = "action"
+	OrderByActor       = "actor"
+	OrderByDateCreated = "date_created"
+)
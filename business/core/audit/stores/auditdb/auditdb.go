@@ -0,0 +1,124 @@
+// Package auditdb contains audit log related CRUD functionality.
+package auditdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for audit log database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (audit.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds an entry to the audit log.
+func (s *Store) Create(ctx context.Context, entry audit.Entry) error {
+	dbEnt, err := toDBEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	const q = `
+	INSERT INTO audit_log
+		(audit_id, tenant_id, actor, trace_id, entity, entity_id, action, diff, date_created)
+	VALUES
+		(:audit_id, :tenant_id, :actor, :trace_id, :entity, :entity_id, :action, :diff, :date_created)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbEnt); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves a list of existing entries from the database.
+func (s *Store) Query(ctx context.Context, filter audit.QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]audit.Entry, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		audit_id, tenant_id, actor, trace_id, entity, entity_id, action, diff, date_created
+	FROM
+		audit_log`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbEntries []dbEntry
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreEntrySlice(dbEntries)
+}
+
+// Count returns the total number of entries in the DB.
+func (s *Store) Count(ctx context.Context, filter audit.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		audit_log`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, err
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
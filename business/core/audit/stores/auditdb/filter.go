@@ -0,0 +1,68 @@
+package auditdb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+)
+
+func (s *Store) applyFilter(ctx context.Context, filter audit.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) error {
+	var wc []string
+
+	if filter.Entity != nil {
+		data["entity"] = *filter.Entity
+		wc = append(wc, "entity = :entity")
+	}
+
+	if filter.EntityID != nil {
+		data["entity_id"] = (*filter.EntityID).String()
+		wc = append(wc, "entity_id = :entity_id")
+	}
+
+	if filter.Action != nil {
+		data["action"] = *filter.Action
+		wc = append(wc, "action = :action")
+	}
+
+	if filter.Actor != nil {
+		data["actor"] = *filter.Actor
+		wc = append(wc, "actor = :actor")
+	}
+
+	if filter.TraceID != nil {
+		data["trace_id"] = *filter.TraceID
+		wc = append(wc, "trace_id = :trace_id")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = *filter.StartCreatedDate
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = *filter.EndCreatedDate
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	// Entries are scoped to the tenant of the context unless the context is
+	// marked as unscoped.
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+
+	return nil
+}
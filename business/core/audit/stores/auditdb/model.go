@@ -0,0 +1,78 @@
+package auditdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/google/uuid"
+)
+
+// dbEntry represents an entry stored in the audit log.
+type dbEntry struct {
+	ID          uuid.UUID `db:"audit_id"`     // Unique identifier.
+	TenantID    uuid.UUID `db:"tenant_id"`    // Tenant the entity belongs to.
+	Actor       string    `db:"actor"`        // Who performed the mutation.
+	TraceID     string    `db:"trace_id"`     // Trace of the request that performed the mutation.
+	Entity      string    `db:"entity"`       // Kind of entity that was mutated.
+	EntityID    uuid.UUID `db:"entity_id"`    // Identifier of the entity that was mutated.
+	Action      string    `db:"action"`       // Kind of mutation.
+	Diff        []byte    `db:"diff"`         // Encoded fields that changed.
+	DateCreated time.Time `db:"date_created"` // When the mutation was recorded.
+}
+
+// =============================================================================
+
+func toDBEntry(entry audit.Entry) (dbEntry, error) {
+	diff, err := json.Marshal(entry.Diff)
+	if err != nil {
+		return dbEntry{}, fmt.Errorf("marshal diff: %w", err)
+	}
+
+	dbEnt := dbEntry{
+		ID:          entry.ID,
+		TenantID:    entry.TenantID,
+		Actor:       entry.Actor,
+		TraceID:     entry.TraceID,
+		Entity:      entry.Entity,
+		EntityID:    entry.EntityID,
+		Action:      entry.Action,
+		Diff:        diff,
+		DateCreated: entry.DateCreated.UTC(),
+	}
+
+	return dbEnt, nil
+}
+
+func toCoreEntry(dbEnt dbEntry) (audit.Entry, error) {
+	var diff audit.Diff
+	if err := json.Unmarshal(dbEnt.Diff, &diff); err != nil {
+		return audit.Entry{}, fmt.Errorf("unmarshal diff: %w", err)
+	}
+
+	entry := audit.Entry{
+		ID:          dbEnt.ID,
+		TenantID:    dbEnt.TenantID,
+		Actor:       dbEnt.Actor,
+		TraceID:     dbEnt.TraceID,
+		Entity:      dbEnt.Entity,
+		EntityID:    dbEnt.EntityID,
+		Action:      dbEnt.Action,
+		Diff:        diff,
+		DateCreated: dbEnt.DateCreated.In(time.Local),
+	}
+
+	return entry, nil
+}
+
+func toCoreEntrySlice(dbEntries []dbEntry) ([]audit.Entry, error) {
+	entries := make([]audit.Entry, len(dbEntries))
+	for i, dbEnt := range dbEntries {
+		var err error
+		if entries[i], err = toCoreEntry(dbEnt); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
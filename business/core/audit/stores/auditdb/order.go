@@ -0,0 +1,33 @@
+package auditdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	audit.OrderByID:          "audit_id",
+	audit.OrderByEntity:      "entity",
+	audit.OrderByAction:      "action",
+	audit.OrderByActor:       "actor",
+	audit.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	fields := orderBy.Fields()
+
+	clauses := make([]string, len(fields))
+	for i, field := range fields {
+		by, exists := orderByFields[field.Field]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", field.Field)
+		}
+
+		clauses[i] = by + " " + field.Direction
+	}
+
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
@@ -1,43 +1,98 @@
 // Package event provides business access to events in the system.
+//
+// Events are not dispatched when they are sent. They are recorded in an
+// outbox using the transaction found in the context, if any, so an event is
+// only ever stored when the change that produced it is committed. A relay
+// then delivers the pending events to the registered handlers, retrying with
+// backoff until an event is delivered or moved to the dead state. Delivery is
+// at-least-once so handlers must be idempotent.
 package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
 )
 
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, entry Entry) error
+	Update(ctx context.Context, entry Entry) error
+	Claim(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]Entry, error)
+}
+
+// =============================================================================
+
 // Core manages the set of APIs for event access.
 type Core struct {
 	log      *logger.Logger
+	storer   Storer
 	handlers map[string]map[string][]HandleFunc
 }
 
 // NewCore constructs a core for event api access.
-func NewCore(log *logger.Logger) *Core {
+func NewCore(log *logger.Logger, storer Storer) *Core {
 	return &Core{
 		log:      log,
+		storer:   storer,
 		handlers: map[string]map[string][]HandleFunc{},
 	}
 }
 
-// SendEvent sends event to all handlers registered for the specified event.
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store-related calls. The handlers are shared
+// with the original core.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:      c.log,
+		storer:   storer,
+		handlers: c.handlers,
+	}
+
+	return c, nil
+}
+
+// SendEvent records the event in the outbox. If the context carries a
+// transaction, the event is written as part of that transaction.
 func (c *Core) SendEvent(ctx context.Context, event Event) error {
 	c.log.Info(ctx, "sendevent", "status", "started", "source", event.Source, "type", event.Type, "params", event.RawParams)
 	defer c.log.Info(ctx, "sendevent", "status", "completed")
 
-	if m, ok := c.handlers[event.Source]; ok {
-		if hfs, ok := m[event.Type]; ok {
-			for _, hf := range hfs {
-				c.log.Info(ctx, "sendevent", "status", "sending")
-
-				if err := hf(ctx, event); err != nil {
-					c.log.Error(ctx, "sendevent", "msg", err)
-				}
-			}
+	storer := c.storer
+	if tx, ok := transaction.Get(ctx); ok {
+		var err error
+		if storer, err = storer.ExecuteUnderTransaction(tx); err != nil {
+			return fmt.Errorf("executeundertransaction: %w", err)
 		}
 	}
 
+	now := time.Now()
+
+	entry := Entry{
+		ID:          uuid.New(),
+		Event:       event,
+		Status:      StatusPending,
+		DateCreated: now,
+		NextAttempt: now,
+	}
+
+	if err := storer.Create(ctx, entry); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
 	return nil
 }
 
@@ -51,3 +106,108 @@ func (c *Core) AddHandler(source, t string, f HandleFunc) {
 	ss[t] = append(ss[t], f)
 	c.handlers[source] = ss
 }
+
+// Relay delivers the pending events in the outbox until the context is
+// cancelled.
+func (c *Core) Relay(ctx context.Context, cfg RelayConfig) {
+	cfg = cfg.withDefaults()
+
+	c.log.Info(ctx, "relay", "status", "started", "interval", cfg.Interval)
+	defer c.log.Info(ctx, "relay", "status", "stopped")
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep processing while full batches are returned so a backlog is
+		// drained without waiting for the next tick.
+		for {
+			n, err := c.ProcessPending(ctx, cfg)
+			if err != nil {
+				c.log.Error(ctx, "relay", "msg", err)
+				break
+			}
+
+			if n < cfg.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// ProcessPending claims a batch of pending events from the outbox and
+// delivers them to the registered handlers. It returns the number of events
+// that were claimed.
+func (c *Core) ProcessPending(ctx context.Context, cfg RelayConfig) (int, error) {
+	cfg = cfg.withDefaults()
+
+	now := time.Now()
+
+	entries, err := c.storer.Claim(ctx, now, now.Add(cfg.Lease), cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim: %w", err)
+	}
+
+	for _, entry := range entries {
+		entry.Attempts++
+
+		err := c.dispatch(ctx, entry.Event)
+
+		now := time.Now()
+
+		switch {
+		case err == nil:
+			entry.Status = StatusDelivered
+			entry.LastError = ""
+			entry.DateDelivered = now
+
+		case entry.Attempts >= cfg.MaxAttempts:
+			c.log.Error(ctx, "relay", "status", "dead", "event_id", entry.ID, "attempts", entry.Attempts, "msg", err)
+			entry.Status = StatusDead
+			entry.LastError = err.Error()
+
+		default:
+			c.log.Info(ctx, "relay", "status", "retry", "event_id", entry.ID, "attempts", entry.Attempts, "msg", err)
+			entry.LastError = err.Error()
+			entry.NextAttempt = now.Add(cfg.backoff(entry.Attempts))
+		}
+
+		if err := c.storer.Update(ctx, entry); err != nil {
+			return 0, fmt.Errorf("update: eventID[%s]: %w", entry.ID, err)
+		}
+	}
+
+	return len(entries), nil
+}
+
+// =============================================================================
+
+// dispatch sends the event to all handlers registered for it. Every handler
+// is called even if a previous one failed.
+func (c *Core) dispatch(ctx context.Context, event Event) error {
+	c.log.Info(ctx, "dispatch", "source", event.Source, "type", event.Type)
+
+	// The relay delivers the events of every tenant, the handlers act on the
+	// entities named by the event.
+	ctx = tenant.Unscoped(ctx)
+
+	var errs []error
+
+	if m, ok := c.handlers[event.Source]; ok {
+		if hfs, ok := m[event.Type]; ok {
+			for _, hf := range hfs {
+				if err := hf(ctx, event); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
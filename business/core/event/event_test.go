@@ -0,0 +1,163 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Event(t *testing.T) {
+	t.Run("outbox", outbox)
+	t.Run("deadletter", deadLetter)
+}
+
+// =============================================================================
+
+func outbox(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	evnCore := event.NewCore(test.Log, eventdb.NewStore(test.Log, test.DB))
+
+	var delivered []event.Event
+	evnCore.AddHandler("test", "Sent", func(ctx context.Context, ev event.Event) error {
+		delivered = append(delivered, ev)
+		return nil
+	})
+
+	cfg := event.RelayConfig{BatchSize: 10}
+
+	// -------------------------------------------------------------------------
+
+	errRollback := errors.New("rollback")
+	f := func(tx transaction.Transaction) error {
+		ctx := transaction.Set(ctx, tx)
+		if err := evnCore.SendEvent(ctx, event.Event{Source: "test", Type: "Sent", RawParams: []byte(`{"n":1}`)}); err != nil {
+			return err
+		}
+		return errRollback
+	}
+
+	if err := transaction.ExecuteUnderTransaction(ctx, test.Log, db.NewBeginner(test.DB), f); !errors.Is(err, errRollback) {
+		t.Fatalf("Should get the rollback error : %v", err)
+	}
+
+	n, err := evnCore.ProcessPending(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Should be able to process the outbox : %s", err)
+	}
+
+	if n != 0 {
+		t.Fatalf("Should NOT find events from a rolled back transaction : got %d", n)
+	}
+
+	// -------------------------------------------------------------------------
+
+	f = func(tx transaction.Transaction) error {
+		ctx := transaction.Set(ctx, tx)
+		return evnCore.SendEvent(ctx, event.Event{Source: "test", Type: "Sent", RawParams: []byte(`{"n":2}`)})
+	}
+
+	if err := transaction.ExecuteUnderTransaction(ctx, test.Log, db.NewBeginner(test.DB), f); err != nil {
+		t.Fatalf("Should be able to send an event under a transaction : %s", err)
+	}
+
+	n, err = evnCore.ProcessPending(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Should be able to process the outbox : %s", err)
+	}
+
+	if n != 1 || len(delivered) != 1 {
+		t.Fatalf("Should deliver the committed event : claimed %d delivered %d", n, len(delivered))
+	}
+
+	if string(delivered[0].RawParams) != `{"n":2}` {
+		t.Fatalf("Should get back the same params : got %s", delivered[0].RawParams)
+	}
+
+	n, err = evnCore.ProcessPending(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Should be able to process the outbox : %s", err)
+	}
+
+	if n != 0 {
+		t.Fatalf("Should NOT deliver an event twice : got %d", n)
+	}
+}
+
+func deadLetter(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	evnCore := event.NewCore(test.Log, eventdb.NewStore(test.Log, test.DB))
+
+	var calls int
+	evnCore.AddHandler("test", "Failed", func(ctx context.Context, ev event.Event) error {
+		calls++
+		return errors.New("handler failed")
+	})
+
+	if err := evnCore.SendEvent(ctx, event.Event{Source: "test", Type: "Failed"}); err != nil {
+		t.Fatalf("Should be able to send an event : %s", err)
+	}
+
+	cfg := event.RelayConfig{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := evnCore.ProcessPending(ctx, cfg); err != nil {
+			t.Fatalf("Should be able to process the outbox : %s", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if calls != cfg.MaxAttempts {
+		t.Fatalf("Should stop retrying once the event is dead : got %d calls want %d", calls, cfg.MaxAttempts)
+	}
+}
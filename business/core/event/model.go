@@ -3,6 +3,9 @@ package event
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // HandleFunc represents a function that can receive an event.
@@ -22,3 +25,83 @@ func (e Event) String() string {
 		e.Source, e.Type, string(e.RawParams),
 	)
 }
+
+// =============================================================================
+
+// Set of states an outbox entry can be in.
+const (
+	StatusPending   = "PENDING"
+	StatusDelivered = "DELIVERED"
+	StatusDead      = "DEAD"
+)
+
+// Entry represents an event recorded in the outbox waiting to be delivered
+// to the registered handlers.
+type Entry struct {
+	ID            uuid.UUID
+	Event         Event
+	Status        string
+	Attempts      int
+	LastError     string
+	DateCreated   time.Time
+	NextAttempt   time.Time
+	DateDelivered time.Time
+}
+
+// RelayConfig represents the settings used by the relay when delivering
+// the events stored in the outbox.
+type RelayConfig struct {
+	Interval    time.Duration // How often the outbox is checked for pending events.
+	BatchSize   int           // Maximum number of events claimed on each pass.
+	MaxAttempts int           // Attempts before an event is moved to the dead state.
+	BaseBackoff time.Duration // Delay before the first retry, doubled on each attempt.
+	MaxBackoff  time.Duration // Upper limit for the delay between retries.
+	Lease       time.Duration // Time an event is held by a relay before it can be claimed again.
+}
+
+// DefaultRelayConfig provides the settings used when a field of a RelayConfig
+// value is not provided.
+var DefaultRelayConfig = RelayConfig{
+	Interval:    time.Second,
+	BatchSize:   100,
+	MaxAttempts: 10,
+	BaseBackoff: time.Second,
+	MaxBackoff:  5 * time.Minute,
+	Lease:       time.Minute,
+}
+
+func (cfg RelayConfig) withDefaults() RelayConfig {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultRelayConfig.Interval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultRelayConfig.BatchSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultRelayConfig.MaxAttempts
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = DefaultRelayConfig.BaseBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultRelayConfig.MaxBackoff
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultRelayConfig.Lease
+	}
+
+	return cfg
+}
+
+// backoff returns the delay to wait before the next attempt is made.
+func (cfg RelayConfig) backoff(attempts int) time.Duration {
+	d := cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,120 @@
+// Package eventdb contains the outbox related functionality for events.
+package eventdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for outbox database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (event.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds an event to the outbox.
+func (s *Store) Create(ctx context.Context, entry event.Entry) error {
+	const q = `
+	INSERT INTO event_outbox
+		(event_id, source, type, raw_params, status, attempts, last_error, date_created, next_attempt, date_delivered)
+	VALUES
+		(:event_id, :source, :type, :raw_params, :status, :attempts, :last_error, :date_created, :next_attempt, :date_delivered)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(entry)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update records the result of a delivery attempt.
+func (s *Store) Update(ctx context.Context, entry event.Entry) error {
+	const q = `
+	UPDATE
+		event_outbox
+	SET
+		"status" = :status,
+		"attempts" = :attempts,
+		"last_error" = :last_error,
+		"next_attempt" = :next_attempt,
+		"date_delivered" = :date_delivered
+	WHERE
+		event_id = :event_id`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(entry)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Claim retrieves the pending events that are due and holds them until the
+// lease expires, so concurrent relays don't deliver the same events. Rows
+// already locked by another relay are skipped.
+func (s *Store) Claim(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]event.Entry, error) {
+	data := map[string]interface{}{
+		"status":      event.StatusPending,
+		"now":         now.UTC(),
+		"lease_until": leaseUntil.UTC(),
+		"limit":       limit,
+	}
+
+	const q = `
+	UPDATE
+		event_outbox
+	SET
+		"next_attempt" = :lease_until
+	WHERE
+		event_id IN (
+			SELECT
+				event_id
+			FROM
+				event_outbox
+			WHERE
+				status = :status AND next_attempt <= :now
+			ORDER BY
+				date_created
+			LIMIT :limit
+			FOR UPDATE SKIP LOCKED
+		)
+	RETURNING
+		event_id, source, type, raw_params, status, attempts, last_error, date_created, next_attempt, date_delivered`
+
+	var dbEntries []dbEntry
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreEntrySlice(dbEntries), nil
+}
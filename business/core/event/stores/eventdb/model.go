@@ -0,0 +1,78 @@
+package eventdb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/google/uuid"
+)
+
+// dbEntry represents an event stored in the outbox.
+type dbEntry struct {
+	ID            uuid.UUID      `db:"event_id"`       // Unique identifier.
+	Source        string         `db:"source"`         // Domain that produced the event.
+	Type          string         `db:"type"`           // Type of event within the source.
+	RawParams     []byte         `db:"raw_params"`     // Encoded parameters of the event.
+	Status        string         `db:"status"`         // Delivery state of the event.
+	Attempts      int            `db:"attempts"`       // Number of delivery attempts made.
+	LastError     sql.NullString `db:"last_error"`     // Error from the last failed attempt.
+	DateCreated   time.Time      `db:"date_created"`   // When the event was recorded.
+	NextAttempt   time.Time      `db:"next_attempt"`   // When the event can be claimed again.
+	DateDelivered sql.NullTime   `db:"date_delivered"` // When the event was delivered.
+}
+
+// =============================================================================
+
+func toDBEntry(entry event.Entry) dbEntry {
+	dbEnt := dbEntry{
+		ID:        entry.ID,
+		Source:    entry.Event.Source,
+		Type:      entry.Event.Type,
+		RawParams: entry.Event.RawParams,
+		Status:    entry.Status,
+		Attempts:  entry.Attempts,
+		LastError: sql.NullString{
+			String: entry.LastError,
+			Valid:  entry.LastError != "",
+		},
+		DateCreated: entry.DateCreated.UTC(),
+		NextAttempt: entry.NextAttempt.UTC(),
+		DateDelivered: sql.NullTime{
+			Time:  entry.DateDelivered.UTC(),
+			Valid: !entry.DateDelivered.IsZero(),
+		},
+	}
+
+	return dbEnt
+}
+
+func toCoreEntry(dbEnt dbEntry) event.Entry {
+	entry := event.Entry{
+		ID: dbEnt.ID,
+		Event: event.Event{
+			Source:    dbEnt.Source,
+			Type:      dbEnt.Type,
+			RawParams: dbEnt.RawParams,
+		},
+		Status:      dbEnt.Status,
+		Attempts:    dbEnt.Attempts,
+		LastError:   dbEnt.LastError.String,
+		DateCreated: dbEnt.DateCreated.In(time.Local),
+		NextAttempt: dbEnt.NextAttempt.In(time.Local),
+	}
+
+	if dbEnt.DateDelivered.Valid {
+		entry.DateDelivered = dbEnt.DateDelivered.Time.In(time.Local)
+	}
+
+	return entry
+}
+
+func toCoreEntrySlice(dbEntries []dbEntry) []event.Entry {
+	entries := make([]event.Entry, len(dbEntries))
+	for i, dbEnt := range dbEntries {
+		entries[i] = toCoreEntry(dbEnt)
+	}
+	return entries
+}
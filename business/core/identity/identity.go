@@ -0,0 +1,272 @@
+// Package identity provides the core business API for logging users in
+// through an external identity provider.
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for identity operations.
+var (
+	ErrNotFound         = errors.New("identity not found")
+	ErrInvalidLogin     = errors.New("login not valid")
+	ErrLoginExpired     = errors.New("login expired")
+	ErrEmailNotVerified = errors.New("email not verified by the provider")
+	ErrUniqueSubject    = errors.New("subject is not unique")
+)
+
+// LoginTTL is the amount of time the user has to log in with the provider.
+const LoginTTL = 10 * time.Minute
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, idn Identity) error
+	QueryBySubject(ctx context.Context, issuer string, subject string) (Identity, error)
+	CreateLogin(ctx context.Context, lgn Login) error
+	DeleteLogin(ctx context.Context, stateHash string) (Login, error)
+	DeleteExpiredLogins(ctx context.Context, now time.Time) error
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for identity access.
+type Core struct {
+	log          *logger.Logger
+	usrCore      *user.Core
+	storer       Storer
+	defaultRoles []user.Role
+}
+
+// NewCore constructs a core for identity api access.
+func NewCore(log *logger.Logger, usrCore *user.Core, storer Storer, options ...func(c *Core)) *Core {
+	c := Core{
+		log:          log,
+		usrCore:      usrCore,
+		storer:       storer,
+		defaultRoles: []user.Role{user.RoleUser},
+	}
+
+	for _, option := range options {
+		option(&c)
+	}
+
+	return &c
+}
+
+// WithDefaultRoles sets the roles given to the users created on their first
+// login. Without roles users get the USER role.
+func WithDefaultRoles(roles ...user.Role) func(c *Core) {
+	return func(c *Core) {
+		if len(roles) > 0 {
+			c.defaultRoles = roles
+		}
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:          c.log,
+		usrCore:      usrCore,
+		storer:       storer,
+		defaultRoles: c.defaultRoles,
+	}
+
+	return c, nil
+}
+
+// StartLogin records a new login with the provider. The raw state is returned
+// along with the login, which holds the nonce and PKCE verifier for the
+// provider.
+func (c *Core) StartLogin(ctx context.Context) (string, Login, error) {
+	now := time.Now()
+
+	if err := c.storer.DeleteExpiredLogins(ctx, now); err != nil {
+		return "", Login{}, fmt.Errorf("deleteexpiredlogins: %w", err)
+	}
+
+	state, err := random()
+	if err != nil {
+		return "", Login{}, err
+	}
+
+	nonce, err := random()
+	if err != nil {
+		return "", Login{}, err
+	}
+
+	verifier, err := random()
+	if err != nil {
+		return "", Login{}, err
+	}
+
+	lgn := Login{
+		StateHash:   hash(state),
+		Nonce:       nonce,
+		Verifier:    verifier,
+		DateCreated: now,
+		DateExpires: now.Add(LoginTTL),
+	}
+
+	if err := c.storer.CreateLogin(ctx, lgn); err != nil {
+		return "", Login{}, fmt.Errorf("createlogin: %w", err)
+	}
+
+	return state, lgn, nil
+}
+
+// FinishLogin removes the login for the raw state and returns it. A login can
+// only be finished once.
+func (c *Core) FinishLogin(ctx context.Context, state string) (Login, error) {
+	lgn, err := c.storer.DeleteLogin(ctx, hash(state))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Login{}, ErrInvalidLogin
+		}
+		return Login{}, fmt.Errorf("deletelogin: %w", err)
+	}
+
+	if time.Now().After(lgn.DateExpires) {
+		return Login{}, ErrLoginExpired
+	}
+
+	return lgn, nil
+}
+
+// Resolve returns the user for the identity asserted by the provider. A user
+// who logs in for the first time is linked to the existing user with the same
+// email, or created with the default roles when there is none. Linking to an
+// existing user requires the provider to have verified the email, otherwise
+// anyone able to register the email with the provider would take over the
+// account.
+func (c *Core) Resolve(ctx context.Context, ext External) (user.User, error) {
+	idn, err := c.storer.QueryBySubject(ctx, ext.Issuer, ext.Subject)
+	switch {
+	case err == nil:
+		usr, err := c.usrCore.QueryByID(ctx, idn.UserID)
+		if err != nil {
+			return user.User{}, fmt.Errorf("user.querybyid: userID[%s]: %w", idn.UserID, err)
+		}
+		return usr, nil
+
+	case !errors.Is(err, ErrNotFound):
+		return user.User{}, fmt.Errorf("querybysubject: subject[%s]: %w", ext.Subject, err)
+	}
+
+	usr, err := c.usrCore.QueryByEmail(ctx, ext.Email)
+	switch {
+	case err == nil:
+		if !ext.EmailVerified {
+			return user.User{}, ErrEmailNotVerified
+		}
+
+	case errors.Is(err, user.ErrNotFound):
+		usr, err = c.provision(ctx, ext)
+		if err != nil {
+			return user.User{}, err
+		}
+
+	default:
+		return user.User{}, fmt.Errorf("user.querybyemail: %w", err)
+	}
+
+	idn = Identity{
+		ID:          uuid.New(),
+		UserID:      usr.ID,
+		Issuer:      ext.Issuer,
+		Subject:     ext.Subject,
+		Email:       ext.Email.Address,
+		DateCreated: time.Now(),
+	}
+
+	if err := c.storer.Create(ctx, idn); err != nil {
+		return user.User{}, fmt.Errorf("create: userID[%s]: %w", usr.ID, err)
+	}
+
+	return usr, nil
+}
+
+// =============================================================================
+
+// provision creates the user for an identity seen for the first time. The
+// user gets a random password, so they can only log in through the provider
+// until they reset it.
+func (c *Core) provision(ctx context.Context, ext External) (user.User, error) {
+	password, err := random()
+	if err != nil {
+		return user.User{}, err
+	}
+
+	name := ext.Name
+	if name == "" {
+		name = ext.Email.Name
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(ext.Email.Address, "@")
+	}
+
+	nu := user.NewUser{
+		Name:            name,
+		Email:           mail.Address{Name: name, Address: ext.Email.Address},
+		Roles:           c.defaultRoles,
+		Password:        password,
+		PasswordConfirm: password,
+	}
+
+	usr, err := c.usrCore.Create(ctx, nu)
+	if err != nil {
+		return user.User{}, fmt.Errorf("user.create: %w", err)
+	}
+
+	if ext.EmailVerified {
+		usr, err = c.usrCore.VerifyEmail(ctx, usr)
+		if err != nil {
+			return user.User{}, fmt.Errorf("user.verifyemail: userID[%s]: %w", usr.ID, err)
+		}
+	}
+
+	return usr, nil
+}
+
+func random() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random value: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
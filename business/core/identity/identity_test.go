@@ -0,0 +1,160 @@
+package identity_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Identity(t *testing.T) {
+	t.Run("login", login)
+	t.Run("resolve", resolve)
+}
+
+// =============================================================================
+
+func login(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	state, lgn, err := api.Identity.StartLogin(ctx)
+	if err != nil {
+		t.Fatalf("Should be able to start a login : %s", err)
+	}
+
+	if lgn.Nonce == "" || lgn.Verifier == "" || lgn.StateHash == state {
+		t.Fatalf("Should get a nonce, verifier and hashed state : %+v", lgn)
+	}
+
+	got, err := api.Identity.FinishLogin(ctx, state)
+	if err != nil {
+		t.Fatalf("Should be able to finish the login : %s", err)
+	}
+
+	if got.Nonce != lgn.Nonce || got.Verifier != lgn.Verifier {
+		t.Fatalf("Should get back the nonce and verifier : got %+v, exp %+v", got, lgn)
+	}
+
+	if _, err := api.Identity.FinishLogin(ctx, state); !errors.Is(err, identity.ErrInvalidLogin) {
+		t.Fatalf("Should NOT be able to finish the login twice : %v", err)
+	}
+
+	if _, err := api.Identity.FinishLogin(ctx, "unknown"); !errors.Is(err, identity.ErrInvalidLogin) {
+		t.Fatalf("Should NOT be able to finish an unknown login : %v", err)
+	}
+}
+
+func resolve(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	const issuer = "https://idp.example.com"
+
+	ext := identity.External{
+		Issuer:        issuer,
+		Subject:       "subject-1",
+		Email:         mail.Address{Address: "jane@example.com"},
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}
+
+	usr, err := api.Identity.Resolve(ctx, ext)
+	if err != nil {
+		t.Fatalf("Should be able to provision a user : %s", err)
+	}
+
+	if usr.Name != "Jane Doe" || usr.Email.Address != "jane@example.com" || !usr.EmailVerified {
+		t.Fatalf("Should get a user from the claims : %+v", usr)
+	}
+
+	if len(usr.Roles) != 1 || usr.Roles[0] != user.RoleUser {
+		t.Fatalf("Should get the default role : %v", usr.Roles)
+	}
+
+	// The provider can report a new email, the subject still identifies the
+	// user.
+	ext.Email = mail.Address{Address: "jane.doe@example.com"}
+
+	got, err := api.Identity.Resolve(ctx, ext)
+	if err != nil {
+		t.Fatalf("Should be able to resolve a known subject : %s", err)
+	}
+
+	if got.ID != usr.ID {
+		t.Fatalf("Should get the same user for the subject : got %s, exp %s", got.ID, usr.ID)
+	}
+
+	// -------------------------------------------------------------------------
+
+	existing, err := api.User.QueryByEmail(ctx, mail.Address{Address: "user@example.com"})
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the seeded user : %s", err)
+	}
+
+	ext = identity.External{
+		Issuer:  issuer,
+		Subject: "subject-2",
+		Email:   mail.Address{Address: "user@example.com"},
+	}
+
+	if _, err := api.Identity.Resolve(ctx, ext); !errors.Is(err, identity.ErrEmailNotVerified) {
+		t.Fatalf("Should NOT link a user through an unverified email : %v", err)
+	}
+
+	ext.EmailVerified = true
+
+	got, err = api.Identity.Resolve(ctx, ext)
+	if err != nil {
+		t.Fatalf("Should be able to link an existing user : %s", err)
+	}
+
+	if got.ID != existing.ID {
+		t.Fatalf("Should get the existing user for the email : got %s, exp %s", got.ID, existing.ID)
+	}
+}
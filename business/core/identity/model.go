@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity links a user to the subject of an external identity provider.
+// Email is the address the provider asserted when the link was created.
+type Identity struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Issuer      string
+	Subject     string
+	Email       string
+	DateCreated time.Time
+}
+
+// Login represents a login with the provider waiting for the user to come
+// back. Only the hash of the state is kept, the raw state is sent to the
+// provider and the browser.
+type Login struct {
+	StateHash   string
+	Nonce       string
+	Verifier    string
+	DateCreated time.Time
+	DateExpires time.Time
+}
+
+// External represents the user as asserted by the provider.
+type External struct {
+	Issuer        string
+	Subject       string
+	Email         mail.Address
+	EmailVerified bool
+	Name          string
+}
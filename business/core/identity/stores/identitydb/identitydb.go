@@ -0,0 +1,157 @@
+// Package identitydb contains identity related CRUD functionality.
+package identitydb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for identity database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (identity.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds an identity to the database.
+func (s *Store) Create(ctx context.Context, idn identity.Identity) error {
+	const q = `
+	INSERT INTO user_identities
+		(identity_id, user_id, issuer, subject, email, date_created)
+	VALUES
+		(:identity_id, :user_id, :issuer, :subject, :email, :date_created)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBIdentity(idn)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", identity.ErrUniqueSubject)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryBySubject finds the identity for the subject of the issuer.
+func (s *Store) QueryBySubject(ctx context.Context, issuer string, subject string) (identity.Identity, error) {
+	data := struct {
+		Issuer  string `db:"issuer"`
+		Subject string `db:"subject"`
+	}{
+		Issuer:  issuer,
+		Subject: subject,
+	}
+
+	const q = `
+	SELECT
+		identity_id, user_id, issuer, subject, email, date_created
+	FROM
+		user_identities
+	WHERE
+		issuer = :issuer AND subject = :subject`
+
+	var dbIdn dbIdentity
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbIdn); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return identity.Identity{}, fmt.Errorf("namedquerystruct: %w", identity.ErrNotFound)
+		}
+		return identity.Identity{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreIdentity(dbIdn), nil
+}
+
+// CreateLogin adds a login to the database.
+func (s *Store) CreateLogin(ctx context.Context, lgn identity.Login) error {
+	const q = `
+	INSERT INTO oidc_logins
+		(state_hash, nonce, verifier, date_created, date_expires)
+	VALUES
+		(:state_hash, :nonce, :verifier, :date_created, :date_expires)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBLogin(lgn)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLogin removes the login with the state hash and returns it. Only one
+// caller can get the login back, the loser of a race gets
+// identity.ErrNotFound.
+func (s *Store) DeleteLogin(ctx context.Context, stateHash string) (identity.Login, error) {
+	data := struct {
+		StateHash string `db:"state_hash"`
+	}{
+		StateHash: stateHash,
+	}
+
+	const q = `
+	DELETE FROM
+		oidc_logins
+	WHERE
+		state_hash = :state_hash
+	RETURNING
+		state_hash, nonce, verifier, date_created, date_expires`
+
+	var dbLgn dbLogin
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbLgn); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return identity.Login{}, fmt.Errorf("namedquerystruct: %w", identity.ErrNotFound)
+		}
+		return identity.Login{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreLogin(dbLgn), nil
+}
+
+// DeleteExpiredLogins removes the logins that expired before now.
+func (s *Store) DeleteExpiredLogins(ctx context.Context, now time.Time) error {
+	data := struct {
+		Now time.Time `db:"now"`
+	}{
+		Now: now.UTC(),
+	}
+
+	const q = `
+	DELETE FROM
+		oidc_logins
+	WHERE
+		date_expires < :now`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
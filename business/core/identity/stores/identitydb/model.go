@@ -0,0 +1,71 @@
+package identitydb
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	"github.com/google/uuid"
+)
+
+// dbIdentity represents the link between a user and a provider subject.
+type dbIdentity struct {
+	ID          uuid.UUID `db:"identity_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Issuer      string    `db:"issuer"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBIdentity(idn identity.Identity) dbIdentity {
+	return dbIdentity{
+		ID:          idn.ID,
+		UserID:      idn.UserID,
+		Issuer:      idn.Issuer,
+		Subject:     idn.Subject,
+		Email:       idn.Email,
+		DateCreated: idn.DateCreated.UTC(),
+	}
+}
+
+func toCoreIdentity(dbIdn dbIdentity) identity.Identity {
+	return identity.Identity{
+		ID:          dbIdn.ID,
+		UserID:      dbIdn.UserID,
+		Issuer:      dbIdn.Issuer,
+		Subject:     dbIdn.Subject,
+		Email:       dbIdn.Email,
+		DateCreated: dbIdn.DateCreated.In(time.Local),
+	}
+}
+
+// =============================================================================
+
+// dbLogin represents a login waiting for the provider callback.
+type dbLogin struct {
+	StateHash   string    `db:"state_hash"`
+	Nonce       string    `db:"nonce"`
+	Verifier    string    `db:"verifier"`
+	DateCreated time.Time `db:"date_created"`
+	DateExpires time.Time `db:"date_expires"`
+}
+
+func toDBLogin(lgn identity.Login) dbLogin {
+	return dbLogin{
+		StateHash:   lgn.StateHash,
+		Nonce:       lgn.Nonce,
+		Verifier:    lgn.Verifier,
+		DateCreated: lgn.DateCreated.UTC(),
+		DateExpires: lgn.DateExpires.UTC(),
+	}
+}
+
+func toCoreLogin(dbLgn dbLogin) identity.Login {
+	return identity.Login{
+		StateHash:   dbLgn.StateHash,
+		Nonce:       dbLgn.Nonce,
+		Verifier:    dbLgn.Verifier,
+		DateCreated: dbLgn.DateCreated.In(time.Local),
+		DateExpires: dbLgn.DateExpires.In(time.Local),
+	}
+}
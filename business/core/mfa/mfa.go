@@ -0,0 +1,393 @@
+// Package mfa provides the core business API for the TOTP second factor of
+// users, their recovery codes and the challenge that completes a login.
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/totp"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for mfa operations.
+var (
+	ErrNotFound         = errors.New("mfa not found")
+	ErrNotEnrolled      = errors.New("mfa not enrolled")
+	ErrAlreadyEnrolled  = errors.New("mfa already enrolled")
+	ErrInvalidCode      = errors.New("mfa code not valid")
+	ErrInvalidChallenge = errors.New("mfa challenge not valid")
+	ErrChallengeExpired = errors.New("mfa challenge expired")
+	ErrLocked           = errors.New("mfa locked")
+)
+
+// ChallengeTTL is the amount of time a user has to provide the second factor
+// after the password was verified.
+const ChallengeTTL = 5 * time.Minute
+
+// FailureLockout is the amount of time a user can't start or complete a
+// challenge after too many failed attempts.
+const FailureLockout = 15 * time.Minute
+
+// Limits for challenges, users and recovery codes. A challenge is discarded
+// after maxAttempts wrong codes and a user is locked out after maxFailures
+// wrong codes across their challenges.
+const (
+	maxAttempts   = 5
+	maxFailures   = 10
+	recoveryCodes = 10
+)
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	SaveEnrollment(ctx context.Context, enr Enrollment) error
+	UpdateLastStep(ctx context.Context, userID uuid.UUID, step int64) error
+	DeleteEnrollment(ctx context.Context, userID uuid.UUID) error
+	QueryEnrollment(ctx context.Context, userID uuid.UUID) (Enrollment, error)
+	CreateRecoveryCodes(ctx context.Context, codes []RecoveryCode) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, now time.Time) error
+	CountFailure(ctx context.Context, userID uuid.UUID, limit int, since time.Time, now time.Time) error
+	ClearFailures(ctx context.Context, userID uuid.UUID) error
+	CreateChallenge(ctx context.Context, ch Challenge) error
+	AttemptChallenge(ctx context.Context, challengeID uuid.UUID, limit int) (int, error)
+	UseChallenge(ctx context.Context, challengeID uuid.UUID, now time.Time) error
+	QueryChallengeByHash(ctx context.Context, hash string) (Challenge, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for mfa access.
+type Core struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewCore constructs a core for mfa api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:    c.log,
+		storer: storer,
+	}
+
+	return c, nil
+}
+
+// Enroll generates a new TOTP secret for the user of the tenant. Enrolling
+// again before the enrollment is confirmed replaces the secret.
+func (c *Core) Enroll(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID) (Enrollment, error) {
+	enr, err := c.storer.QueryEnrollment(ctx, userID)
+	switch {
+	case err == nil:
+		if enr.Confirmed() {
+			return Enrollment{}, ErrAlreadyEnrolled
+		}
+	case !errors.Is(err, ErrNotFound):
+		return Enrollment{}, fmt.Errorf("queryenrollment: userID[%s]: %w", userID, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	enr = Enrollment{
+		UserID:      userID,
+		TenantID:    tenantID,
+		Secret:      secret,
+		DateCreated: time.Now(),
+	}
+
+	if err := c.storer.SaveEnrollment(ctx, enr); err != nil {
+		return Enrollment{}, fmt.Errorf("saveenrollment: userID[%s]: %w", userID, err)
+	}
+
+	return enr, nil
+}
+
+// Confirm completes the enrollment of the user with a code from their app.
+// The raw recovery codes are returned and are not stored anywhere.
+func (c *Core) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	enr, err := c.storer.QueryEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotEnrolled
+		}
+		return nil, fmt.Errorf("queryenrollment: userID[%s]: %w", userID, err)
+	}
+
+	if enr.Confirmed() {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	now := time.Now()
+
+	step, err := totp.Validate(enr.Secret, code, now)
+	if err != nil {
+		return nil, ErrInvalidCode
+	}
+
+	enr.LastStep = step
+	enr.DateConfirmed = now
+
+	if err := c.storer.SaveEnrollment(ctx, enr); err != nil {
+		return nil, fmt.Errorf("saveenrollment: userID[%s]: %w", userID, err)
+	}
+
+	return c.RegenerateRecoveryCodes(ctx, userID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user. The raw
+// codes are returned and are not stored anywhere.
+func (c *Core) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	enr, err := c.storer.QueryEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotEnrolled
+		}
+		return nil, fmt.Errorf("queryenrollment: userID[%s]: %w", userID, err)
+	}
+
+	if err := c.storer.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("deleterecoverycodes: userID[%s]: %w", userID, err)
+	}
+
+	now := time.Now()
+
+	raws := make([]string, recoveryCodes)
+	codes := make([]RecoveryCode, recoveryCodes)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generating recovery code: %w", err)
+		}
+		raw := hex.EncodeToString(b)
+
+		raws[i] = raw[:5] + "-" + raw[5:]
+		codes[i] = RecoveryCode{
+			ID:          uuid.New(),
+			UserID:      userID,
+			TenantID:    enr.TenantID,
+			Hash:        hash(raw),
+			DateCreated: now,
+		}
+	}
+
+	if err := c.storer.CreateRecoveryCodes(ctx, codes); err != nil {
+		return nil, fmt.Errorf("createrecoverycodes: userID[%s]: %w", userID, err)
+	}
+
+	return raws, nil
+}
+
+// Enabled reports whether the user has a confirmed second factor.
+func (c *Core) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	enr, err := c.storer.QueryEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("queryenrollment: userID[%s]: %w", userID, err)
+	}
+
+	return enr.Confirmed(), nil
+}
+
+// Disable removes the second factor and the recovery codes of the user.
+func (c *Core) Disable(ctx context.Context, userID uuid.UUID) error {
+	if err := c.storer.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("deleterecoverycodes: userID[%s]: %w", userID, err)
+	}
+
+	if err := c.storer.DeleteEnrollment(ctx, userID); err != nil {
+		return fmt.Errorf("deleteenrollment: userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// Challenge starts the second step of a login for the user. The raw
+// challenge token is returned and is not stored anywhere. A user locked out
+// after too many failed attempts can't start a challenge.
+func (c *Core) Challenge(ctx context.Context, userID uuid.UUID) (string, Challenge, error) {
+	enr, err := c.storer.QueryEnrollment(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", Challenge{}, ErrNotEnrolled
+		}
+		return "", Challenge{}, fmt.Errorf("queryenrollment: userID[%s]: %w", userID, err)
+	}
+
+	if enr.Locked(time.Now()) {
+		return "", Challenge{}, ErrLocked
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", Challenge{}, fmt.Errorf("generating challenge: %w", err)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+
+	ch := Challenge{
+		ID:          uuid.New(),
+		UserID:      userID,
+		TenantID:    enr.TenantID,
+		Hash:        hash(raw),
+		DateCreated: now,
+		DateExpires: now.Add(ChallengeTTL),
+	}
+
+	if err := c.storer.CreateChallenge(ctx, ch); err != nil {
+		return "", Challenge{}, fmt.Errorf("createchallenge: userID[%s]: %w", userID, err)
+	}
+
+	return raw, ch, nil
+}
+
+// Verify completes the challenge with either a TOTP code or a recovery code.
+// A challenge can only be completed once and is discarded after too many
+// wrong codes, and the user is locked out after too many wrong codes across
+// their challenges. Failed attempts are written straight to the store, so
+// this must not run in a transaction that is rolled back on error.
+func (c *Core) Verify(ctx context.Context, raw string, code string) (Challenge, error) {
+	ch, err := c.storer.QueryChallengeByHash(ctx, hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Challenge{}, ErrInvalidChallenge
+		}
+		return Challenge{}, fmt.Errorf("querychallengebyhash: %w", err)
+	}
+
+	now := time.Now()
+
+	if ch.Used() || ch.Attempts >= maxAttempts {
+		return Challenge{}, ErrInvalidChallenge
+	}
+
+	if now.After(ch.DateExpires) {
+		return Challenge{}, ErrChallengeExpired
+	}
+
+	enr, err := c.storer.QueryEnrollment(ctx, ch.UserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Challenge{}, ErrNotEnrolled
+		}
+		return Challenge{}, fmt.Errorf("queryenrollment: userID[%s]: %w", ch.UserID, err)
+	}
+
+	if !enr.Confirmed() {
+		return Challenge{}, ErrNotEnrolled
+	}
+
+	// The attempt is taken from the challenge and counted as a failure of the
+	// user before the code is checked, so concurrent attempts can't go past
+	// the limits. The failures are cleared once a code is accepted.
+	attempts, err := c.storer.AttemptChallenge(ctx, ch.ID, maxAttempts)
+	if err != nil {
+		if errors.Is(err, ErrInvalidChallenge) {
+			return Challenge{}, ErrInvalidChallenge
+		}
+		return Challenge{}, fmt.Errorf("attemptchallenge: %w", err)
+	}
+
+	ch.Attempts = attempts
+
+	if err := c.storer.CountFailure(ctx, ch.UserID, maxFailures, now.Add(-FailureLockout), now); err != nil {
+		if errors.Is(err, ErrLocked) {
+			return Challenge{}, ErrLocked
+		}
+		return Challenge{}, fmt.Errorf("countfailure: userID[%s]: %w", ch.UserID, err)
+	}
+
+	ok, err := c.checkCode(ctx, enr, code, now)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	if !ok {
+		return Challenge{}, ErrInvalidCode
+	}
+
+	if err := c.storer.UseChallenge(ctx, ch.ID, now); err != nil {
+		if errors.Is(err, ErrInvalidChallenge) {
+			return Challenge{}, ErrInvalidChallenge
+		}
+		return Challenge{}, fmt.Errorf("usechallenge: %w", err)
+	}
+
+	ch.DateUsed = now
+
+	if err := c.storer.ClearFailures(ctx, ch.UserID); err != nil {
+		return Challenge{}, fmt.Errorf("clearfailures: userID[%s]: %w", ch.UserID, err)
+	}
+
+	return ch, nil
+}
+
+// =============================================================================
+
+// checkCode validates a TOTP code or uses up a recovery code. A TOTP code
+// that was already accepted can't be used again.
+func (c *Core) checkCode(ctx context.Context, enr Enrollment, code string, now time.Time) (bool, error) {
+	code = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(code)), "-", "")
+
+	if len(code) == totp.Digits {
+		step, err := totp.Validate(enr.Secret, code, now)
+		if err != nil || step <= enr.LastStep {
+			return false, nil
+		}
+
+		if err := c.storer.UpdateLastStep(ctx, enr.UserID, step); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("updatelaststep: userID[%s]: %w", enr.UserID, err)
+		}
+
+		return true, nil
+	}
+
+	if err := c.storer.UseRecoveryCode(ctx, enr.UserID, hash(code), now); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("userecoverycode: userID[%s]: %w", enr.UserID, err)
+	}
+
+	return true, nil
+}
+
+func hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,238 @@
+package mfa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/diegomagalhaes-dev/go-service/foundation/totp"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_MFA(t *testing.T) {
+	t.Run("enroll", enroll)
+	t.Run("challenge", challenge)
+}
+
+// =============================================================================
+
+func enroll(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	if _, err := api.MFA.Confirm(ctx, userID, "000000"); !errors.Is(err, mfa.ErrNotEnrolled) {
+		t.Fatalf("Should NOT be able to confirm without an enrollment : %v", err)
+	}
+
+	enr, err := api.MFA.Enroll(ctx, userID, tenant.Default)
+	if err != nil {
+		t.Fatalf("Should be able to enroll : %s", err)
+	}
+
+	enabled, err := api.MFA.Enabled(ctx, userID)
+	if err != nil || enabled {
+		t.Fatalf("Should NOT be enabled before the enrollment is confirmed : %v %v", enabled, err)
+	}
+
+	if _, err := api.MFA.Confirm(ctx, userID, "abcdef"); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Fatalf("Should NOT be able to confirm with a wrong code : %v", err)
+	}
+
+	code, err := totp.Code(enr.Secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("Should be able to generate a code : %s", err)
+	}
+
+	codes, err := api.MFA.Confirm(ctx, userID, code)
+	if err != nil {
+		t.Fatalf("Should be able to confirm the enrollment : %s", err)
+	}
+
+	if len(codes) != 10 {
+		t.Fatalf("Should get back the recovery codes : %v", codes)
+	}
+
+	enabled, err = api.MFA.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		t.Fatalf("Should be enabled once the enrollment is confirmed : %v %v", enabled, err)
+	}
+
+	if _, err := api.MFA.Enroll(ctx, userID, tenant.Default); !errors.Is(err, mfa.ErrAlreadyEnrolled) {
+		t.Fatalf("Should NOT be able to enroll twice : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	ctxOther := tenant.Set(ctx, uuid.New())
+
+	enabled, err = api.MFA.Enabled(ctxOther, userID)
+	if err != nil || enabled {
+		t.Fatalf("Should NOT see the enrollment from another tenant : %v %v", enabled, err)
+	}
+
+	if err := api.MFA.Disable(ctxOther, userID); err != nil {
+		t.Fatalf("Should be able to call disable from another tenant : %s", err)
+	}
+
+	enabled, err = api.MFA.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		t.Fatalf("Should NOT be disabled from another tenant : %v %v", enabled, err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := api.MFA.Disable(ctx, userID); err != nil {
+		t.Fatalf("Should be able to disable mfa : %s", err)
+	}
+
+	enabled, err = api.MFA.Enabled(ctx, userID)
+	if err != nil || enabled {
+		t.Fatalf("Should NOT be enabled once disabled : %v %v", enabled, err)
+	}
+}
+
+func challenge(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	enr, err := api.MFA.Enroll(ctx, userID, tenant.Default)
+	if err != nil {
+		t.Fatalf("Should be able to enroll : %s", err)
+	}
+
+	// Confirm with the code of the previous step so the current step is
+	// still available for the challenge.
+	code, err := totp.Code(enr.Secret, totp.Step(time.Now())-1)
+	if err != nil {
+		t.Fatalf("Should be able to generate a code : %s", err)
+	}
+
+	codes, err := api.MFA.Confirm(ctx, userID, code)
+	if err != nil {
+		t.Fatalf("Should be able to confirm the enrollment : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	raw, _, err := api.MFA.Challenge(ctx, userID)
+	if err != nil {
+		t.Fatalf("Should be able to start a challenge : %s", err)
+	}
+
+	if _, err := api.MFA.Verify(ctx, raw, code); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Fatalf("Should NOT be able to reuse a code that was already accepted : %v", err)
+	}
+
+	code, err = totp.Code(enr.Secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("Should be able to generate a code : %s", err)
+	}
+
+	ch, err := api.MFA.Verify(ctx, raw, code)
+	if err != nil {
+		t.Fatalf("Should be able to complete the challenge : %s", err)
+	}
+
+	if ch.UserID != userID {
+		t.Fatalf("Should get back the user of the challenge : %s", ch.UserID)
+	}
+
+	if _, err := api.MFA.Verify(ctx, raw, code); !errors.Is(err, mfa.ErrInvalidChallenge) {
+		t.Fatalf("Should NOT be able to complete a challenge twice : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	raw, _, err = api.MFA.Challenge(ctx, userID)
+	if err != nil {
+		t.Fatalf("Should be able to start a challenge : %s", err)
+	}
+
+	if _, err := api.MFA.Verify(ctx, raw, codes[0]); err != nil {
+		t.Fatalf("Should be able to complete the challenge with a recovery code : %s", err)
+	}
+
+	raw, _, err = api.MFA.Challenge(ctx, userID)
+	if err != nil {
+		t.Fatalf("Should be able to start a challenge : %s", err)
+	}
+
+	if _, err := api.MFA.Verify(ctx, raw, codes[0]); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Fatalf("Should NOT be able to use a recovery code twice : %v", err)
+	}
+
+	for i := 1; i < 5; i++ {
+		if _, err := api.MFA.Verify(ctx, raw, "bad-code"); !errors.Is(err, mfa.ErrInvalidCode) {
+			t.Fatalf("Should NOT be able to complete the challenge with a wrong code : %v", err)
+		}
+	}
+
+	if _, err := api.MFA.Verify(ctx, raw, codes[1]); !errors.Is(err, mfa.ErrInvalidChallenge) {
+		t.Fatalf("Should discard the challenge after too many attempts : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// The wrong codes are counted across the challenges of the user.
+
+	raw, _, err = api.MFA.Challenge(ctx, userID)
+	if err != nil {
+		t.Fatalf("Should be able to start a challenge : %s", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := api.MFA.Verify(ctx, raw, "bad-code"); !errors.Is(err, mfa.ErrInvalidCode) {
+			t.Fatalf("Should NOT be able to complete the challenge with a wrong code : %v", err)
+		}
+	}
+
+	if _, _, err := api.MFA.Challenge(ctx, userID); !errors.Is(err, mfa.ErrLocked) {
+		t.Fatalf("Should NOT be able to start a challenge after too many wrong codes : %v", err)
+	}
+}
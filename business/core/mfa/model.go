@@ -0,0 +1,68 @@
+package mfa
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/totp"
+	"github.com/google/uuid"
+)
+
+// Enrollment represents the TOTP second factor of a user. The enrollment only
+// protects logins once it is confirmed with a code from the user's app. The
+// failed attempts are counted across every challenge of the user.
+type Enrollment struct {
+	UserID         uuid.UUID
+	TenantID       uuid.UUID
+	Secret         string
+	LastStep       int64
+	FailedAttempts int
+	DateCreated    time.Time
+	DateConfirmed  time.Time
+	DateLastFailed time.Time
+}
+
+// Confirmed reports whether the enrollment was confirmed with a code.
+func (e Enrollment) Confirmed() bool {
+	return !e.DateConfirmed.IsZero()
+}
+
+// Locked reports whether the user made too many failed attempts to start or
+// complete a challenge at the specified time.
+func (e Enrollment) Locked(now time.Time) bool {
+	return e.FailedAttempts >= maxFailures && now.Before(e.DateLastFailed.Add(FailureLockout))
+}
+
+// URI returns the provisioning URI authenticator apps read from a QR code.
+func (e Enrollment) URI(issuer string, account string) string {
+	return totp.URI(issuer, account, e.Secret)
+}
+
+// RecoveryCode represents a single use code that can stand in for a TOTP code
+// when the user no longer has access to their app. Only the hash of the code
+// is kept.
+type RecoveryCode struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	TenantID    uuid.UUID
+	Hash        string
+	DateCreated time.Time
+	DateUsed    time.Time
+}
+
+// Challenge represents a login that verified the password and is waiting for
+// the second factor. Only the hash of the challenge token is kept.
+type Challenge struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	TenantID    uuid.UUID
+	Hash        string
+	Attempts    int
+	DateCreated time.Time
+	DateExpires time.Time
+	DateUsed    time.Time
+}
+
+// Used reports whether the challenge was already completed.
+func (ch Challenge) Used() bool {
+	return !ch.DateUsed.IsZero()
+}
@@ -0,0 +1,455 @@
+// Package mfadb contains mfa related CRUD functionality.
+package mfadb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for mfa database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (mfa.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// SaveEnrollment adds or replaces the enrollment of a user in the database.
+// The secret is kept out of the logs of the query.
+func (s *Store) SaveEnrollment(ctx context.Context, enr mfa.Enrollment) error {
+	const q = `
+	INSERT INTO mfa_enrollments
+		(user_id, tenant_id, secret, last_step, date_created, date_confirmed)
+	VALUES
+		(:user_id, :tenant_id, :secret, :last_step, :date_created, :date_confirmed)
+	ON CONFLICT (user_id) DO UPDATE SET
+		"secret" = EXCLUDED.secret,
+		"last_step" = EXCLUDED.last_step,
+		"date_created" = EXCLUDED.date_created,
+		"date_confirmed" = EXCLUDED.date_confirmed`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEnrollment(enr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLastStep records the step of the last TOTP code accepted for a user.
+// The update only succeeds for a step past the stored one, so a code can't be
+// accepted twice. Otherwise mfa.ErrNotFound is returned.
+func (s *Store) UpdateLastStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	data := map[string]interface{}{
+		"user_id":   userID.String(),
+		"last_step": step,
+	}
+
+	const q = `
+	UPDATE
+		mfa_enrollments
+	SET
+		"last_step" = :last_step`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id", "last_step < :last_step")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" RETURNING user_id")
+
+	var dest struct {
+		UserID uuid.UUID `db:"user_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", mfa.ErrNotFound)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEnrollment removes the enrollment of a user from the database.
+func (s *Store) DeleteEnrollment(ctx context.Context, userID uuid.UUID) error {
+	data := map[string]interface{}{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		mfa_enrollments`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryEnrollment gets the enrollment of a user from the database.
+func (s *Store) QueryEnrollment(ctx context.Context, userID uuid.UUID) (mfa.Enrollment, error) {
+	data := map[string]interface{}{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	SELECT
+		user_id, tenant_id, secret, last_step, failed_attempts, date_created, date_confirmed, date_last_failed
+	FROM
+		mfa_enrollments`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return mfa.Enrollment{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbEnr dbEnrollment
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbEnr); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return mfa.Enrollment{}, fmt.Errorf("namedquerystruct: %w", mfa.ErrNotFound)
+		}
+		return mfa.Enrollment{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreEnrollment(dbEnr), nil
+}
+
+// CountFailure counts an attempt of a user as failed. The update only
+// succeeds while the user is under the limit or the last failure happened
+// before since, so concurrent attempts can't go past the limit. Otherwise
+// mfa.ErrLocked is returned.
+func (s *Store) CountFailure(ctx context.Context, userID uuid.UUID, limit int, since time.Time, now time.Time) error {
+	data := map[string]interface{}{
+		"user_id":          userID.String(),
+		"limit":            limit,
+		"since":            since.UTC(),
+		"date_last_failed": now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		mfa_enrollments
+	SET
+		"failed_attempts" = failed_attempts + 1,
+		"date_last_failed" = :date_last_failed`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id", "(failed_attempts < :limit OR date_last_failed < :since)")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" RETURNING user_id")
+
+	var dest struct {
+		UserID uuid.UUID `db:"user_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", mfa.ErrLocked)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// ClearFailures resets the failed attempts of a user.
+func (s *Store) ClearFailures(ctx context.Context, userID uuid.UUID) error {
+	data := map[string]interface{}{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	UPDATE
+		mfa_enrollments
+	SET
+		"failed_attempts" = 0,
+		"date_last_failed" = NULL`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRecoveryCodes adds recovery codes to the database.
+func (s *Store) CreateRecoveryCodes(ctx context.Context, codes []mfa.RecoveryCode) error {
+	const q = `
+	INSERT INTO mfa_recovery_codes
+		(code_id, user_id, tenant_id, code_hash, date_created, date_used)
+	VALUES
+		(:code_id, :user_id, :tenant_id, :code_hash, :date_created, :date_used)`
+
+	for _, code := range codes {
+		if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBRecoveryCode(code)); err != nil {
+			return fmt.Errorf("namedexeccontext: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes removes the recovery codes of a user from the database.
+func (s *Store) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	data := map[string]interface{}{
+		"user_id": userID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		mfa_recovery_codes`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used. The update
+// only succeeds for a code that is not used yet, so a code can't be used
+// twice. Otherwise mfa.ErrNotFound is returned.
+func (s *Store) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string, now time.Time) error {
+	data := map[string]interface{}{
+		"user_id":   userID.String(),
+		"code_hash": hash,
+		"date_used": now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		mfa_recovery_codes
+	SET
+		"date_used" = :date_used`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id", "code_hash = :code_hash", "date_used IS NULL")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" RETURNING code_id")
+
+	var dest struct {
+		ID uuid.UUID `db:"code_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", mfa.ErrNotFound)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// CreateChallenge adds a login challenge to the database.
+func (s *Store) CreateChallenge(ctx context.Context, ch mfa.Challenge) error {
+	const q = `
+	INSERT INTO mfa_challenges
+		(challenge_id, user_id, tenant_id, challenge_hash, attempts, date_created, date_expires, date_used)
+	VALUES
+		(:challenge_id, :user_id, :tenant_id, :challenge_hash, :attempts, :date_created, :date_expires, :date_used)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBChallenge(ch)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// AttemptChallenge takes an attempt from a challenge and returns the number
+// of attempts made. The update only succeeds for a challenge that is not used
+// yet and is under the limit, so concurrent attempts can't go past the limit.
+// Otherwise mfa.ErrInvalidChallenge is returned.
+func (s *Store) AttemptChallenge(ctx context.Context, challengeID uuid.UUID, limit int) (int, error) {
+	data := map[string]interface{}{
+		"challenge_id": challengeID.String(),
+		"limit":        limit,
+	}
+
+	const q = `
+	UPDATE
+		mfa_challenges
+	SET
+		"attempts" = attempts + 1`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "challenge_id = :challenge_id", "date_used IS NULL", "attempts < :limit")
+	if err != nil {
+		return 0, err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" RETURNING attempts")
+
+	var dest struct {
+		Attempts int `db:"attempts"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return 0, fmt.Errorf("namedquerystruct: %w", mfa.ErrInvalidChallenge)
+		}
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dest.Attempts, nil
+}
+
+// UseChallenge marks a challenge as completed. The update only succeeds for a
+// challenge that is not used yet, so two concurrent verifications can't both
+// succeed. The loser gets mfa.ErrInvalidChallenge.
+func (s *Store) UseChallenge(ctx context.Context, challengeID uuid.UUID, now time.Time) error {
+	data := map[string]interface{}{
+		"challenge_id": challengeID.String(),
+		"date_used":    now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		mfa_challenges
+	SET
+		"date_used" = :date_used`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "challenge_id = :challenge_id", "date_used IS NULL")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" RETURNING challenge_id")
+
+	var dest struct {
+		ID uuid.UUID `db:"challenge_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", mfa.ErrInvalidChallenge)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// QueryChallengeByHash finds the challenge with the specified hash.
+func (s *Store) QueryChallengeByHash(ctx context.Context, hash string) (mfa.Challenge, error) {
+	data := map[string]interface{}{
+		"challenge_hash": hash,
+	}
+
+	const q = `
+	SELECT
+		challenge_id, user_id, tenant_id, challenge_hash, attempts, date_created, date_expires, date_used
+	FROM
+		mfa_challenges`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "challenge_hash = :challenge_hash")
+	if err != nil {
+		return mfa.Challenge{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbCh dbChallenge
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbCh); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return mfa.Challenge{}, fmt.Errorf("namedquerystruct: %w", mfa.ErrNotFound)
+		}
+		return mfa.Challenge{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreChallenge(dbCh), nil
+}
+
+// =============================================================================
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
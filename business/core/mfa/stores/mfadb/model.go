@@ -0,0 +1,136 @@
+package mfadb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/google/uuid"
+)
+
+// dbEnrollment represents the stored TOTP second factor of a user.
+type dbEnrollment struct {
+	UserID         uuid.UUID    `db:"user_id"`
+	TenantID       uuid.UUID    `db:"tenant_id"`
+	Secret         db.Redacted  `db:"secret"`
+	LastStep       int64        `db:"last_step"`
+	FailedAttempts int          `db:"failed_attempts"`
+	DateCreated    time.Time    `db:"date_created"`
+	DateConfirmed  sql.NullTime `db:"date_confirmed"`
+	DateLastFailed sql.NullTime `db:"date_last_failed"`
+}
+
+func toDBEnrollment(enr mfa.Enrollment) dbEnrollment {
+	return dbEnrollment{
+		UserID:         enr.UserID,
+		TenantID:       enr.TenantID,
+		Secret:         db.Redacted(enr.Secret),
+		LastStep:       enr.LastStep,
+		FailedAttempts: enr.FailedAttempts,
+		DateCreated:    enr.DateCreated.UTC(),
+		DateConfirmed: sql.NullTime{
+			Time:  enr.DateConfirmed.UTC(),
+			Valid: !enr.DateConfirmed.IsZero(),
+		},
+		DateLastFailed: sql.NullTime{
+			Time:  enr.DateLastFailed.UTC(),
+			Valid: !enr.DateLastFailed.IsZero(),
+		},
+	}
+}
+
+func toCoreEnrollment(dbEnr dbEnrollment) mfa.Enrollment {
+	enr := mfa.Enrollment{
+		UserID:         dbEnr.UserID,
+		TenantID:       dbEnr.TenantID,
+		Secret:         string(dbEnr.Secret),
+		LastStep:       dbEnr.LastStep,
+		FailedAttempts: dbEnr.FailedAttempts,
+		DateCreated:    dbEnr.DateCreated.In(time.Local),
+	}
+
+	if dbEnr.DateConfirmed.Valid {
+		enr.DateConfirmed = dbEnr.DateConfirmed.Time.In(time.Local)
+	}
+
+	if dbEnr.DateLastFailed.Valid {
+		enr.DateLastFailed = dbEnr.DateLastFailed.Time.In(time.Local)
+	}
+
+	return enr
+}
+
+// =============================================================================
+
+// dbRecoveryCode represents a stored recovery code.
+type dbRecoveryCode struct {
+	ID          uuid.UUID    `db:"code_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	TenantID    uuid.UUID    `db:"tenant_id"`
+	Hash        string       `db:"code_hash"`
+	DateCreated time.Time    `db:"date_created"`
+	DateUsed    sql.NullTime `db:"date_used"`
+}
+
+func toDBRecoveryCode(code mfa.RecoveryCode) dbRecoveryCode {
+	return dbRecoveryCode{
+		ID:          code.ID,
+		UserID:      code.UserID,
+		TenantID:    code.TenantID,
+		Hash:        code.Hash,
+		DateCreated: code.DateCreated.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  code.DateUsed.UTC(),
+			Valid: !code.DateUsed.IsZero(),
+		},
+	}
+}
+
+// =============================================================================
+
+// dbChallenge represents a stored login challenge.
+type dbChallenge struct {
+	ID          uuid.UUID    `db:"challenge_id"`
+	UserID      uuid.UUID    `db:"user_id"`
+	TenantID    uuid.UUID    `db:"tenant_id"`
+	Hash        string       `db:"challenge_hash"`
+	Attempts    int          `db:"attempts"`
+	DateCreated time.Time    `db:"date_created"`
+	DateExpires time.Time    `db:"date_expires"`
+	DateUsed    sql.NullTime `db:"date_used"`
+}
+
+func toDBChallenge(ch mfa.Challenge) dbChallenge {
+	return dbChallenge{
+		ID:          ch.ID,
+		UserID:      ch.UserID,
+		TenantID:    ch.TenantID,
+		Hash:        ch.Hash,
+		Attempts:    ch.Attempts,
+		DateCreated: ch.DateCreated.UTC(),
+		DateExpires: ch.DateExpires.UTC(),
+		DateUsed: sql.NullTime{
+			Time:  ch.DateUsed.UTC(),
+			Valid: !ch.DateUsed.IsZero(),
+		},
+	}
+}
+
+func toCoreChallenge(dbCh dbChallenge) mfa.Challenge {
+	ch := mfa.Challenge{
+		ID:          dbCh.ID,
+		UserID:      dbCh.UserID,
+		TenantID:    dbCh.TenantID,
+		Hash:        dbCh.Hash,
+		Attempts:    dbCh.Attempts,
+		DateCreated: dbCh.DateCreated.In(time.Local),
+		DateExpires: dbCh.DateExpires.In(time.Local),
+	}
+
+	if dbCh.DateUsed.Valid {
+		ch.DateUsed = dbCh.DateUsed.Time.In(time.Local)
+	}
+
+	return ch
+}
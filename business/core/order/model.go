@@ -0,0 +1,39 @@
+package order
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Order represents a sale of one or more products to a user.
+type Order struct {
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	UserID      uuid.UUID
+	Status      Status
+	Items       []Item
+	Total       float64
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// Item represents a single product line within an order. The cost is the
+// unit price of the product at the time the order was placed.
+type Item struct {
+	ProductID uuid.UUID
+	Quantity  int
+	Cost      float64
+}
+
+// NewOrder is what we require from clients when placing an Order.
+type NewOrder struct {
+	UserID uuid.UUID
+	Items  []NewItem
+}
+
+// NewItem is what we require from clients for each product in an Order.
+type NewItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
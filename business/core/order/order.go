@@ -0,0 +1,248 @@
+// Package order provides the core business API for recording sales. Placing
+// or cancelling an order adjusts the stock of the products involved, so these
+// calls are expected to be executed under a transaction.
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for CRUD operations.
+var (
+	ErrNotFound          = errors.New("order not found")
+	ErrUserDisabled      = errors.New("user disabled")
+	ErrNoItems           = errors.New("order has no items")
+	ErrInvalidQuantity   = errors.New("quantity not valid")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrProductInactive   = errors.New("product inactive")
+	ErrAlreadyCancelled  = errors.New("order already cancelled")
+)
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, ord Order) error
+	Cancel(ctx context.Context, ord Order) error
+	Query(ctx context.Context, filter QueryFilter, orderBy ordering.By, pageNumber int, rowsPerPage int) ([]Order, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error)
+}
+
+// UserCore interface declares the behavior this package needs from the user
+// core domain.
+type UserCore interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (*user.Core, error)
+	QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error)
+}
+
+// ProductCore interface declares the behavior this package needs from the
+// product core domain.
+type ProductCore interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (*product.Core, error)
+	QueryByID(ctx context.Context, productID uuid.UUID) (product.Product, error)
+	AdjustStock(ctx context.Context, prd product.Product, delta int) (product.Product, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for order access.
+type Core struct {
+	log     *logger.Logger
+	usrCore UserCore
+	prdCore ProductCore
+	storer  Storer
+}
+
+// NewCore constructs a core for order api access.
+func NewCore(log *logger.Logger, usrCore UserCore, prdCore ProductCore, storer Storer) *Core {
+	return &Core{
+		log:     log,
+		usrCore: usrCore,
+		prdCore: prdCore,
+		storer:  storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	prdCore, err := c.prdCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:     c.log,
+		usrCore: usrCore,
+		prdCore: prdCore,
+		storer:  storer,
+	}
+
+	return c, nil
+}
+
+// Create places a new order for the specified user and removes the ordered
+// quantities from the stock of each product. The order belongs to the tenant
+// of its user.
+func (c *Core) Create(ctx context.Context, no NewOrder) (Order, error) {
+	usr, err := c.usrCore.QueryByID(ctx, no.UserID)
+	if err != nil {
+		return Order{}, fmt.Errorf("user.querybyid: %s: %w", no.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return Order{}, ErrUserDisabled
+	}
+
+	if len(no.Items) == 0 {
+		return Order{}, ErrNoItems
+	}
+
+	// Collapse repeated products into a single line so the stock check is
+	// performed against the total quantity being ordered.
+	var productIDs []uuid.UUID
+	quantities := make(map[uuid.UUID]int)
+	for _, ni := range no.Items {
+		if ni.Quantity <= 0 {
+			return Order{}, ErrInvalidQuantity
+		}
+
+		if _, exists := quantities[ni.ProductID]; !exists {
+			productIDs = append(productIDs, ni.ProductID)
+		}
+		quantities[ni.ProductID] += ni.Quantity
+	}
+
+	items := make([]Item, len(productIDs))
+	var total float64
+
+	for i, productID := range productIDs {
+		prd, err := c.prdCore.QueryByID(ctx, productID)
+		if err != nil {
+			return Order{}, fmt.Errorf("product.querybyid: %s: %w", productID, err)
+		}
+
+		if !prd.Active {
+			return Order{}, fmt.Errorf("productID[%s]: %w", productID, ErrProductInactive)
+		}
+
+		quantity := quantities[productID]
+		if prd.Quantity < quantity {
+			return Order{}, fmt.Errorf("productID[%s] available[%d] requested[%d]: %w", productID, prd.Quantity, quantity, ErrInsufficientStock)
+		}
+
+		if _, err := c.prdCore.AdjustStock(ctx, prd, -quantity); err != nil {
+			if errors.Is(err, product.ErrInsufficientStock) {
+				return Order{}, fmt.Errorf("productID[%s] requested[%d]: %w", productID, quantity, ErrInsufficientStock)
+			}
+			return Order{}, fmt.Errorf("product.adjuststock: %s: %w", productID, err)
+		}
+
+		items[i] = Item{
+			ProductID: productID,
+			Quantity:  quantity,
+			Cost:      prd.Cost,
+		}
+		total += prd.Cost * float64(quantity)
+	}
+
+	now := time.Now()
+
+	ord := Order{
+		ID:          uuid.New(),
+		TenantID:    usr.TenantID,
+		UserID:      no.UserID,
+		Status:      StatusPlaced,
+		Items:       items,
+		Total:       total,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, ord); err != nil {
+		return Order{}, fmt.Errorf("create: %w", err)
+	}
+
+	return ord, nil
+}
+
+// Cancel marks the order as cancelled and returns the ordered quantities back
+// to the stock of each product that still exists. The order is cancelled
+// before the stock is returned, so an order cancelled concurrently fails with
+// ErrAlreadyCancelled instead of returning the stock twice.
+func (c *Core) Cancel(ctx context.Context, ord Order) (Order, error) {
+	if ord.Status.Equal(StatusCancelled) {
+		return Order{}, ErrAlreadyCancelled
+	}
+
+	ord.Status = StatusCancelled
+	ord.DateUpdated = time.Now()
+
+	if err := c.storer.Cancel(ctx, ord); err != nil {
+		return Order{}, fmt.Errorf("cancel: %w", err)
+	}
+
+	for _, item := range ord.Items {
+		prd, err := c.prdCore.QueryByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, product.ErrNotFound) {
+				continue
+			}
+			return Order{}, fmt.Errorf("product.querybyid: %s: %w", item.ProductID, err)
+		}
+
+		if _, err := c.prdCore.AdjustStock(ctx, prd, item.Quantity); err != nil {
+			return Order{}, fmt.Errorf("product.adjuststock: %s: %w", item.ProductID, err)
+		}
+	}
+
+	return ord, nil
+}
+
+// Query retrieves a list of existing orders.
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy ordering.By, pageNumber int, rowsPerPage int) ([]Order, error) {
+	ords, err := c.storer.Query(ctx, filter, orderBy, pageNumber, rowsPerPage)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return ords, nil
+}
+
+// Count returns the total number of orders.
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	return c.storer.Count(ctx, filter)
+}
+
+// QueryByID finds the order by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, orderID uuid.UUID) (Order, error) {
+	ord, err := c.storer.QueryByID(ctx, orderID)
+	if err != nil {
+		return Order{}, fmt.Errorf("query: orderID[%s]: %w", orderID, err)
+	}
+
+	return ord, nil
+}
@@ -0,0 +1,152 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Order(t *testing.T) {
+	t.Run("crud", crud)
+}
+
+// =============================================================================
+
+func crud(t *testing.T) {
+	seed := func(ctx context.Context, usrCore *user.Core, prdCore *product.Core) (user.User, product.Product, error) {
+		var filter user.QueryFilter
+		filter.WithName("User Gopher")
+
+		usrs, err := usrCore.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+		if err != nil {
+			return user.User{}, product.Product{}, fmt.Errorf("seeding users : %w", err)
+		}
+
+		np := product.NewProduct{
+			Name:     "Comics",
+			Cost:     10,
+			Quantity: 5,
+			UserID:   usrs[0].ID,
+		}
+
+		prd, err := prdCore.Create(ctx, np)
+		if err != nil {
+			return user.User{}, product.Product{}, fmt.Errorf("seeding products : %w", err)
+		}
+
+		return usrs[0], prd, nil
+	}
+
+	// -------------------------------------------------------------------------
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	usr, prd, err := seed(ctx, api.User, api.Product)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	no := order.NewOrder{
+		UserID: usr.ID,
+		Items: []order.NewItem{
+			{ProductID: prd.ID, Quantity: 6},
+		},
+	}
+
+	if _, err := api.Order.Create(ctx, no); !errors.Is(err, order.ErrInsufficientStock) {
+		t.Fatalf("Should NOT be able to order more than the stock : %v", err)
+	}
+
+	no.Items[0].Quantity = 3
+
+	ord, err := api.Order.Create(ctx, no)
+	if err != nil {
+		t.Fatalf("Should be able to create order : %s", err)
+	}
+
+	if ord.Total != 30 {
+		t.Errorf("Should get back the right total : got %v want %v", ord.Total, 30)
+	}
+
+	saved, err := api.Order.QueryByID(ctx, ord.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve order by ID : %s", err)
+	}
+
+	if len(saved.Items) != 1 || saved.Items[0].Quantity != 3 {
+		t.Fatalf("Should get back the ordered items : %+v", saved.Items)
+	}
+
+	stock, err := api.Product.QueryByID(ctx, prd.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve product by ID : %s", err)
+	}
+
+	if stock.Quantity != 2 {
+		t.Fatalf("Should have decremented the product stock : got %d want %d", stock.Quantity, 2)
+	}
+
+	// -------------------------------------------------------------------------
+
+	saved, err = api.Order.Cancel(ctx, saved)
+	if err != nil {
+		t.Fatalf("Should be able to cancel order : %s", err)
+	}
+
+	if !saved.Status.Equal(order.StatusCancelled) {
+		t.Fatalf("Should have a cancelled status : got %s", saved.Status.Name())
+	}
+
+	if _, err := api.Order.Cancel(ctx, saved); !errors.Is(err, order.ErrAlreadyCancelled) {
+		t.Fatalf("Should NOT be able to cancel order twice : %v", err)
+	}
+
+	stock, err = api.Product.QueryByID(ctx, prd.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve product by ID : %s", err)
+	}
+
+	if stock.Quantity != 5 {
+		t.Fatalf("Should have restored the product stock : got %d want %d", stock.Quantity, 5)
+	}
+}
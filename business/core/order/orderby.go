@@ -0,0 +1,16 @@
+package order
+
+import ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+
+// DefaultOrderBy represents the default way we sort.
+var DefaultOrderBy = ordering.NewBy(OrderByOrderID, ordering.ASC)
+
+// Set of fields that the results can be ordered by. These are the names
+// that should be used by the application layer.
+const (
+	OrderByOrderID     = "order_id"
+	OrderByUserID      = "user_id"
+	OrderByStatus      = "status"
+	OrderByTotal       = "total"
+	OrderByDateCreated = "date_created"
+)
@@ -0,0 +1,67 @@
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+)
+
+func (s *Store) applyFilter(ctx context.Context, filter order.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) error {
+	var wc []string
+
+	if filter.ID != nil {
+		data["order_id"] = *filter.ID
+		wc = append(wc, "order_id = :order_id")
+	}
+
+	if filter.UserID != nil {
+		data["user_id"] = *filter.UserID
+		wc = append(wc, "user_id = :user_id")
+	}
+
+	if filter.Status != nil {
+		data["status"] = filter.Status.Name()
+		wc = append(wc, "status = :status")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = *filter.StartCreatedDate
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = *filter.EndCreatedDate
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	clause, err := where(ctx, data, wc...)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	return nil
+}
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
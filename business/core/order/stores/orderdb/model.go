@@ -0,0 +1,103 @@
+package orderdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/google/uuid"
+)
+
+// dbOrder represents an individual order.
+type dbOrder struct {
+	ID          uuid.UUID `db:"order_id"`     // Unique identifier.
+	TenantID    uuid.UUID `db:"tenant_id"`    // Tenant the order belongs to.
+	UserID      uuid.UUID `db:"user_id"`      // ID of the user who placed the order.
+	Status      string    `db:"status"`       // Current status of the order.
+	Total       float64   `db:"total"`        // Sum of the cost of every item.
+	DateCreated time.Time `db:"date_created"` // When the order was placed.
+	DateUpdated time.Time `db:"date_updated"` // When the order record was last modified.
+}
+
+// dbItem represents an individual product line inside an order.
+type dbItem struct {
+	OrderID   uuid.UUID `db:"order_id"`   // ID of the order this line belongs to.
+	TenantID  uuid.UUID `db:"tenant_id"`  // Tenant the order belongs to.
+	ProductID uuid.UUID `db:"product_id"` // ID of the product being sold.
+	Quantity  int       `db:"quantity"`   // Number of items sold.
+	Cost      float64   `db:"cost"`       // Price for one item when the order was placed.
+}
+
+// =============================================================================
+
+func toDBOrder(ord order.Order) dbOrder {
+	return dbOrder{
+		ID:          ord.ID,
+		TenantID:    ord.TenantID,
+		UserID:      ord.UserID,
+		Status:      ord.Status.Name(),
+		Total:       ord.Total,
+		DateCreated: ord.DateCreated.UTC(),
+		DateUpdated: ord.DateUpdated.UTC(),
+	}
+}
+
+func toDBItems(ord order.Order) []dbItem {
+	items := make([]dbItem, len(ord.Items))
+	for i, item := range ord.Items {
+		items[i] = dbItem{
+			OrderID:   ord.ID,
+			TenantID:  ord.TenantID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Cost:      item.Cost,
+		}
+	}
+	return items
+}
+
+func toCoreOrder(dbOrd dbOrder, dbItems []dbItem) (order.Order, error) {
+	status, err := order.ParseStatus(dbOrd.Status)
+	if err != nil {
+		return order.Order{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	items := make([]order.Item, len(dbItems))
+	for i, dbItm := range dbItems {
+		items[i] = order.Item{
+			ProductID: dbItm.ProductID,
+			Quantity:  dbItm.Quantity,
+			Cost:      dbItm.Cost,
+		}
+	}
+
+	ord := order.Order{
+		ID:          dbOrd.ID,
+		TenantID:    dbOrd.TenantID,
+		UserID:      dbOrd.UserID,
+		Status:      status,
+		Items:       items,
+		Total:       dbOrd.Total,
+		DateCreated: dbOrd.DateCreated.In(time.Local),
+		DateUpdated: dbOrd.DateUpdated.In(time.Local),
+	}
+
+	return ord, nil
+}
+
+func toCoreOrderSlice(dbOrders []dbOrder, dbItems []dbItem) ([]order.Order, error) {
+	itemsByOrder := make(map[uuid.UUID][]dbItem)
+	for _, dbItm := range dbItems {
+		itemsByOrder[dbItm.OrderID] = append(itemsByOrder[dbItm.OrderID], dbItm)
+	}
+
+	ords := make([]order.Order, len(dbOrders))
+	for i, dbOrd := range dbOrders {
+		var err error
+		ords[i], err = toCoreOrder(dbOrd, itemsByOrder[dbOrd.ID])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ords, nil
+}
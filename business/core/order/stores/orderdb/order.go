@@ -0,0 +1,33 @@
+package orderdb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	order.OrderByOrderID:     "order_id",
+	order.OrderByUserID:      "user_id",
+	order.OrderByStatus:      "status",
+	order.OrderByTotal:       "total",
+	order.OrderByDateCreated: "date_created",
+}
+
+func orderByClause(orderBy ordering.By) (string, error) {
+	fields := orderBy.Fields()
+
+	clauses := make([]string, len(fields))
+	for i, field := range fields {
+		by, exists := orderByFields[field.Field]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", field.Field)
+		}
+
+		clauses[i] = by + " " + field.Direction
+	}
+
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
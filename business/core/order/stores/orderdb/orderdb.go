@@ -0,0 +1,246 @@
+// Package orderdb contains order related CRUD functionality.
+package orderdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for order database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (order.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds an Order and its items to the database.
+func (s *Store) Create(ctx context.Context, ord order.Order) error {
+	const q = `
+	INSERT INTO orders
+		(order_id, tenant_id, user_id, status, total, date_created, date_updated)
+	VALUES
+		(:order_id, :tenant_id, :user_id, :status, :total, :date_created, :date_updated)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBOrder(ord)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	const qi = `
+	INSERT INTO order_items
+		(order_id, tenant_id, product_id, quantity, cost)
+	VALUES
+		(:order_id, :tenant_id, :product_id, :quantity, :cost)`
+
+	for _, item := range toDBItems(ord) {
+		if err := db.NamedExecContext(ctx, s.log, s.db, qi, item); err != nil {
+			return fmt.Errorf("namedexeccontext: item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Cancel marks an Order as cancelled. The items of an order are never
+// modified once it has been placed. The update only succeeds for an order
+// that is not cancelled yet, so an order can't be cancelled twice. Otherwise
+// order.ErrAlreadyCancelled is returned.
+func (s *Store) Cancel(ctx context.Context, ord order.Order) error {
+	const q = `
+	UPDATE
+		orders
+	SET
+		"status" = :status,
+		"date_updated" = :date_updated
+	WHERE
+		order_id = :order_id AND tenant_id = :tenant_id AND status <> 'CANCELLED'`
+
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, toDBOrder(ord))
+	if err != nil {
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", order.ErrAlreadyCancelled)
+	}
+
+	return nil
+}
+
+// Query gets all Orders from the database.
+func (s *Store) Query(ctx context.Context, filter order.QueryFilter, orderBy ordering.By, pageNumber int, rowsPerPage int) ([]order.Order, error) {
+	data := map[string]interface{}{
+		"offset":        (pageNumber - 1) * rowsPerPage,
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		order_id, tenant_id, user_id, status, total, date_created, date_updated
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbOrds []dbOrder
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbOrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	orderIDs := make([]uuid.UUID, len(dbOrds))
+	for i, dbOrd := range dbOrds {
+		orderIDs[i] = dbOrd.ID
+	}
+
+	dbItems, err := s.queryItems(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCoreOrderSlice(dbOrds, dbItems)
+}
+
+// Count returns the total number of orders in the DB.
+func (s *Store) Count(ctx context.Context, filter order.QueryFilter) (int, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, err
+	}
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+// QueryByID finds the order identified by a given ID.
+func (s *Store) QueryByID(ctx context.Context, orderID uuid.UUID) (order.Order, error) {
+	data := map[string]interface{}{
+		"order_id": orderID.String(),
+	}
+
+	const q = `
+	SELECT
+		order_id, tenant_id, user_id, status, total, date_created, date_updated
+	FROM
+		orders`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "order_id = :order_id")
+	if err != nil {
+		return order.Order{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbOrd dbOrder
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbOrd); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return order.Order{}, fmt.Errorf("namedquerystruct: %w", order.ErrNotFound)
+		}
+		return order.Order{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	dbItems, err := s.queryItems(ctx, []uuid.UUID{orderID})
+	if err != nil {
+		return order.Order{}, err
+	}
+
+	return toCoreOrder(dbOrd, dbItems)
+}
+
+// =============================================================================
+
+// queryItems retrieves the items that belong to the specified orders.
+func (s *Store) queryItems(ctx context.Context, orderIDs []uuid.UUID) ([]dbItem, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(orderIDs))
+	for i, orderID := range orderIDs {
+		ids[i] = orderID.String()
+	}
+
+	data := map[string]interface{}{
+		"order_id": dbarray.Array(ids),
+	}
+
+	const q = `
+	SELECT
+		order_id, tenant_id, product_id, quantity, cost
+	FROM
+		order_items`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "order_id = ANY(:order_id)")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" ORDER BY product_id")
+
+	var dbItems []dbItem
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbItems); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: items: %w", err)
+	}
+
+	return dbItems, nil
+}
@@ -0,0 +1,31 @@
+package product
+
+import (
+	"github.com/google/uuid"
+)
+
+// AuditEntity represents the name of products in the audit log.
+const AuditEntity = "product"
+
+// auditProduct is the representation of a product recorded in the audit log.
+type auditProduct struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenantID"`
+	UserID   uuid.UUID `json:"userID"`
+	Name     string    `json:"name"`
+	Cost     float64   `json:"cost"`
+	Quantity int       `json:"quantity"`
+	Active   bool      `json:"active"`
+}
+
+func toAuditProduct(prd Product) auditProduct {
+	return auditProduct{
+		ID:       prd.ID,
+		TenantID: prd.TenantID,
+		UserID:   prd.UserID,
+		Name:     prd.Name,
+		Cost:     prd.Cost,
+		Quantity: prd.Quantity,
+		Active:   prd.Active,
+	}
+}
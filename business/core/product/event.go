@@ -2,7 +2,7 @@ package product
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
@@ -13,18 +13,46 @@ func (c *Core) registerEventHandlers() {
 	c.evnCore.AddHandler(user.EventSource, user.EventUpdated, c.handleUserUpdatedEvent)
 }
 
+// handleUserUpdatedEvent deactivates all the products of a user that has been
+// disabled and reactivates them when the user is enabled again. Events can be
+// delivered late and out of order, so the current state of the user is read
+// instead of trusting the one carried by the event. Products that already have
+// the expected status are left untouched so the event can be delivered more
+// than once.
 func (c *Core) handleUserUpdatedEvent(ctx context.Context, ev event.Event) error {
-	var params user.EventParamsUpdated
-	err := json.Unmarshal(ev.RawParams, &params)
+	params, err := user.UnmarshalUpdated(ev.RawParams)
 	if err != nil {
-		return fmt.Errorf("expected an encoded %T: %w", params, err)
+		return err
 	}
 
 	c.log.Info(ctx, "user update event", "user_id", params.UserID, "enabled", params.Enabled)
 
-	// Now we can see if this user has been disabled. If they have been, we will
-	// want to disable to mark all these products as deleted. Right now we don't
-	// have support for this, but you can see how we can process the event.
+	if params.Enabled == nil {
+		return nil
+	}
+
+	usr, err := c.usrCore.QueryByID(ctx, params.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("querybyid: userID[%s]: %w", params.UserID, err)
+	}
+
+	prds, err := c.storer.QueryByUserID(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("querybyuserid: userID[%s]: %w", usr.ID, err)
+	}
+
+	for _, prd := range prds {
+		if prd.Active == usr.Enabled {
+			continue
+		}
+
+		if _, err := c.SetActive(ctx, prd, usr.Enabled); err != nil {
+			return fmt.Errorf("setactive: productID[%s]: %w", prd.ID, err)
+		}
+	}
 
 	return nil
 }
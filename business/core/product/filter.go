@@ -2,7 +2,9 @@ package product
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
@@ -13,6 +15,17 @@ type QueryFilter struct {
 	Name     *string    `validate:"omitempty,min=3"`
 	Cost     *float64   `validate:"omitempty,numeric"`
 	Quantity *int       `validate:"omitempty,numeric"`
+	Search   *string    `validate:"omitempty,min=2"`
+	IDs      []uuid.UUID
+	UserIDs  []uuid.UUID
+
+	CostRange        query.Range[float64]
+	QuantityRange    query.Range[int]
+	DateCreatedRange query.Range[time.Time]
+
+	// IncludeInactive returns the products that have been deactivated along
+	// with the active ones. Inactive products are hidden by default.
+	IncludeInactive bool
 }
 
 // Validate checks the data in the model is considered clean.
@@ -42,3 +55,39 @@ func (qf *QueryFilter) WithCost(cost float64) {
 func (qf *QueryFilter) WithQuantity(quantity int) {
 	qf.Quantity = &quantity
 }
+
+// WithSearch sets the Search field of the QueryFilter value.
+func (qf *QueryFilter) WithSearch(search string) {
+	qf.Search = &search
+}
+
+// WithProductIDs sets the IDs field of the QueryFilter value.
+func (qf *QueryFilter) WithProductIDs(productIDs []uuid.UUID) {
+	qf.IDs = productIDs
+}
+
+// WithUserIDs sets the UserIDs field of the QueryFilter value.
+func (qf *QueryFilter) WithUserIDs(userIDs []uuid.UUID) {
+	qf.UserIDs = userIDs
+}
+
+// WithCostRange sets the CostRange field of the QueryFilter value.
+func (qf *QueryFilter) WithCostRange(cost query.Range[float64]) {
+	qf.CostRange = cost
+}
+
+// WithQuantityRange sets the QuantityRange field of the QueryFilter value.
+func (qf *QueryFilter) WithQuantityRange(quantity query.Range[int]) {
+	qf.QuantityRange = quantity
+}
+
+// WithDateCreatedRange sets the DateCreatedRange field of the QueryFilter
+// value.
+func (qf *QueryFilter) WithDateCreatedRange(dateCreated query.Range[time.Time]) {
+	qf.DateCreatedRange = dateCreated
+}
+
+// WithIncludeInactive sets the IncludeInactive field of the QueryFilter value.
+func (qf *QueryFilter) WithIncludeInactive(include bool) {
+	qf.IncludeInactive = include
+}
@@ -9,12 +9,20 @@ import (
 // Product represents an individual product.
 type Product struct {
 	ID          uuid.UUID
+	TenantID    uuid.UUID
 	UserID      uuid.UUID
 	Name        string
 	Cost        float64
 	Quantity    int
+	Active      bool
 	DateCreated time.Time
 	DateUpdated time.Time
+	Version     int
+
+	// Snippet holds the HTML escaped name with the terms of a search
+	// highlighted. It's only set on the products returned by a query
+	// searching them.
+	Snippet string
 }
 
 // NewProduct is what we require from clients when adding a Product.
@@ -1,6 +1,11 @@
 package product
 
-import "github.com/diegomagalhaes-dev/go-service/business/data/order"
+import (
+	"strconv"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
+)
 
 // DefaultOrderBy represents the default way we sort.
 var DefaultOrderBy = order.NewBy(OrderByProdID, order.ASC)
@@ -15,4 +20,30 @@ const (
 	OrderBySold     = "sold"
 	OrderByRevenue  = "revenue"
 	OrderByUserID   = "user_id"
+
+	// OrderByRelevance orders by how well the products match a search, it
+	// can only be used by a query searching the products.
+	OrderByRelevance = "relevance"
 )
+
+// cursorKey returns the key of the product within the ordering by the field.
+func cursorKey(prd Product, field string) page.Key {
+	key := page.Key{
+		ID: prd.ID.String(),
+	}
+
+	switch field {
+	case OrderByProdID:
+		key.Value = prd.ID.String()
+	case OrderByName:
+		key.Value = prd.Name
+	case OrderByCost:
+		key.Value = strconv.FormatFloat(prd.Cost, 'f', -1, 64)
+	case OrderByQuantity:
+		key.Value = strconv.Itoa(prd.Quantity)
+	case OrderByUserID:
+		key.Value = prd.UserID.String()
+	}
+
+	return key
+}
@@ -1,6 +1,5 @@
-// Package product provides an example of a core business API. Right now these
-// calls are just wrapping the data/store layer. But at some point you will
-// want auditing or something that isn't specific to the data/store layer.
+// Package product provides an example of a core business API. These calls wrap
+// the data/store layer and record every mutation in the audit log.
 package product
 
 import (
@@ -9,9 +8,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/google/uuid"
@@ -22,6 +23,14 @@ var (
 	ErrNotFound     = errors.New("product not found")
 	ErrUserDisabled = errors.New("user disabled")
 	ErrInvalidCost  = errors.New("cost not valid")
+
+	// ErrInsufficientStock is returned when an adjustment would take the
+	// stock of the product below zero.
+	ErrInsufficientStock = errors.New("insufficient stock")
+
+	// ErrConcurrentModification is returned when the product was changed by
+	// someone else since it was read.
+	ErrConcurrentModification = errors.New("product was modified concurrently")
 )
 
 // =============================================================================
@@ -31,9 +40,13 @@ var (
 type Storer interface {
 	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
 	Create(ctx context.Context, prd Product) error
+	CreateBatch(ctx context.Context, prds []Product) error
 	Update(ctx context.Context, prd Product) error
+	AdjustQuantity(ctx context.Context, prd Product, delta int) (quantity int, version int, err error)
 	Delete(ctx context.Context, prd Product) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Product, error)
+	QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]Product, error)
+	QueryStream(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, productID uuid.UUID) (Product, error)
 	QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product, error)
@@ -44,6 +57,7 @@ type Storer interface {
 type UserCore interface {
 	ExecuteUnderTransaction(tx transaction.Transaction) (*user.Core, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error)
+	QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]user.User, error)
 }
 
 // =============================================================================
@@ -52,15 +66,17 @@ type UserCore interface {
 type Core struct {
 	log     *logger.Logger
 	evnCore *event.Core
+	audCore *audit.Core
 	usrCore UserCore
 	storer  Storer
 }
 
 // NewCore constructs a core for product api access.
-func NewCore(log *logger.Logger, evnCore *event.Core, usrCore UserCore, storer Storer) *Core {
+func NewCore(log *logger.Logger, evnCore *event.Core, audCore *audit.Core, usrCore UserCore, storer Storer) *Core {
 	c := Core{
 		log:     log,
 		evnCore: evnCore,
+		audCore: audCore,
 		usrCore: usrCore,
 		storer:  storer,
 	}
@@ -78,6 +94,16 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 		return nil, err
 	}
 
+	evnCore, err := c.evnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	audCore, err := c.audCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
 	if err != nil {
 		return nil, err
@@ -85,7 +111,8 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 
 	c = &Core{
 		storer:  storer,
-		evnCore: c.evnCore,
+		evnCore: evnCore,
+		audCore: audCore,
 		usrCore: usrCore,
 		log:     c.log,
 	}
@@ -100,35 +127,91 @@ func (c *Core) Create(ctx context.Context, np NewProduct) (Product, error) {
 		return Product{}, fmt.Errorf("user.querybyid: %s: %w", np.UserID, err)
 	}
 
-	if np.Cost < 0 {
-		return Product{}, ErrInvalidCost
+	prd, err := newProduct(np, usr, time.Now())
+	if err != nil {
+		return Product{}, err
 	}
 
-	if !usr.Enabled {
-		return Product{}, ErrUserDisabled
+	if err := c.storer.Create(ctx, prd); err != nil {
+		return Product{}, fmt.Errorf("create: %w", err)
+	}
+
+	if err := c.recordCreate(ctx, prd); err != nil {
+		return Product{}, err
+	}
+
+	return prd, nil
+}
+
+// PrepareBatch checks the new products of a batch and builds the products to
+// create from them. The users of the batch are read at once. A new product
+// failing the checks gets its error at its index and a zero product, the
+// checks of the others are not affected.
+func (c *Core) PrepareBatch(ctx context.Context, nps []NewProduct) ([]Product, []error, error) {
+	seen := make(map[uuid.UUID]bool)
+
+	var userIDs []uuid.UUID
+	for _, np := range nps {
+		if !seen[np.UserID] {
+			seen[np.UserID] = true
+			userIDs = append(userIDs, np.UserID)
+		}
+	}
+
+	usrs := make(map[uuid.UUID]user.User, len(userIDs))
+	if len(userIDs) > 0 {
+		found, err := c.usrCore.QueryByIDs(ctx, userIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("user.querybyids: %w", err)
+		}
+
+		for _, usr := range found {
+			usrs[usr.ID] = usr
+		}
 	}
 
 	now := time.Now()
 
-	prd := Product{
-		ID:          uuid.New(),
-		Name:        np.Name,
-		Cost:        np.Cost,
-		Quantity:    np.Quantity,
-		UserID:      np.UserID,
-		DateCreated: now,
-		DateUpdated: now,
+	prds := make([]Product, len(nps))
+	errs := make([]error, len(nps))
+
+	for i, np := range nps {
+		usr, exists := usrs[np.UserID]
+		if !exists {
+			errs[i] = fmt.Errorf("user.querybyids: %s: %w", np.UserID, user.ErrNotFound)
+			continue
+		}
+
+		prds[i], errs[i] = newProduct(np, usr, now)
 	}
 
-	if err := c.storer.Create(ctx, prd); err != nil {
-		return Product{}, fmt.Errorf("create: %w", err)
+	return prds, errs, nil
+}
+
+// CreateBatch adds the products built by PrepareBatch to the system with a
+// single insert.
+func (c *Core) CreateBatch(ctx context.Context, prds []Product) error {
+	if len(prds) == 0 {
+		return nil
 	}
 
-	return prd, nil
+	if err := c.storer.CreateBatch(ctx, prds); err != nil {
+		return fmt.Errorf("createbatch: %w", err)
+	}
+
+	for _, prd := range prds {
+		if err := c.recordCreate(ctx, prd); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Update modifies information about a product.
 func (c *Core) Update(ctx context.Context, prd Product, up UpdateProduct) (Product, error) {
+	before := prd
+
 	if up.Name != nil {
 		prd.Name = *up.Name
 	}
@@ -142,11 +225,88 @@ func (c *Core) Update(ctx context.Context, prd Product, up UpdateProduct) (Produ
 	}
 
 	prd.DateUpdated = time.Now()
+	prd.Version++
+
+	if err := c.storer.Update(ctx, prd); err != nil {
+		return Product{}, fmt.Errorf("update: %w", err)
+	}
+
+	ne := audit.NewEntry{
+		TenantID: prd.TenantID,
+		Entity:   AuditEntity,
+		EntityID: prd.ID,
+		Action:   audit.ActionUpdate,
+		Before:   toAuditProduct(before),
+		After:    toAuditProduct(prd),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return Product{}, fmt.Errorf("record: %w", err)
+	}
+
+	return prd, nil
+}
+
+// AdjustStock adds the delta to the stock of the product, a negative delta
+// removes stock. The stock is adjusted in place by the store, so concurrent
+// adjustments all apply, and an adjustment that would take the stock below
+// zero fails with ErrInsufficientStock.
+func (c *Core) AdjustStock(ctx context.Context, prd Product, delta int) (Product, error) {
+	prd.DateUpdated = time.Now()
+
+	quantity, version, err := c.storer.AdjustQuantity(ctx, prd, delta)
+	if err != nil {
+		return Product{}, fmt.Errorf("adjustquantity: %w", err)
+	}
+
+	before := prd
+	before.Quantity = quantity - delta
+	before.Version = version - 1
+
+	prd.Quantity = quantity
+	prd.Version = version
+
+	ne := audit.NewEntry{
+		TenantID: prd.TenantID,
+		Entity:   AuditEntity,
+		EntityID: prd.ID,
+		Action:   audit.ActionUpdate,
+		Before:   toAuditProduct(before),
+		After:    toAuditProduct(prd),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return Product{}, fmt.Errorf("record: %w", err)
+	}
+
+	return prd, nil
+}
+
+// SetActive activates or deactivates the product.
+func (c *Core) SetActive(ctx context.Context, prd Product, active bool) (Product, error) {
+	before := prd
+
+	prd.Active = active
+	prd.DateUpdated = time.Now()
+	prd.Version++
 
 	if err := c.storer.Update(ctx, prd); err != nil {
 		return Product{}, fmt.Errorf("update: %w", err)
 	}
 
+	ne := audit.NewEntry{
+		TenantID: prd.TenantID,
+		Entity:   AuditEntity,
+		EntityID: prd.ID,
+		Action:   audit.ActionUpdate,
+		Before:   toAuditProduct(before),
+		After:    toAuditProduct(prd),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return Product{}, fmt.Errorf("record: %w", err)
+	}
+
 	return prd, nil
 }
 
@@ -156,6 +316,18 @@ func (c *Core) Delete(ctx context.Context, prd Product) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	ne := audit.NewEntry{
+		TenantID: prd.TenantID,
+		Entity:   AuditEntity,
+		EntityID: prd.ID,
+		Action:   audit.ActionDelete,
+		Before:   toAuditProduct(prd),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+
 	return nil
 }
 
@@ -169,6 +341,32 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 	return prds, nil
 }
 
+// QueryByCursor retrieves a page of existing products past the key of the
+// cursor, along with the cursors to the pages around it.
+func (c *Core) QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]Product, page.Cursors, error) {
+	prds, err := c.storer.QueryByCursor(ctx, filter, cur, rowsPerPage+1)
+	if err != nil {
+		return nil, page.Cursors{}, fmt.Errorf("querybycursor: %w", err)
+	}
+
+	prds, cursors := page.Keyset(prds, cur, rowsPerPage, func(prd Product) page.Key {
+		return cursorKey(prd, cur.OrderBy.Field)
+	})
+
+	return prds, cursors, nil
+}
+
+// QueryStream hands every product matching the filter to fn in order, one at
+// a time, without holding them all in memory. It stops at the first error fn
+// returns.
+func (c *Core) QueryStream(ctx context.Context, filter QueryFilter, orderBy order.By, fn func(Product) error) error {
+	if err := c.storer.QueryStream(ctx, filter, orderBy, fn); err != nil {
+		return fmt.Errorf("querystream: %w", err)
+	}
+
+	return nil
+}
+
 // Count returns the total number of products.
 func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return c.storer.Count(ctx, filter)
@@ -193,3 +391,49 @@ func (c *Core) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]Product,
 
 	return prds, nil
 }
+
+// =============================================================================
+
+// newProduct checks the new product against its user and builds the product
+// to create from it.
+func newProduct(np NewProduct, usr user.User, now time.Time) (Product, error) {
+	if np.Cost < 0 {
+		return Product{}, ErrInvalidCost
+	}
+
+	if !usr.Enabled {
+		return Product{}, ErrUserDisabled
+	}
+
+	prd := Product{
+		ID:          uuid.New(),
+		Name:        np.Name,
+		Cost:        np.Cost,
+		Quantity:    np.Quantity,
+		Active:      true,
+		UserID:      np.UserID,
+		TenantID:    usr.TenantID,
+		DateCreated: now,
+		DateUpdated: now,
+		Version:     1,
+	}
+
+	return prd, nil
+}
+
+// recordCreate records the creation of the product in the audit log.
+func (c *Core) recordCreate(ctx context.Context, prd Product) error {
+	ne := audit.NewEntry{
+		TenantID: prd.TenantID,
+		Entity:   AuditEntity,
+		EntityID: prd.ID,
+		Action:   audit.ActionCreate,
+		After:    toAuditProduct(prd),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+
+	return nil
+}
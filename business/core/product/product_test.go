@@ -6,16 +6,22 @@ import (
 	"fmt"
 	"net/mail"
 	"runtime/debug"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 )
 
 var c *docker.Container
@@ -36,6 +42,11 @@ func Test_Product(t *testing.T) {
 	t.Run("crud", crud)
 	t.Run("paging", paging)
 	t.Run("transaction", tran)
+	t.Run("cascade", cascade)
+	t.Run("concurrency", concurrency)
+	t.Run("search", search)
+	t.Run("batch", batch)
+	t.Run("stream", stream)
 }
 
 // =============================================================================
@@ -71,7 +82,7 @@ func crud(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	t.Log("Go seeding ...")
@@ -222,7 +233,7 @@ func paging(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	t.Log("Go seeding ...")
@@ -303,7 +314,7 @@ func tran(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	// -------------------------------------------------------------------------
@@ -454,3 +465,438 @@ func tran(t *testing.T) {
 		t.Fatal("Should have products in the DB.")
 	}
 }
+
+func cascade(t *testing.T) {
+	seed := func(ctx context.Context, usrCore *user.Core, prdCore *product.Core) (user.User, []product.Product, error) {
+		var filter user.QueryFilter
+		filter.WithName("User Gopher")
+
+		usrs, err := usrCore.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+		if err != nil {
+			return user.User{}, nil, fmt.Errorf("seeding users : %w", err)
+		}
+
+		prds, err := product.TestGenerateSeedProducts(2, prdCore, usrs[0].ID)
+		if err != nil {
+			return user.User{}, nil, fmt.Errorf("seeding products : %w", err)
+		}
+
+		return usrs[0], prds, nil
+	}
+
+	// -------------------------------------------------------------------------
+
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	usr, prds, err := seed(ctx, api.User, api.Product)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	count := func(includeInactive bool) int {
+		var filter product.QueryFilter
+		filter.WithIncludeInactive(includeInactive)
+
+		n, err := api.Product.Count(ctx, filter)
+		if err != nil {
+			t.Fatalf("Should be able to count products : %s", err)
+		}
+
+		return n
+	}
+
+	active := count(false)
+	all := count(true)
+
+	// -------------------------------------------------------------------------
+
+	usr, err = api.User.Update(ctx, usr, user.UpdateUser{Enabled: dbtest.BoolPointer(false)})
+	if err != nil {
+		t.Fatalf("Should be able to disable user : %s", err)
+	}
+
+	if _, err := api.Event.ProcessPending(ctx, event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver events : %s", err)
+	}
+
+	for _, prd := range prds {
+		saved, err := api.Product.QueryByID(ctx, prd.ID)
+		if err != nil {
+			t.Fatalf("Should be able to retrieve product by ID : %s", err)
+		}
+
+		if saved.Active {
+			t.Fatalf("Should have deactivated the product of a disabled user : %s", prd.ID)
+		}
+	}
+
+	if got := count(false); got != active-len(prds) {
+		t.Fatalf("Should hide inactive products by default : got %d want %d", got, active-len(prds))
+	}
+
+	if got := count(true); got != all {
+		t.Fatalf("Should see inactive products when requested : got %d want %d", got, all)
+	}
+
+	var af audit.QueryFilter
+	af.WithEntityID(prds[0].ID)
+	af.WithAction(audit.ActionUpdate)
+	af.WithActor(audit.ActorSystem)
+
+	if n, err := api.Audit.Count(ctx, af); err != nil || n != 1 {
+		t.Fatalf("Should have audited the deactivation of the product : %d : %v", n, err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if _, err := api.User.Update(ctx, usr, user.UpdateUser{Enabled: dbtest.BoolPointer(true)}); err != nil {
+		t.Fatalf("Should be able to enable user : %s", err)
+	}
+
+	if _, err := api.Event.ProcessPending(ctx, event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver events : %s", err)
+	}
+
+	if got := count(false); got != active {
+		t.Fatalf("Should have reactivated the products of the user : got %d want %d", got, active)
+	}
+
+	// -------------------------------------------------------------------------
+
+	stale := user.UpdateUser{Enabled: dbtest.BoolPointer(false)}
+	if err := api.Event.SendEvent(ctx, stale.UpdatedEvent(usr.ID)); err != nil {
+		t.Fatalf("Should be able to send a stale event : %s", err)
+	}
+
+	if _, err := api.Event.ProcessPending(ctx, event.RelayConfig{}); err != nil {
+		t.Fatalf("Should be able to deliver events : %s", err)
+	}
+
+	if got := count(false); got != active {
+		t.Fatalf("Should follow the current state of the user over a stale event : got %d want %d", got, active)
+	}
+}
+
+func concurrency(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	var filter user.QueryFilter
+	filter.WithName("Admin Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	prds, err := product.TestGenerateSeedProducts(1, api.Product, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if prds[0].Version != 1 {
+		t.Fatalf("Should create the product at the first version : got %d", prds[0].Version)
+	}
+
+	upd, err := api.Product.Update(ctx, prds[0], product.UpdateProduct{Name: dbtest.StringPointer("Comics")})
+	if err != nil {
+		t.Fatalf("Should be able to update the product : %s", err)
+	}
+
+	if upd.Version != 2 {
+		t.Fatalf("Should move the product to the next version : got %d", upd.Version)
+	}
+
+	// The first copy of the product is stale now.
+	if _, err := api.Product.Update(ctx, prds[0], product.UpdateProduct{Name: dbtest.StringPointer("Novels")}); !errors.Is(err, product.ErrConcurrentModification) {
+		t.Fatalf("Should NOT be able to update a stale product : %v", err)
+	}
+
+	saved, err := api.Product.QueryByID(ctx, prds[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the product : %s", err)
+	}
+
+	if saved.Name != "Comics" || saved.Version != 2 {
+		t.Fatalf("Should keep the first update : got %q at version %d", saved.Name, saved.Version)
+	}
+
+	if err := api.Product.Delete(ctx, prds[0]); !errors.Is(err, product.ErrConcurrentModification) {
+		t.Fatalf("Should NOT be able to delete a stale product : %v", err)
+	}
+
+	if err := api.Product.Delete(ctx, saved); err != nil {
+		t.Fatalf("Should be able to delete the current product : %s", err)
+	}
+}
+
+func search(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	var filter user.QueryFilter
+	filter.WithName("Admin Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	for _, name := range []string{"Comic Books", "Comic Book Covers", "Board Games"} {
+		np := product.NewProduct{
+			Name:     name,
+			Cost:     10,
+			Quantity: 1,
+			UserID:   usrs[0].ID,
+		}
+
+		if _, err := api.Product.Create(ctx, np); err != nil {
+			t.Fatalf("Seeding error: %s", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+
+	orderBy := order.NewBy(product.OrderByRelevance, order.DESC)
+
+	var pf product.QueryFilter
+	pf.WithSearch("comic books")
+
+	prds, err := api.Product.Query(ctx, pf, orderBy, 1, 10)
+	if err != nil {
+		t.Fatalf("Should be able to search the products : %s", err)
+	}
+
+	if len(prds) != 2 {
+		t.Fatalf("Should find the products matching the search : got %d", len(prds))
+	}
+
+	if prds[0].Name != "Comic Books" {
+		t.Fatalf("Should rank the closest match first : got %q", prds[0].Name)
+	}
+
+	if !strings.Contains(prds[0].Snippet, "<mark>Comic</mark>") {
+		t.Fatalf("Should highlight the terms of the search : got %q", prds[0].Snippet)
+	}
+
+	// -------------------------------------------------------------------------
+
+	pf.WithSearch("Bord Games")
+
+	prds, err = api.Product.Query(ctx, pf, orderBy, 1, 10)
+	if err != nil {
+		t.Fatalf("Should be able to search the products : %s", err)
+	}
+
+	if len(prds) == 0 || prds[0].Name != "Board Games" {
+		t.Fatalf("Should find the product despite the typos : got %+v", prds)
+	}
+
+	// -------------------------------------------------------------------------
+
+	np := product.NewProduct{
+		Name:     `Puzzle <img src=x onerror="alert(1)">`,
+		Cost:     10,
+		Quantity: 1,
+		UserID:   usrs[0].ID,
+	}
+
+	if _, err := api.Product.Create(ctx, np); err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	pf.WithSearch("puzzle")
+
+	prds, err = api.Product.Query(ctx, pf, orderBy, 1, 10)
+	if err != nil {
+		t.Fatalf("Should be able to search the products : %s", err)
+	}
+
+	if len(prds) != 1 {
+		t.Fatalf("Should find the product matching the search : got %d", len(prds))
+	}
+
+	if strings.Contains(prds[0].Snippet, "<img") || !strings.Contains(prds[0].Snippet, "&lt;img") {
+		t.Fatalf("Should escape the name in the snippet : got %q", prds[0].Snippet)
+	}
+
+	if !strings.Contains(prds[0].Snippet, "<mark>Puzzle</mark>") {
+		t.Fatalf("Should highlight the terms of the search in the escaped name : got %q", prds[0].Snippet)
+	}
+}
+
+func batch(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	var filter user.QueryFilter
+	filter.WithName("Admin Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	nps := []product.NewProduct{
+		{Name: "Batch One", Cost: 10, Quantity: 1, UserID: usrs[0].ID},
+		{Name: "Batch Two", Cost: 10, Quantity: 1, UserID: uuid.New()},
+		{Name: "Batch Three", Cost: 20, Quantity: 2, UserID: usrs[0].ID},
+	}
+
+	prds, errs, err := api.Product.PrepareBatch(ctx, nps)
+	if err != nil {
+		t.Fatalf("Should be able to prepare the batch : %s", err)
+	}
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Fatalf("Should be able to prepare the products of a known user : %v, %v", errs[0], errs[2])
+	}
+
+	if !errors.Is(errs[1], user.ErrNotFound) {
+		t.Fatalf("Should NOT be able to prepare the product of an unknown user : %v", errs[1])
+	}
+
+	valid := []product.Product{prds[0], prds[2]}
+
+	if err := api.Product.CreateBatch(ctx, valid); err != nil {
+		t.Fatalf("Should be able to create the batch : %s", err)
+	}
+
+	for _, prd := range valid {
+		saved, err := api.Product.QueryByID(ctx, prd.ID)
+		if err != nil {
+			t.Fatalf("Should be able to retrieve the product of the batch : %s", err)
+		}
+
+		if saved.Name != prd.Name || saved.Cost != prd.Cost || saved.Version != 1 {
+			t.Fatalf("Should get back the product of the batch : got %+v, want %+v", saved, prd)
+		}
+	}
+}
+
+func stream(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	var filter user.QueryFilter
+	filter.WithName("Admin Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, 1, 1)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	prds, err := product.TestGenerateSeedProducts(3, api.Product, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	var pf product.QueryFilter
+	pf.WithUserIDs([]uuid.UUID{usrs[0].ID})
+
+	orderBy := order.NewBy(product.OrderByCost, order.ASC)
+
+	var got []product.Product
+	err = api.Product.QueryStream(ctx, pf, orderBy, func(prd product.Product) error {
+		got = append(got, prd)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Should be able to stream the products : %s", err)
+	}
+
+	if len(got) != len(prds) {
+		t.Fatalf("Should stream every product of the user : got %d want %d", len(got), len(prds))
+	}
+
+	for i := 1; i < len(got); i++ {
+		if got[i-1].Cost > got[i].Cost {
+			t.Fatalf("Should stream the products in order : %v before %v", got[i-1].Cost, got[i].Cost)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+
+	stop := errors.New("stop")
+
+	var n int
+	err = api.Product.QueryStream(ctx, pf, orderBy, func(prd product.Product) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Fatalf("Should stop streaming at the first error : %v after %d products", err, n)
+	}
+}
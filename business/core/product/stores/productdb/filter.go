@@ -2,15 +2,18 @@ package productdb
 
 import (
 	"bytes"
+	"context"
+	"database/sql/driver"
 	"fmt"
 	"strings"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	"github.com/google/uuid"
 )
 
-func (s *Store) applyFilter(filter product.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(ctx context.Context, filter product.QueryFilter, data map[string]interface{}, buf *bytes.Buffer, wc ...string) error {
 	if filter.ID != nil {
 		data["product_id"] = *filter.ID
 		wc = append(wc, "product_id = :product_id")
@@ -18,7 +21,7 @@ func (s *Store) applyFilter(filter product.QueryFilter, data map[string]interfac
 
 	if filter.Name != nil {
 		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		wc = append(wc, "name ILIKE :name")
 	}
 
 	if filter.Cost != nil {
@@ -31,8 +34,78 @@ func (s *Store) applyFilter(filter product.QueryFilter, data map[string]interfac
 		wc = append(wc, "quantity = :quantity")
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if filter.Search != nil {
+		data["search"] = *filter.Search
+		wc = append(wc, "(search_vector @@ websearch_to_tsquery('english', :search) OR :search <% name)")
+	}
+
+	if len(filter.IDs) > 0 {
+		data["product_ids"] = toDBArray(filter.IDs)
+		wc = append(wc, "product_id = ANY(:product_ids)")
+	}
+
+	if len(filter.UserIDs) > 0 {
+		data["user_ids"] = toDBArray(filter.UserIDs)
+		wc = append(wc, "user_id = ANY(:user_ids)")
+	}
+
+	wc = append(wc, filter.CostRange.Where("cost", "cost", data)...)
+	wc = append(wc, filter.QuantityRange.Where("quantity", "quantity", data)...)
+	wc = append(wc, filter.DateCreatedRange.Where("date_created", "date_created", data)...)
+
+	if !filter.IncludeInactive {
+		wc = append(wc, "active = TRUE")
+	}
+
+	clause, err := where(ctx, data, wc...)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	return nil
+}
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
+
+// toDBArray converts the ids into an array the database can compare a column
+// against.
+func toDBArray(ids []uuid.UUID) driver.Valuer {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	return dbarray.Array(values)
+}
+
+// searchColumns returns the columns added to the products read by a query
+// searching them. The name is HTML escaped before the marks are added, so the
+// snippet can be rendered as HTML without the name injecting any markup.
+func searchColumns(filter product.QueryFilter) string {
+	if filter.Search == nil {
+		return ""
 	}
+
+	const escaped = `replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+
+	return `, ts_headline('english', ` + escaped + `, websearch_to_tsquery('english', :search), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS snippet`
 }
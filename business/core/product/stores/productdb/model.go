@@ -10,12 +10,16 @@ import (
 // dbProduct represents an individual product.
 type dbProduct struct {
 	ID          uuid.UUID `db:"product_id"`   // Unique identifier.
+	TenantID    uuid.UUID `db:"tenant_id"`    // ID of the tenant owning the product.
 	UserID      uuid.UUID `db:"user_id"`      // ID of the user who created the product.
 	Name        string    `db:"name"`         // Display name of the product.
 	Cost        float64   `db:"cost"`         // Price for one item in cents.
 	Quantity    int       `db:"quantity"`     // Original number of items available.
+	Active      bool      `db:"active"`       // Whether the product is available for sale.
 	DateCreated time.Time `db:"date_created"` // When the product was added.
 	DateUpdated time.Time `db:"date_updated"` // When the product record was last modified.
+	Version     int       `db:"version"`      // Number of times the product has been written.
+	Snippet     string    `db:"snippet"`      // Name with the terms of a search highlighted.
 }
 
 // =============================================================================
@@ -23,12 +27,15 @@ type dbProduct struct {
 func toDBProduct(prd product.Product) dbProduct {
 	prdDB := dbProduct{
 		ID:          prd.ID,
+		TenantID:    prd.TenantID,
 		UserID:      prd.UserID,
 		Name:        prd.Name,
 		Cost:        prd.Cost,
 		Quantity:    prd.Quantity,
+		Active:      prd.Active,
 		DateCreated: prd.DateCreated.UTC(),
 		DateUpdated: prd.DateUpdated.UTC(),
+		Version:     prd.Version,
 	}
 
 	return prdDB
@@ -37,12 +44,16 @@ func toDBProduct(prd product.Product) dbProduct {
 func toCoreProduct(dbPrd dbProduct) product.Product {
 	prd := product.Product{
 		ID:          dbPrd.ID,
+		TenantID:    dbPrd.TenantID,
 		UserID:      dbPrd.UserID,
 		Name:        dbPrd.Name,
 		Cost:        dbPrd.Cost,
 		Quantity:    dbPrd.Quantity,
+		Active:      dbPrd.Active,
 		DateCreated: dbPrd.DateCreated.In(time.Local),
 		DateUpdated: dbPrd.DateUpdated.In(time.Local),
+		Version:     dbPrd.Version,
+		Snippet:     dbPrd.Snippet,
 	}
 
 	return prd
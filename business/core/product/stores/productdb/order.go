@@ -2,9 +2,11 @@ package productdb
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 )
 
 var orderByFields = map[string]string{
@@ -15,13 +17,63 @@ var orderByFields = map[string]string{
 	product.OrderBySold:     "sold",
 	product.OrderByRevenue:  "revenue",
 	product.OrderByUserID:   "user_id",
+
+	// The rank sums the full text rank and the trigram similarity, so names
+	// matching with a typo still come after the exact matches.
+	product.OrderByRelevance: "ts_rank(search_vector, websearch_to_tsquery('english', :search)) + word_similarity(:search, name)",
 }
 
 func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	fields := orderBy.Fields()
+
+	clauses := make([]string, len(fields))
+	for i, field := range fields {
+		by, exists := orderByFields[field.Field]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", field.Field)
+		}
+
+		clauses[i] = by + " " + field.Direction
+	}
+
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
+
+// keysetFields holds the fields the products can be read through a cursor
+// by, along with the type the value of a key is cast to.
+var keysetFields = map[string]string{
+	product.OrderByProdID:   "UUID",
+	product.OrderByName:     "TEXT",
+	product.OrderByCost:     "NUMERIC",
+	product.OrderByQuantity: "INT",
+	product.OrderByUserID:   "UUID",
+}
+
+// keysetClause returns the conditions selecting the products past the key of
+// the cursor and the order they must be read in. The id breaks the ties so
+// the key points at a single row.
+func keysetClause(cur page.Cursor, data map[string]interface{}) ([]string, string, error) {
+	by, exists := orderByFields[cur.OrderBy.Field]
+	typ, supported := keysetFields[cur.OrderBy.Field]
+	if !exists || !supported || len(cur.OrderBy.Then) > 0 {
+		return nil, "", fmt.Errorf("field %q: %w", cur.OrderBy.Field, page.ErrCursorOrder)
+	}
+
+	op, direction := cur.Seek()
+	orderBy := " ORDER BY " + by + " " + direction + ", product_id " + direction
+
+	if cur.Key == nil {
+		return nil, orderBy, nil
 	}
 
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+	if err := cur.Key.Check(typ); err != nil {
+		return nil, "", err
+	}
+
+	data["cursor_value"] = cur.Key.Value
+	data["cursor_id"] = cur.Key.ID
+
+	wc := fmt.Sprintf("(%s, product_id) %s (CAST(:cursor_value AS %s), CAST(:cursor_id AS UUID))", by, op, typ)
+
+	return []string{wc}, orderBy, nil
 }
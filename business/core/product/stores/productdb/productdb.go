@@ -6,16 +6,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+// streamFetchSize is the number of rows fetched from the cursor of a stream at
+// a time.
+const streamFetchSize = 500
+
 // Store manages the set of APIs for product database access.
 type Store struct {
 	log *logger.Logger
@@ -51,9 +57,9 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (product.Sto
 func (s *Store) Create(ctx context.Context, prd product.Product) error {
 	const q = `
 	INSERT INTO products
-		(product_id, user_id, name, cost, quantity, date_created, date_updated)
+		(product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version)
 	VALUES
-		(:product_id, :user_id, :name, :cost, :quantity, :date_created, :date_updated)`
+		(:product_id, :tenant_id, :user_id, :name, :cost, :quantity, :active, :date_created, :date_updated, :version)`
 
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
@@ -62,8 +68,31 @@ func (s *Store) Create(ctx context.Context, prd product.Product) error {
 	return nil
 }
 
+// CreateBatch adds the Products to the database with a single multi-row
+// insert.
+func (s *Store) CreateBatch(ctx context.Context, prds []product.Product) error {
+	const q = `
+	INSERT INTO products
+		(product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version)
+	VALUES
+		(:product_id, :tenant_id, :user_id, :name, :cost, :quantity, :active, :date_created, :date_updated, :version)`
+
+	dbPrds := make([]dbProduct, len(prds))
+	for i, prd := range prds {
+		dbPrds[i] = toDBProduct(prd)
+	}
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbPrds); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
 // Update modifies data about a Product. It will error if the specified ID is
-// invalid or does not reference an existing Product.
+// invalid or does not reference an existing Product. The product carries the
+// version it is moving to, so the update only happens when the stored version
+// is the one preceding it.
 func (s *Store) Update(ctx context.Context, prd product.Product) error {
 	const q = `
 	UPDATE
@@ -72,33 +101,92 @@ func (s *Store) Update(ctx context.Context, prd product.Product) error {
 		"name" = :name,
 		"cost" = :cost,
 		"quantity" = :quantity,
-		"date_updated" = :date_updated
+		"active" = :active,
+		"date_updated" = :date_updated,
+		"version" = :version
 	WHERE
-		product_id = :product_id`
+		product_id = :product_id AND tenant_id = :tenant_id AND version = :version - 1`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBProduct(prd)); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, toDBProduct(prd))
+	if err != nil {
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", product.ErrConcurrentModification)
 	}
 
 	return nil
 }
 
+// AdjustQuantity adds the delta to the quantity of a product in the database
+// and returns the new quantity and version. The update only succeeds when the
+// quantity doesn't drop below zero. Otherwise product.ErrInsufficientStock is
+// returned.
+func (s *Store) AdjustQuantity(ctx context.Context, prd product.Product, delta int) (int, int, error) {
+	data := struct {
+		ID          string    `db:"product_id"`
+		TenantID    string    `db:"tenant_id"`
+		Delta       int       `db:"delta"`
+		DateUpdated time.Time `db:"date_updated"`
+	}{
+		ID:          prd.ID.String(),
+		TenantID:    prd.TenantID.String(),
+		Delta:       delta,
+		DateUpdated: prd.DateUpdated.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		products
+	SET
+		"quantity" = quantity + :delta,
+		"date_updated" = :date_updated,
+		"version" = version + 1
+	WHERE
+		product_id = :product_id AND tenant_id = :tenant_id AND quantity + :delta >= 0
+	RETURNING
+		quantity, version`
+
+	var dest struct {
+		Quantity int `db:"quantity"`
+		Version  int `db:"version"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return 0, 0, fmt.Errorf("namedquerystruct: %w", product.ErrInsufficientStock)
+		}
+		return 0, 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dest.Quantity, dest.Version, nil
+}
+
 // Delete removes the product identified by a given ID.
 func (s *Store) Delete(ctx context.Context, prd product.Product) error {
 	data := struct {
-		ID string `db:"product_id"`
+		ID       string `db:"product_id"`
+		TenantID string `db:"tenant_id"`
+		Version  int    `db:"version"`
 	}{
-		ID: prd.ID.String(),
+		ID:       prd.ID.String(),
+		TenantID: prd.TenantID.String(),
+		Version:  prd.Version,
 	}
 
 	const q = `
 	DELETE FROM
 		products
 	WHERE
-		product_id = :product_id`
+		product_id = :product_id AND tenant_id = :tenant_id AND version = :version`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, data)
+	if err != nil {
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", product.ErrConcurrentModification)
 	}
 
 	return nil
@@ -113,12 +201,14 @@ func (s *Store) Query(ctx context.Context, filter product.QueryFilter, orderBy o
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version%s
 	FROM
 		products`
 
-	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	buf := bytes.NewBufferString(fmt.Sprintf(q, searchColumns(filter)))
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, err
+	}
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -136,6 +226,74 @@ func (s *Store) Query(ctx context.Context, filter product.QueryFilter, orderBy o
 	return toCoreProductSlice(dbPrds), nil
 }
 
+// QueryStream reads the Products from the database through a server-side
+// cursor and hands them to fn one at a time.
+func (s *Store) QueryStream(ctx context.Context, filter product.QueryFilter, orderBy order.By, fn func(product.Product) error) error {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+	    product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version%s
+	FROM
+		products`
+
+	buf := bytes.NewBufferString(fmt.Sprintf(q, searchColumns(filter)))
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return err
+	}
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(orderByClause)
+
+	f := func(dbPrd dbProduct) error {
+		return fn(toCoreProduct(dbPrd))
+	}
+
+	if err := db.NamedQueryCursor(ctx, s.log, s.db, buf.String(), data, streamFetchSize, f); err != nil {
+		return fmt.Errorf("namedquerycursor: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByCursor gets the Products from the database past the key of the
+// cursor, in the order the cursor reads them.
+func (s *Store) QueryByCursor(ctx context.Context, filter product.QueryFilter, cur page.Cursor, rowsPerPage int) ([]product.Product, error) {
+	data := map[string]interface{}{
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+	    product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version%s
+	FROM
+		products`
+
+	wc, orderByClause, err := keysetClause(cur, data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(fmt.Sprintf(q, searchColumns(filter)))
+	if err := s.applyFilter(ctx, filter, data, buf, wc...); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbPrds []dbProduct
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreProductSlice(dbPrds), nil
+}
+
 // Count returns the total number of users in the DB.
 func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, error) {
 	data := map[string]interface{}{}
@@ -147,7 +305,9 @@ func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, err
 		products`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, err
+	}
 
 	var count struct {
 		Count   int `db:"count"`
@@ -163,22 +323,26 @@ func (s *Store) Count(ctx context.Context, filter product.QueryFilter) (int, err
 
 // QueryByID finds the product identified by a given ID.
 func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (product.Product, error) {
-	data := struct {
-		ID string `db:"product_id"`
-	}{
-		ID: productID.String(),
+	data := map[string]interface{}{
+		"product_id": productID.String(),
 	}
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version
 	FROM
-		products
-	WHERE
-		product_id = :product_id`
+		products`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "product_id = :product_id")
+	if err != nil {
+		return product.Product{}, err
+	}
+
+	buf.WriteString(clause)
 
 	var dbPrd dbProduct
-	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbPrd); err != nil {
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbPrd); err != nil {
 		if errors.Is(err, db.ErrDBNotFound) {
 			return product.Product{}, fmt.Errorf("namedquerystruct: %w", product.ErrNotFound)
 		}
@@ -190,22 +354,26 @@ func (s *Store) QueryByID(ctx context.Context, productID uuid.UUID) (product.Pro
 
 // QueryByUserID finds the product identified by a given User ID.
 func (s *Store) QueryByUserID(ctx context.Context, userID uuid.UUID) ([]product.Product, error) {
-	data := struct {
-		ID string `db:"user_id"`
-	}{
-		ID: userID.String(),
+	data := map[string]interface{}{
+		"user_id": userID.String(),
 	}
 
 	const q = `
 	SELECT
-	    product_id, user_id, name, cost, quantity, date_created, date_updated
+	    product_id, tenant_id, user_id, name, cost, quantity, active, date_created, date_updated, version
 	FROM
-		products
-	WHERE
-		user_id = :user_id`
+		products`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
 
 	var dbPrds []dbProduct
-	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbPrds); err != nil {
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbPrds); err != nil {
 		return nil, fmt.Errorf("namedquerystruct: %w", err)
 	}
 
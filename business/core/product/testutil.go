@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/google/uuid"
 )
 
@@ -34,7 +35,7 @@ func TestGenerateSeedProducts(n int, api *Core, userID uuid.UUID) ([]Product, er
 
 	prds := make([]Product, len(newPrds))
 	for i, np := range newPrds {
-		prd, err := api.Create(context.Background(), np)
+		prd, err := api.Create(tenant.Unscoped(context.Background()), np)
 		if err != nil {
 			return nil, fmt.Errorf("seeding product: idx: %d : %w", i, err)
 		}
@@ -0,0 +1,36 @@
+package role
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/google/uuid"
+)
+
+// Role represents a role of a tenant along with the permissions it grants.
+// Permissions have the form resource:action, resource:* grants every action on
+// the resource and * grants everything but the platform permissions, which
+// only the roles of the default tenant may grant.
+type Role struct {
+	TenantID    uuid.UUID
+	Name        user.Role
+	Description string
+	Permissions []string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewRole is what we require from clients when adding a Role.
+type NewRole struct {
+	Name        user.Role
+	Description string
+	Permissions []string
+}
+
+// UpdateRole defines what information may be provided to modify an existing
+// Role. All fields are optional so clients can send just the fields they want
+// changed.
+type UpdateRole struct {
+	Description *string
+	Permissions []string
+}
@@ -0,0 +1,253 @@
+// Package role provides the core business API for the roles that can be
+// given to users and the permissions they grant.
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for role operations.
+var (
+	ErrNotFound          = errors.New("role not found")
+	ErrUniqueName        = errors.New("role name is not unique")
+	ErrBuiltIn           = errors.New("built in roles can't be deleted")
+	ErrUnknownRole       = errors.New("role doesn't exist")
+	ErrInvalidPermission = errors.New("permission not valid")
+)
+
+// permission is the format of a permission.
+var permission = regexp.MustCompile(`^(\*|[a-z][a-z0-9_]*:(\*|[a-z][a-z0-9_]*))$`)
+
+// platform is the resource of the permissions that manage the system as a
+// whole, such as the tenants. They are not granted by *, only the roles of the
+// default tenant may grant them.
+const platform = "platform"
+
+// builtIn are the roles every tenant starts with.
+var builtIn = []NewRole{
+	{
+		Name:        user.RoleAdmin,
+		Description: "Administrators of the system",
+		Permissions: []string{"*"},
+	},
+	{
+		Name:        user.RoleUser,
+		Description: "Users of the system",
+		Permissions: []string{"product:read", "product:write", "order:read", "order:write", "user:read", "user:write"},
+	},
+}
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, rol Role) error
+	Update(ctx context.Context, rol Role) error
+	Delete(ctx context.Context, rol Role) error
+	Query(ctx context.Context) ([]Role, error)
+	QueryByName(ctx context.Context, name user.Role) (Role, error)
+	QueryByNames(ctx context.Context, names []user.Role) ([]Role, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for role access.
+type Core struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewCore constructs a core for role api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:    c.log,
+		storer: storer,
+	}
+
+	return c, nil
+}
+
+// Create adds a new role to the tenant of the context. Roles created outside
+// of a tenant belong to the default tenant.
+func (c *Core) Create(ctx context.Context, nr NewRole) (Role, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	if !scoped {
+		tenantID = tenant.Default
+	}
+
+	if err := checkPermissions(tenantID, nr.Permissions); err != nil {
+		return Role{}, err
+	}
+
+	now := time.Now()
+
+	rol := Role{
+		TenantID:    tenantID,
+		Name:        nr.Name,
+		Description: nr.Description,
+		Permissions: compact(nr.Permissions),
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, rol); err != nil {
+		return Role{}, fmt.Errorf("create: %w", err)
+	}
+
+	return rol, nil
+}
+
+// CreateBuiltIn adds the built in roles to the tenant of the context.
+func (c *Core) CreateBuiltIn(ctx context.Context) error {
+	for _, nr := range builtIn {
+		if _, err := c.Create(ctx, nr); err != nil {
+			return fmt.Errorf("create: name[%s]: %w", nr.Name.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Update modifies information about a role.
+func (c *Core) Update(ctx context.Context, rol Role, ur UpdateRole) (Role, error) {
+	if ur.Description != nil {
+		rol.Description = *ur.Description
+	}
+
+	if ur.Permissions != nil {
+		if err := checkPermissions(rol.TenantID, ur.Permissions); err != nil {
+			return Role{}, err
+		}
+		rol.Permissions = compact(ur.Permissions)
+	}
+
+	rol.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, rol); err != nil {
+		return Role{}, fmt.Errorf("update: %w", err)
+	}
+
+	return rol, nil
+}
+
+// Delete removes the specified role. Built in roles can't be deleted. Users
+// still holding a deleted role no longer get any permission from it.
+func (c *Core) Delete(ctx context.Context, rol Role) error {
+	if rol.Name.BuiltIn() {
+		return ErrBuiltIn
+	}
+
+	if err := c.storer.Delete(ctx, rol); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves every role of the tenant.
+func (c *Core) Query(ctx context.Context) ([]Role, error) {
+	roles, err := c.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return roles, nil
+}
+
+// QueryByName finds the role by the specified name.
+func (c *Core) QueryByName(ctx context.Context, name user.Role) (Role, error) {
+	rol, err := c.storer.QueryByName(ctx, name)
+	if err != nil {
+		return Role{}, fmt.Errorf("query: name[%s]: %w", name.Name(), err)
+	}
+
+	return rol, nil
+}
+
+// Check validates that every role exists.
+func (c *Core) Check(ctx context.Context, names []user.Role) error {
+	roles, err := c.storer.QueryByNames(ctx, names)
+	if err != nil {
+		return fmt.Errorf("querybynames: %w", err)
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(roles, func(rol Role) bool { return rol.Name == name }) {
+			return fmt.Errorf("%w: %s", ErrUnknownRole, name.Name())
+		}
+	}
+
+	return nil
+}
+
+// Permissions returns the permissions granted by the roles. Roles that don't
+// exist grant nothing.
+func (c *Core) Permissions(ctx context.Context, names []user.Role) ([]string, error) {
+	roles, err := c.storer.QueryByNames(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("querybynames: %w", err)
+	}
+
+	var perms []string
+	for _, rol := range roles {
+		perms = append(perms, rol.Permissions...)
+	}
+
+	return compact(perms), nil
+}
+
+// =============================================================================
+
+func checkPermissions(tenantID uuid.UUID, perms []string) error {
+	for _, perm := range perms {
+		if !permission.MatchString(perm) {
+			return fmt.Errorf("%w: %q", ErrInvalidPermission, perm)
+		}
+
+		if tenantID != tenant.Default && strings.HasPrefix(perm, platform+":") {
+			return fmt.Errorf("%w: %q is only granted by the default tenant", ErrInvalidPermission, perm)
+		}
+	}
+
+	return nil
+}
+
+// compact returns the sorted permissions without duplicates.
+func compact(perms []string) []string {
+	perms = slices.Clone(perms)
+	slices.Sort(perms)
+
+	return slices.Compact(perms)
+}
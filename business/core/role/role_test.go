@@ -0,0 +1,235 @@
+package role_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Role(t *testing.T) {
+	t.Run("crud", crud)
+	t.Run("permissions", permissions)
+	t.Run("tenant", tenants)
+}
+
+// =============================================================================
+
+func crud(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	nr := role.NewRole{
+		Name:        user.MustParseRole("EDITOR"),
+		Description: "Edits the catalog",
+		Permissions: []string{"product:write", "product:read", "product:write"},
+	}
+
+	rol, err := api.Role.Create(ctx, nr)
+	if err != nil {
+		t.Fatalf("Should be able to create a role : %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"product:read", "product:write"}, rol.Permissions); diff != "" {
+		t.Fatalf("Should get back the sorted permissions without duplicates. Diff:\n%s", diff)
+	}
+
+	if _, err := api.Role.Create(ctx, nr); !errors.Is(err, role.ErrUniqueName) {
+		t.Fatalf("Should NOT be able to create a role twice : %v", err)
+	}
+
+	bad := role.NewRole{
+		Name:        user.MustParseRole("BROKEN"),
+		Permissions: []string{"product write"},
+	}
+
+	if _, err := api.Role.Create(ctx, bad); !errors.Is(err, role.ErrInvalidPermission) {
+		t.Fatalf("Should NOT be able to create a role with a malformed permission : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	rol, err = api.Role.Update(ctx, rol, role.UpdateRole{Permissions: []string{"product:*"}})
+	if err != nil {
+		t.Fatalf("Should be able to update the role : %s", err)
+	}
+
+	saved, err := api.Role.QueryByName(ctx, rol.Name)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the role : %s", err)
+	}
+
+	if diff := cmp.Diff([]string{"product:*"}, saved.Permissions); diff != "" {
+		t.Fatalf("Should get back the updated permissions. Diff:\n%s", diff)
+	}
+
+	if saved.Description != "Edits the catalog" {
+		t.Fatalf("Should keep the description : %q", saved.Description)
+	}
+
+	// -------------------------------------------------------------------------
+
+	admin, err := api.Role.QueryByName(ctx, user.RoleAdmin)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the admin role : %s", err)
+	}
+
+	if err := api.Role.Delete(ctx, admin); !errors.Is(err, role.ErrBuiltIn) {
+		t.Fatalf("Should NOT be able to delete a built in role : %v", err)
+	}
+
+	if err := api.Role.Delete(ctx, rol); err != nil {
+		t.Fatalf("Should be able to delete the role : %s", err)
+	}
+
+	if _, err := api.Role.QueryByName(ctx, rol.Name); !errors.Is(err, role.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve a deleted role : %v", err)
+	}
+}
+
+func permissions(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	nr := role.NewRole{
+		Name:        user.MustParseRole("AUDITOR"),
+		Permissions: []string{"audit:read", "user:read"},
+	}
+
+	if _, err := api.Role.Create(ctx, nr); err != nil {
+		t.Fatalf("Should be able to create a role : %s", err)
+	}
+
+	perms, err := api.Role.Permissions(ctx, []user.Role{user.RoleUser, nr.Name, user.MustParseRole("MISSING")})
+	if err != nil {
+		t.Fatalf("Should be able to get the permissions : %s", err)
+	}
+
+	exp := []string{"audit:read", "order:read", "product:read", "product:write", "user:read"}
+	if diff := cmp.Diff(exp, perms); diff != "" {
+		t.Fatalf("Should get the permissions of every existing role. Diff:\n%s", diff)
+	}
+
+	if err := api.Role.Check(ctx, []user.Role{user.RoleUser, nr.Name}); err != nil {
+		t.Fatalf("Should be able to check existing roles : %s", err)
+	}
+
+	if err := api.Role.Check(ctx, []user.Role{user.RoleUser, user.MustParseRole("MISSING")}); !errors.Is(err, role.ErrUnknownRole) {
+		t.Fatalf("Should NOT accept a role that doesn't exist : %v", err)
+	}
+}
+
+func tenants(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	tnt, err := api.Tenant.Create(ctx, tenant.NewTenant{Name: "Wholesale"})
+	if err != nil {
+		t.Fatalf("Should be able to create a tenant : %s", err)
+	}
+
+	ctxDefault := tenant.Set(ctx, tenant.Default)
+	ctxOther := tenant.Set(ctx, tnt.ID)
+
+	if err := api.Role.CreateBuiltIn(ctxOther); err != nil {
+		t.Fatalf("Should be able to create the built in roles of the tenant : %s", err)
+	}
+
+	rol, err := api.Role.QueryByName(ctxOther, user.RoleUser)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the role of the tenant : %s", err)
+	}
+
+	if rol.TenantID != tnt.ID {
+		t.Fatalf("Should get the role of the tenant : got %s", rol.TenantID)
+	}
+
+	if _, err := api.Role.Update(ctxOther, rol, role.UpdateRole{Permissions: []string{"*"}}); err != nil {
+		t.Fatalf("Should be able to update the role of the tenant : %s", err)
+	}
+
+	perms, err := api.Role.Permissions(ctxDefault, []user.Role{user.RoleUser})
+	if err != nil {
+		t.Fatalf("Should be able to get the permissions : %s", err)
+	}
+
+	if slices.Contains(perms, "*") {
+		t.Fatalf("Should NOT change the role of another tenant : %v", perms)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if _, err := api.Role.Update(ctxOther, rol, role.UpdateRole{Permissions: []string{"platform:*"}}); !errors.Is(err, role.ErrInvalidPermission) {
+		t.Fatalf("Should NOT be able to grant a platform permission outside of the default tenant : %v", err)
+	}
+
+	nr := role.NewRole{
+		Name:        user.MustParseRole("OPERATOR"),
+		Permissions: []string{"platform:tenant_read"},
+	}
+
+	if _, err := api.Role.Create(ctxOther, nr); !errors.Is(err, role.ErrInvalidPermission) {
+		t.Fatalf("Should NOT be able to create a role with a platform permission outside of the default tenant : %v", err)
+	}
+
+	if _, err := api.Role.Create(ctxDefault, nr); err != nil {
+		t.Fatalf("Should be able to create a role with a platform permission in the default tenant : %s", err)
+	}
+}
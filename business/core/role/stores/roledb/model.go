@@ -0,0 +1,65 @@
+package roledb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	"github.com/google/uuid"
+)
+
+type dbRole struct {
+	TenantID    uuid.UUID      `db:"tenant_id"`
+	Name        string         `db:"name"`
+	Description string         `db:"description"`
+	Permissions dbarray.String `db:"permissions"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+func toDBRole(rol role.Role) dbRole {
+	perms := make([]string, len(rol.Permissions))
+	copy(perms, rol.Permissions)
+
+	return dbRole{
+		TenantID:    rol.TenantID,
+		Name:        rol.Name.Name(),
+		Description: rol.Description,
+		Permissions: perms,
+		DateCreated: rol.DateCreated.UTC(),
+		DateUpdated: rol.DateUpdated.UTC(),
+	}
+}
+
+func toCoreRole(dbRol dbRole) (role.Role, error) {
+	name, err := user.ParseRole(dbRol.Name)
+	if err != nil {
+		return role.Role{}, fmt.Errorf("parse role: %w", err)
+	}
+
+	rol := role.Role{
+		TenantID:    dbRol.TenantID,
+		Name:        name,
+		Description: dbRol.Description,
+		Permissions: dbRol.Permissions,
+		DateCreated: dbRol.DateCreated.In(time.Local),
+		DateUpdated: dbRol.DateUpdated.In(time.Local),
+	}
+
+	return rol, nil
+}
+
+func toCoreRoleSlice(dbRoles []dbRole) ([]role.Role, error) {
+	roles := make([]role.Role, len(dbRoles))
+	for i, dbRol := range dbRoles {
+		var err error
+		roles[i], err = toCoreRole(dbRol)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return roles, nil
+}
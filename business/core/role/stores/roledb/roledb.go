@@ -0,0 +1,223 @@
+// Package roledb contains role related CRUD functionality.
+package roledb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for role database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (role.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create inserts a new role into the database.
+func (s *Store) Create(ctx context.Context, rol role.Role) error {
+	const q = `
+	INSERT INTO roles
+		(tenant_id, name, description, permissions, date_created, date_updated)
+	VALUES
+		(:tenant_id, :name, :description, :permissions, :date_created, :date_updated)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBRole(rol)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", role.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a role document in the database.
+func (s *Store) Update(ctx context.Context, rol role.Role) error {
+	const q = `
+	UPDATE
+		roles
+	SET
+		"description" = :description,
+		"permissions" = :permissions,
+		"date_updated" = :date_updated
+	WHERE
+		tenant_id = :tenant_id AND name = :name`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBRole(rol)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a role from the database.
+func (s *Store) Delete(ctx context.Context, rol role.Role) error {
+	data := struct {
+		TenantID string `db:"tenant_id"`
+		Name     string `db:"name"`
+	}{
+		TenantID: rol.TenantID.String(),
+		Name:     rol.Name.Name(),
+	}
+
+	const q = `
+	DELETE FROM
+		roles
+	WHERE
+		tenant_id = :tenant_id AND name = :name`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves every role from the database.
+func (s *Store) Query(ctx context.Context) ([]role.Role, error) {
+	data := map[string]interface{}{}
+
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, date_created, date_updated
+	FROM
+		roles`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
+	buf.WriteString(" ORDER BY name")
+
+	var dbRoles []dbRole
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreRoleSlice(dbRoles)
+}
+
+// QueryByName gets the specified role from the database.
+func (s *Store) QueryByName(ctx context.Context, name user.Role) (role.Role, error) {
+	data := map[string]interface{}{
+		"name": name.Name(),
+	}
+
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, date_created, date_updated
+	FROM
+		roles`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "name = :name")
+	if err != nil {
+		return role.Role{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbRol dbRole
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbRol); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return role.Role{}, fmt.Errorf("namedquerystruct: %w", role.ErrNotFound)
+		}
+		return role.Role{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRole(dbRol)
+}
+
+// QueryByNames gets the specified roles from the database. Names without a
+// role are left out.
+func (s *Store) QueryByNames(ctx context.Context, names []user.Role) ([]role.Role, error) {
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = name.Name()
+	}
+
+	data := map[string]interface{}{
+		"name": dbarray.Array(values),
+	}
+
+	const q = `
+	SELECT
+		tenant_id, name, description, permissions, date_created, date_updated
+	FROM
+		roles`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "name = ANY(:name)")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbRoles []dbRole
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbRoles); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreRoleSlice(dbRoles)
+}
+
+// =============================================================================
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
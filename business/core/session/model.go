@@ -0,0 +1,29 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents a stored refresh token. Only the hash of the token
+// is kept, the raw value is handed to the client once when it's issued.
+// Tokens that are rotated from the same login share a FamilyID and keep the
+// authentication methods (AMR) that were used for the login. A token belongs
+// to the tenant of its user.
+type RefreshToken struct {
+	ID          uuid.UUID
+	FamilyID    uuid.UUID
+	TenantID    uuid.UUID
+	UserID      uuid.UUID
+	AMR         []string
+	Hash        string
+	DateCreated time.Time
+	DateExpires time.Time
+	DateRevoked time.Time
+}
+
+// Revoked reports whether the token has been used or revoked.
+func (rt RefreshToken) Revoked() bool {
+	return !rt.DateRevoked.IsZero()
+}
@@ -0,0 +1,218 @@
+// Package session provides the core business API for managing refresh tokens
+// and the revocation of access tokens.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for session operations.
+var (
+	ErrNotFound     = errors.New("refresh token not found")
+	ErrInvalidToken = errors.New("refresh token not valid")
+	ErrTokenExpired = errors.New("refresh token expired")
+	ErrTokenReused  = errors.New("refresh token reused")
+)
+
+// RefreshTTL is the amount of time a refresh token can be used for.
+const RefreshTTL = 7 * 24 * time.Hour
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, rt RefreshToken) error
+	Revoke(ctx context.Context, rt RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error
+	RevokeByUserID(ctx context.Context, userID uuid.UUID, now time.Time) error
+	QueryByHash(ctx context.Context, hash string) (RefreshToken, error)
+	RevokeAccess(ctx context.Context, jti string, expires time.Time) error
+	IsAccessRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for session access. The revoker is the storer
+// the core was constructed with. It's kept when the core is executed under a
+// transaction, so the family of a reused token stays revoked when the
+// transaction is rolled back.
+type Core struct {
+	log     *logger.Logger
+	storer  Storer
+	revoker Storer
+}
+
+// NewCore constructs a core for session api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:     log,
+		storer:  storer,
+		revoker: storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:     c.log,
+		storer:  storer,
+		revoker: c.revoker,
+	}
+
+	return c, nil
+}
+
+// Create issues a refresh token for a new login of the specified user of the
+// tenant that authenticated with the amr methods. The raw token is returned
+// and is not stored anywhere.
+func (c *Core) Create(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID, amr []string) (string, RefreshToken, error) {
+	return c.issue(ctx, userID, tenantID, uuid.New(), amr)
+}
+
+// Rotate exchanges a refresh token for a new one from the same family. A
+// token can only be exchanged once. If a token that was already exchanged is
+// presented again, the whole family is revoked since either the client or an
+// attacker is holding a stolen token. The family is revoked outside of any
+// transaction the core is executed under.
+func (c *Core) Rotate(ctx context.Context, raw string) (string, RefreshToken, error) {
+	rt, err := c.storer.QueryByHash(ctx, hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", RefreshToken{}, ErrInvalidToken
+		}
+		return "", RefreshToken{}, fmt.Errorf("querybyhash: %w", err)
+	}
+
+	now := time.Now()
+
+	if rt.Revoked() {
+		return "", RefreshToken{}, c.revokeReused(ctx, rt, now)
+	}
+
+	if now.After(rt.DateExpires) {
+		return "", RefreshToken{}, ErrTokenExpired
+	}
+
+	rt.DateRevoked = now
+
+	if err := c.storer.Revoke(ctx, rt); err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			return "", RefreshToken{}, c.revokeReused(ctx, rt, now)
+		}
+		return "", RefreshToken{}, fmt.Errorf("revoke: %w", err)
+	}
+
+	return c.issue(ctx, rt.UserID, rt.TenantID, rt.FamilyID, rt.AMR)
+}
+
+// Revoke revokes the refresh token and all the tokens rotated from the same
+// login.
+func (c *Core) Revoke(ctx context.Context, raw string) error {
+	rt, err := c.storer.QueryByHash(ctx, hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidToken
+		}
+		return fmt.Errorf("querybyhash: %w", err)
+	}
+
+	if err := c.storer.RevokeFamily(ctx, rt.FamilyID, time.Now()); err != nil {
+		return fmt.Errorf("revokefamily: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every refresh token of the user, ending all of the
+// sessions of the user.
+func (c *Core) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := c.storer.RevokeByUserID(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("revokebyuserid: userID[%s]: %w", userID, err)
+	}
+
+	return nil
+}
+
+// RevokeAccess revokes the access token identified by the jti claim. The
+// revocation only needs to be kept until the token expires.
+func (c *Core) RevokeAccess(ctx context.Context, jti string, expires time.Time) error {
+	if err := c.storer.RevokeAccess(ctx, jti, expires); err != nil {
+		return fmt.Errorf("revokeaccess: jti[%s]: %w", jti, err)
+	}
+
+	return nil
+}
+
+// IsAccessRevoked reports whether the access token identified by the jti
+// claim has been revoked.
+func (c *Core) IsAccessRevoked(ctx context.Context, jti string) (bool, error) {
+	revoked, err := c.storer.IsAccessRevoked(ctx, jti)
+	if err != nil {
+		return false, fmt.Errorf("isaccessrevoked: jti[%s]: %w", jti, err)
+	}
+
+	return revoked, nil
+}
+
+// =============================================================================
+
+func (c *Core) issue(ctx context.Context, userID uuid.UUID, tenantID uuid.UUID, familyID uuid.UUID, amr []string) (string, RefreshToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", RefreshToken{}, fmt.Errorf("generating token: %w", err)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+
+	rt := RefreshToken{
+		ID:          uuid.New(),
+		FamilyID:    familyID,
+		TenantID:    tenantID,
+		UserID:      userID,
+		AMR:         amr,
+		Hash:        hash(raw),
+		DateCreated: now,
+		DateExpires: now.Add(RefreshTTL),
+	}
+
+	if err := c.storer.Create(ctx, rt); err != nil {
+		return "", RefreshToken{}, fmt.Errorf("create: %w", err)
+	}
+
+	return raw, rt, nil
+}
+
+func (c *Core) revokeReused(ctx context.Context, rt RefreshToken, now time.Time) error {
+	c.log.Info(ctx, "session", "status", "refresh token reused", "user_id", rt.UserID, "family_id", rt.FamilyID)
+
+	if err := c.revoker.RevokeFamily(ctx, rt.FamilyID, now); err != nil {
+		return fmt.Errorf("revokefamily: %w", err)
+	}
+
+	return ErrTokenReused
+}
+
+func hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,191 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Session(t *testing.T) {
+	t.Run("rotate", rotate)
+	t.Run("reuseTran", reuseTran)
+	t.Run("revoke", revoke)
+}
+
+// =============================================================================
+
+func rotate(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	raw1, rt1, err := api.Session.Create(ctx, userID, tenant.Default, []string{"pwd"})
+	if err != nil {
+		t.Fatalf("Should be able to create a refresh token : %s", err)
+	}
+
+	raw2, rt2, err := api.Session.Rotate(ctx, raw1)
+	if err != nil {
+		t.Fatalf("Should be able to rotate a refresh token : %s", err)
+	}
+
+	if raw2 == raw1 || rt2.FamilyID != rt1.FamilyID || rt2.UserID != userID || len(rt2.AMR) != 1 {
+		t.Fatalf("Should get a new token from the same family and login : %+v %+v", rt1, rt2)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if _, _, err := api.Session.Rotate(ctx, raw1); !errors.Is(err, session.ErrTokenReused) {
+		t.Fatalf("Should detect the reuse of a rotated token : %v", err)
+	}
+
+	if _, _, err := api.Session.Rotate(ctx, raw2); !errors.Is(err, session.ErrTokenReused) {
+		t.Fatalf("Should have revoked the family after a reuse : %v", err)
+	}
+
+	if _, _, err := api.Session.Rotate(ctx, "unknown"); !errors.Is(err, session.ErrInvalidToken) {
+		t.Fatalf("Should NOT be able to rotate an unknown token : %v", err)
+	}
+}
+
+func reuseTran(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	raw1, _, err := api.Session.Create(ctx, userID, tenant.Default, []string{"pwd"})
+	if err != nil {
+		t.Fatalf("Should be able to create a refresh token : %s", err)
+	}
+
+	raw2, _, err := api.Session.Rotate(ctx, raw1)
+	if err != nil {
+		t.Fatalf("Should be able to rotate a refresh token : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	f := func(tx transaction.Transaction) error {
+		sesCore, err := api.Session.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = sesCore.Rotate(ctx, raw1)
+		return err
+	}
+
+	if err := transaction.ExecuteUnderTransaction(ctx, test.Log, db.NewBeginner(test.DB), f); !errors.Is(err, session.ErrTokenReused) {
+		t.Fatalf("Should detect the reuse of a rotated token under a transaction : %v", err)
+	}
+
+	if _, _, err := api.Session.Rotate(ctx, raw2); !errors.Is(err, session.ErrTokenReused) {
+		t.Fatalf("Should keep the family revoked after the transaction is rolled back : %v", err)
+	}
+}
+
+func revoke(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	userID := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+
+	raw, _, err := api.Session.Create(ctx, userID, tenant.Default, []string{"pwd"})
+	if err != nil {
+		t.Fatalf("Should be able to create a refresh token : %s", err)
+	}
+
+	if err := api.Session.Revoke(ctx, raw); err != nil {
+		t.Fatalf("Should be able to revoke a refresh token : %s", err)
+	}
+
+	if _, _, err := api.Session.Rotate(ctx, raw); err == nil {
+		t.Fatal("Should NOT be able to rotate a revoked token")
+	}
+
+	// -------------------------------------------------------------------------
+
+	jti := uuid.NewString()
+
+	revoked, err := api.Session.IsAccessRevoked(ctx, jti)
+	if err != nil {
+		t.Fatalf("Should be able to check the revocation : %s", err)
+	}
+
+	if revoked {
+		t.Fatal("Should NOT see a revocation that was never made")
+	}
+
+	if err := api.Session.RevokeAccess(ctx, jti, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Should be able to revoke an access token : %s", err)
+	}
+
+	revoked, err = api.Session.IsAccessRevoked(ctx, jti)
+	if err != nil {
+		t.Fatalf("Should be able to check the revocation : %s", err)
+	}
+
+	if !revoked {
+		t.Fatal("Should see the access token as revoked")
+	}
+}
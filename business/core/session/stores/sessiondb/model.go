@@ -0,0 +1,61 @@
+package sessiondb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
+	"github.com/google/uuid"
+)
+
+// dbRefreshToken represents a stored refresh token.
+type dbRefreshToken struct {
+	ID          uuid.UUID      `db:"token_id"`     // Unique identifier.
+	FamilyID    uuid.UUID      `db:"family_id"`    // Identifier shared by tokens of the same login.
+	TenantID    uuid.UUID      `db:"tenant_id"`    // Tenant the user belongs to.
+	UserID      uuid.UUID      `db:"user_id"`      // ID of the user the token was issued to.
+	AMR         dbarray.String `db:"amr"`          // Authentication methods used for the login.
+	Hash        string         `db:"token_hash"`   // SHA-256 hash of the raw token.
+	DateCreated time.Time      `db:"date_created"` // When the token was issued.
+	DateExpires time.Time      `db:"date_expires"` // When the token can no longer be used.
+	DateRevoked sql.NullTime   `db:"date_revoked"` // When the token was used or revoked.
+}
+
+// =============================================================================
+
+func toDBRefreshToken(rt session.RefreshToken) dbRefreshToken {
+	return dbRefreshToken{
+		ID:          rt.ID,
+		FamilyID:    rt.FamilyID,
+		TenantID:    rt.TenantID,
+		UserID:      rt.UserID,
+		AMR:         rt.AMR,
+		Hash:        rt.Hash,
+		DateCreated: rt.DateCreated.UTC(),
+		DateExpires: rt.DateExpires.UTC(),
+		DateRevoked: sql.NullTime{
+			Time:  rt.DateRevoked.UTC(),
+			Valid: !rt.DateRevoked.IsZero(),
+		},
+	}
+}
+
+func toCoreRefreshToken(dbRT dbRefreshToken) session.RefreshToken {
+	rt := session.RefreshToken{
+		ID:          dbRT.ID,
+		FamilyID:    dbRT.FamilyID,
+		TenantID:    dbRT.TenantID,
+		UserID:      dbRT.UserID,
+		AMR:         dbRT.AMR,
+		Hash:        dbRT.Hash,
+		DateCreated: dbRT.DateCreated.In(time.Local),
+		DateExpires: dbRT.DateExpires.In(time.Local),
+	}
+
+	if dbRT.DateRevoked.Valid {
+		rt.DateRevoked = dbRT.DateRevoked.Time.In(time.Local)
+	}
+
+	return rt
+}
@@ -0,0 +1,265 @@
+// Package sessiondb contains refresh and revoked token related CRUD
+// functionality.
+package sessiondb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for session database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (session.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create adds a refresh token to the database.
+func (s *Store) Create(ctx context.Context, rt session.RefreshToken) error {
+	const q = `
+	INSERT INTO refresh_tokens
+		(token_id, family_id, tenant_id, user_id, amr, token_hash, date_created, date_expires, date_revoked)
+	VALUES
+		(:token_id, :family_id, :tenant_id, :user_id, :amr, :token_hash, :date_created, :date_expires, :date_revoked)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBRefreshToken(rt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke marks a refresh token as used. The update only succeeds for a token
+// that is not revoked yet, so two concurrent rotations of the same token can't
+// both succeed. The loser gets session.ErrTokenReused.
+func (s *Store) Revoke(ctx context.Context, rt session.RefreshToken) error {
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		"date_revoked" = :date_revoked
+	WHERE
+		token_id = :token_id AND tenant_id = :tenant_id AND date_revoked IS NULL
+	RETURNING
+		token_id`
+
+	var dest struct {
+		ID uuid.UUID `db:"token_id"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, toDBRefreshToken(rt), &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return fmt.Errorf("namedquerystruct: %w", session.ErrTokenReused)
+		}
+		return fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every token of a family that is not revoked yet.
+func (s *Store) RevokeFamily(ctx context.Context, familyID uuid.UUID, now time.Time) error {
+	data := map[string]interface{}{
+		"family_id":    familyID.String(),
+		"date_revoked": now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		"date_revoked" = :date_revoked`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "family_id = :family_id", "date_revoked IS NULL")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeByUserID revokes every token of the user that is not revoked yet,
+// across all of its families.
+func (s *Store) RevokeByUserID(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	data := map[string]interface{}{
+		"user_id":      userID.String(),
+		"date_revoked": now.UTC(),
+	}
+
+	const q = `
+	UPDATE
+		refresh_tokens
+	SET
+		"date_revoked" = :date_revoked`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id", "date_revoked IS NULL")
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, buf.String(), data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// QueryByHash finds the refresh token with the specified hash.
+func (s *Store) QueryByHash(ctx context.Context, hash string) (session.RefreshToken, error) {
+	data := map[string]interface{}{
+		"token_hash": hash,
+	}
+
+	const q = `
+	SELECT
+		token_id, family_id, tenant_id, user_id, amr, token_hash, date_created, date_expires, date_revoked
+	FROM
+		refresh_tokens`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "token_hash = :token_hash")
+	if err != nil {
+		return session.RefreshToken{}, err
+	}
+
+	buf.WriteString(clause)
+
+	var dbRT dbRefreshToken
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbRT); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return session.RefreshToken{}, fmt.Errorf("namedquerystruct: %w", session.ErrNotFound)
+		}
+		return session.RefreshToken{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreRefreshToken(dbRT), nil
+}
+
+// RevokeAccess records the jti of an access token that is no longer valid.
+// Revocations that expired are removed at the same time.
+func (s *Store) RevokeAccess(ctx context.Context, jti string, expires time.Time) error {
+	data := struct {
+		JTI         string    `db:"jti"`
+		DateExpires time.Time `db:"date_expires"`
+		Now         time.Time `db:"now"`
+	}{
+		JTI:         jti,
+		DateExpires: expires.UTC(),
+		Now:         time.Now().UTC(),
+	}
+
+	const qd = `
+	DELETE FROM
+		revoked_tokens
+	WHERE
+		date_expires < :now`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, qd, data); err != nil {
+		return fmt.Errorf("namedexeccontext: delete: %w", err)
+	}
+
+	const q = `
+	INSERT INTO revoked_tokens
+		(jti, date_expires)
+	VALUES
+		(:jti, :date_expires)
+	ON CONFLICT (jti) DO NOTHING`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// IsAccessRevoked reports whether the jti of an access token was revoked.
+func (s *Store) IsAccessRevoked(ctx context.Context, jti string) (bool, error) {
+	data := struct {
+		JTI string `db:"jti"`
+	}{
+		JTI: jti,
+	}
+
+	const q = `
+	SELECT
+		count(1)
+	FROM
+		revoked_tokens
+	WHERE
+		jti = :jti`
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &count); err != nil {
+		return false, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count > 0, nil
+}
+
+// =============================================================================
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
+	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
+}
@@ -0,0 +1,50 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoTenant is returned when a call is made with a context that is neither
+// scoped to a tenant nor marked as unscoped.
+var ErrNoTenant = errors.New("context is not scoped to a tenant")
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// Set of keys used to store/retrieve the scope of a context.Context.
+const (
+	key ctxKey = iota + 1
+	unscopedKey
+)
+
+// Set stores the tenant the calls made with the context are scoped to.
+func Set(ctx context.Context, tenantID uuid.UUID) context.Context {
+	return context.WithValue(ctx, key, tenantID)
+}
+
+// Unscoped marks the calls made with the context as not scoped to a tenant.
+// It's meant for the work that happens before a tenant is known or across
+// tenants, such as logins, the event relay and tooling. A tenant set on the
+// context afterwards takes precedence.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey, true)
+}
+
+// Get returns the tenant the calls made with the context are scoped to. The
+// bool is false when the context is marked as unscoped, in which case the
+// calls are not scoped. A context that has neither fails with ErrNoTenant, so
+// a call is never left unscoped by accident.
+func Get(ctx context.Context) (uuid.UUID, bool, error) {
+	if v, ok := ctx.Value(key).(uuid.UUID); ok {
+		return v, true, nil
+	}
+
+	if unscoped, _ := ctx.Value(unscopedKey).(bool); unscoped {
+		return uuid.Nil, false, nil
+	}
+
+	return uuid.Nil, false, ErrNoTenant
+}
@@ -0,0 +1,21 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant represents a business unit owning its own set of users and
+// products.
+type Tenant struct {
+	ID          uuid.UUID
+	Name        string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+// NewTenant is what we require from clients when adding a Tenant.
+type NewTenant struct {
+	Name string
+}
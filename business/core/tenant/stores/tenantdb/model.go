@@ -0,0 +1,42 @@
+package tenantdb
+
+import (
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/google/uuid"
+)
+
+type dbTenant struct {
+	ID          uuid.UUID `db:"tenant_id"`
+	Name        string    `db:"name"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBTenant(tnt tenant.Tenant) dbTenant {
+	return dbTenant{
+		ID:          tnt.ID,
+		Name:        tnt.Name,
+		DateCreated: tnt.DateCreated.UTC(),
+		DateUpdated: tnt.DateUpdated.UTC(),
+	}
+}
+
+func toCoreTenant(dbTnt dbTenant) tenant.Tenant {
+	return tenant.Tenant{
+		ID:          dbTnt.ID,
+		Name:        dbTnt.Name,
+		DateCreated: dbTnt.DateCreated.In(time.Local),
+		DateUpdated: dbTnt.DateUpdated.In(time.Local),
+	}
+}
+
+func toCoreTenantSlice(dbTnts []dbTenant) []tenant.Tenant {
+	tnts := make([]tenant.Tenant, len(dbTnts))
+	for i, dbTnt := range dbTnts {
+		tnts[i] = toCoreTenant(dbTnt)
+	}
+
+	return tnts
+}
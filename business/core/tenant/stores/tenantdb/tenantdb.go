@@ -0,0 +1,108 @@
+// Package tenantdb contains tenant related CRUD functionality.
+package tenantdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Store manages the set of APIs for tenant database access.
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+// NewStore constructs the api for data access.
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
+// value with a sqlx DB value that is currently inside a transaction.
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (tenant.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		log: s.log,
+		db:  ec,
+	}
+
+	return s, nil
+}
+
+// Create inserts a new tenant into the database.
+func (s *Store) Create(ctx context.Context, tnt tenant.Tenant) error {
+	const q = `
+	INSERT INTO tenants
+		(tenant_id, name, date_created, date_updated)
+	VALUES
+		(:tenant_id, :name, :date_created, :date_updated)`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBTenant(tnt)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicatedEntry) {
+			return fmt.Errorf("namedexeccontext: %w", tenant.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves every tenant from the database.
+func (s *Store) Query(ctx context.Context) ([]tenant.Tenant, error) {
+	const q = `
+	SELECT
+		tenant_id, name, date_created, date_updated
+	FROM
+		tenants
+	ORDER BY
+		name`
+
+	var dbTnts []dbTenant
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, struct{}{}, &dbTnts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreTenantSlice(dbTnts), nil
+}
+
+// QueryByID gets the specified tenant from the database.
+func (s *Store) QueryByID(ctx context.Context, tenantID uuid.UUID) (tenant.Tenant, error) {
+	data := struct {
+		ID string `db:"tenant_id"`
+	}{
+		ID: tenantID.String(),
+	}
+
+	const q = `
+	SELECT
+		tenant_id, name, date_created, date_updated
+	FROM
+		tenants
+	WHERE
+		tenant_id = :tenant_id`
+
+	var dbTnt dbTenant
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbTnt); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return tenant.Tenant{}, fmt.Errorf("namedquerystruct: %w", tenant.ErrNotFound)
+		}
+		return tenant.Tenant{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreTenant(dbTnt), nil
+}
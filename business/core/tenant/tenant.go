@@ -0,0 +1,107 @@
+// Package tenant provides the core business API for the tenants the users
+// and products of the system are partitioned into.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
+	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for tenant operations.
+var (
+	ErrNotFound   = errors.New("tenant not found")
+	ErrUniqueName = errors.New("tenant name is not unique")
+)
+
+// Default is the tenant owning the rows that existed before tenants were
+// introduced and the users created outside of a tenant. Its roles are the
+// only ones that may grant the platform permissions, such as managing the
+// tenants.
+var Default = uuid.MustParse("f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21")
+
+// =============================================================================
+
+// Storer interface declares the behavior this package needs to perists and
+// retrieve data.
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, tnt Tenant) error
+	Query(ctx context.Context) ([]Tenant, error)
+	QueryByID(ctx context.Context, tenantID uuid.UUID) (Tenant, error)
+}
+
+// =============================================================================
+
+// Core manages the set of APIs for tenant access.
+type Core struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewCore constructs a core for tenant api access.
+func NewCore(log *logger.Logger, storer Storer) *Core {
+	return &Core{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// ExecuteUnderTransaction constructs a new Core value that will use the
+// specified transaction in any store related calls.
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		log:    c.log,
+		storer: storer,
+	}
+
+	return c, nil
+}
+
+// Create adds a new tenant to the database.
+func (c *Core) Create(ctx context.Context, nt NewTenant) (Tenant, error) {
+	now := time.Now()
+
+	tnt := Tenant{
+		ID:          uuid.New(),
+		Name:        nt.Name,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, tnt); err != nil {
+		return Tenant{}, fmt.Errorf("create: %w", err)
+	}
+
+	return tnt, nil
+}
+
+// Query retrieves every tenant.
+func (c *Core) Query(ctx context.Context) ([]Tenant, error) {
+	tnts, err := c.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tnts, nil
+}
+
+// QueryByID finds the tenant by the specified ID.
+func (c *Core) QueryByID(ctx context.Context, tenantID uuid.UUID) (Tenant, error) {
+	tnt, err := c.storer.QueryByID(ctx, tenantID)
+	if err != nil {
+		return Tenant{}, fmt.Errorf("query: tenantID[%s]: %w", tenantID, err)
+	}
+
+	return tnt, nil
+}
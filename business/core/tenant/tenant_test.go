@@ -0,0 +1,280 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
+	ordering "github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	m.Run()
+}
+
+func Test_Tenant(t *testing.T) {
+	t.Run("crud", crud)
+	t.Run("isolation", isolation)
+}
+
+// =============================================================================
+
+func crud(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	tnt, err := api.Tenant.Create(ctx, tenant.NewTenant{Name: "Retail"})
+	if err != nil {
+		t.Fatalf("Should be able to create a tenant : %s", err)
+	}
+
+	got, err := api.Tenant.QueryByID(ctx, tnt.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the tenant : %s", err)
+	}
+
+	if got.Name != "Retail" {
+		t.Fatalf("Should get back the same tenant : got %q", got.Name)
+	}
+
+	if _, err := api.Tenant.Create(ctx, tenant.NewTenant{Name: "Retail"}); !errors.Is(err, tenant.ErrUniqueName) {
+		t.Fatalf("Should NOT be able to create a tenant with the same name : %v", err)
+	}
+
+	tnts, err := api.Tenant.Query(ctx)
+	if err != nil {
+		t.Fatalf("Should be able to query the tenants : %s", err)
+	}
+
+	if len(tnts) != 2 {
+		t.Fatalf("Should get the default tenant and the new one : got %d", len(tnts))
+	}
+}
+
+func isolation(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	tnt, err := api.Tenant.Create(ctx, tenant.NewTenant{Name: "Wholesale"})
+	if err != nil {
+		t.Fatalf("Should be able to create a tenant : %s", err)
+	}
+
+	ctxDefault := tenant.Set(ctx, tenant.Default)
+	ctxOther := tenant.Set(ctx, tnt.ID)
+
+	usr, err := api.User.Create(ctxOther, user.NewUser{
+		Name:            "Tenant Gopher",
+		Email:           mail.Address{Address: "tenant@example.com"},
+		Roles:           []user.Role{user.RoleUser},
+		Password:        "gophers",
+		PasswordConfirm: "gophers",
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a user in the tenant : %s", err)
+	}
+
+	if usr.TenantID != tnt.ID {
+		t.Fatalf("Should create the user in the tenant of the context : got %s", usr.TenantID)
+	}
+
+	prd, err := api.Product.Create(ctxOther, product.NewProduct{
+		UserID:   usr.ID,
+		Name:     "Crates",
+		Cost:     10,
+		Quantity: 5,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a product in the tenant : %s", err)
+	}
+
+	if prd.TenantID != tnt.ID {
+		t.Fatalf("Should create the product in the tenant of its user : got %s", prd.TenantID)
+	}
+
+	ord, err := api.Order.Create(ctxOther, order.NewOrder{
+		UserID: usr.ID,
+		Items:  []order.NewItem{{ProductID: prd.ID, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to place an order in the tenant : %s", err)
+	}
+
+	if ord.TenantID != tnt.ID {
+		t.Fatalf("Should place the order in the tenant of its user : got %s", ord.TenantID)
+	}
+
+	_, key, err := api.APIKey.Create(ctxOther, apikey.NewAPIKey{
+		UserID:       usr.ID,
+		Name:         "Tenant",
+		Roles:        []user.Role{user.RoleUser},
+		GrantorRoles: []user.Role{user.RoleUser},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create an api key in the tenant : %s", err)
+	}
+
+	if key.TenantID != tnt.ID {
+		t.Fatalf("Should create the api key in the tenant of its user : got %s", key.TenantID)
+	}
+
+	// -------------------------------------------------------------------------
+	// The rows of the tenant are visible within the tenant.
+
+	if _, err := api.User.QueryByID(ctxOther, usr.ID); err != nil {
+		t.Fatalf("Should be able to retrieve the user within the tenant : %s", err)
+	}
+
+	if _, err := api.Product.QueryByID(ctxOther, prd.ID); err != nil {
+		t.Fatalf("Should be able to retrieve the product within the tenant : %s", err)
+	}
+
+	if _, err := api.Order.QueryByID(ctxOther, ord.ID); err != nil {
+		t.Fatalf("Should be able to retrieve the order within the tenant : %s", err)
+	}
+
+	if _, err := api.APIKey.QueryByID(ctxOther, key.ID); err != nil {
+		t.Fatalf("Should be able to retrieve the api key within the tenant : %s", err)
+	}
+
+	n, err := api.User.Count(ctxOther, user.QueryFilter{})
+	if err != nil {
+		t.Fatalf("Should be able to count the users : %s", err)
+	}
+
+	if n != 1 {
+		t.Fatalf("Should only count the users of the tenant : got %d", n)
+	}
+
+	// -------------------------------------------------------------------------
+	// The rows of the tenant are not visible from another tenant.
+
+	if _, err := api.User.QueryByID(ctxDefault, usr.ID); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve the user from another tenant : %v", err)
+	}
+
+	if _, err := api.User.QueryByEmail(ctxDefault, usr.Email); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve the user by email from another tenant : %v", err)
+	}
+
+	if _, err := api.Product.QueryByID(ctxDefault, prd.ID); !errors.Is(err, product.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve the product from another tenant : %v", err)
+	}
+
+	if _, err := api.Order.QueryByID(ctxDefault, ord.ID); !errors.Is(err, order.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve the order from another tenant : %v", err)
+	}
+
+	ords, err := api.Order.Query(ctxDefault, order.QueryFilter{}, ordering.By{Field: order.OrderByOrderID, Direction: ordering.ASC}, 1, 100)
+	if err != nil {
+		t.Fatalf("Should be able to query the orders : %s", err)
+	}
+
+	for _, o := range ords {
+		if o.ID == ord.ID {
+			t.Fatalf("Should NOT get the order of another tenant")
+		}
+	}
+
+	if _, err := api.APIKey.QueryByID(ctxDefault, key.ID); !errors.Is(err, apikey.ErrNotFound) {
+		t.Fatalf("Should NOT be able to retrieve the api key from another tenant : %v", err)
+	}
+
+	prds, err := api.Product.Query(ctxDefault, product.QueryFilter{}, ordering.By{Field: product.OrderByProdID, Direction: ordering.ASC}, 1, 100)
+	if err != nil {
+		t.Fatalf("Should be able to query the products : %s", err)
+	}
+
+	for _, p := range prds {
+		if p.ID == prd.ID {
+			t.Fatalf("Should NOT get the product of another tenant")
+		}
+	}
+
+	usrs, err := api.User.QueryByIDs(ctxDefault, []uuid.UUID{usr.ID})
+	if err != nil {
+		t.Fatalf("Should be able to query the users by ids : %s", err)
+	}
+
+	if len(usrs) != 0 {
+		t.Fatalf("Should NOT get the users of another tenant : got %d", len(usrs))
+	}
+
+	smms, err := api.UserSummary.Query(ctxDefault, usersummary.QueryFilter{}, ordering.By{Field: usersummary.OrderByUserID, Direction: ordering.ASC}, 1, 100)
+	if err != nil {
+		t.Fatalf("Should be able to query the summaries : %s", err)
+	}
+
+	for _, smm := range smms {
+		if smm.UserID == usr.ID {
+			t.Fatalf("Should NOT get the summary of a user of another tenant")
+		}
+	}
+
+	if _, err := api.Product.Create(ctxDefault, product.NewProduct{UserID: usr.ID, Name: "Crates", Cost: 10, Quantity: 5}); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("Should NOT be able to create a product for a user of another tenant : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+	// Calls made without a tenant or the unscoped mark are refused.
+
+	ctxNone, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := api.User.QueryByID(ctxNone, usr.ID); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("Should NOT be able to retrieve a user without a tenant : %v", err)
+	}
+
+	if _, err := api.Product.Count(ctxNone, product.QueryFilter{}); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("Should NOT be able to count the products without a tenant : %v", err)
+	}
+
+	if _, err := api.UserSummary.Count(ctxNone, usersummary.QueryFilter{}); !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("Should NOT be able to count the summaries without a tenant : %v", err)
+	}
+}
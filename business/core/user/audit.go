@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/google/uuid"
+)
+
+// AuditEntity represents the name of users in the audit log.
+const AuditEntity = "user"
+
+// auditUser is the representation of a user recorded in the audit log. The
+// password hash is left out so it never ends up in the log, a change of
+// password is recorded through the passwordChanged field instead.
+type auditUser struct {
+	ID              uuid.UUID `json:"id"`
+	TenantID        uuid.UUID `json:"tenantID"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	EmailVerified   bool      `json:"emailVerified"`
+	Roles           []string  `json:"roles"`
+	Department      string    `json:"department"`
+	Enabled         bool      `json:"enabled"`
+	FailedLogins    int       `json:"failedLogins"`
+	LockedUntil     time.Time `json:"lockedUntil"`
+	PasswordChanged bool      `json:"passwordChanged,omitempty"`
+}
+
+func toAuditUser(usr User) auditUser {
+	roles := make([]string, len(usr.Roles))
+	for i, role := range usr.Roles {
+		roles[i] = role.Name()
+	}
+
+	return auditUser{
+		ID:            usr.ID,
+		TenantID:      usr.TenantID,
+		Name:          usr.Name,
+		Email:         usr.Email.Address,
+		EmailVerified: usr.EmailVerified,
+		Roles:         roles,
+		Department:    usr.Department,
+		Enabled:       usr.Enabled,
+		FailedLogins:  usr.FailedLogins,
+		LockedUntil:   usr.LockedUntil.UTC(),
+	}
+}
+
+// recordUpdate adds an entry to the audit log for the update of the user.
+func (c *Core) recordUpdate(ctx context.Context, before User, after User) error {
+	b := toAuditUser(before)
+	a := toAuditUser(after)
+	a.PasswordChanged = string(before.PasswordHash) != string(after.PasswordHash)
+
+	return c.audCore.Record(ctx, audit.NewEntry{
+		TenantID: after.TenantID,
+		Entity:   AuditEntity,
+		EntityID: after.ID,
+		Action:   audit.ActionUpdate,
+		Before:   b,
+		After:    a,
+	})
+}
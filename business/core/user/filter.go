@@ -5,6 +5,7 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
@@ -15,6 +16,9 @@ type QueryFilter struct {
 	Email            *mail.Address `validate:"omitempty"`
 	StartCreatedDate *time.Time    `validate:"omitempty"`
 	EndCreatedDate   *time.Time    `validate:"omitempty"`
+	IDs              []uuid.UUID
+
+	DateCreatedRange query.Range[time.Time]
 }
 
 func (qf *QueryFilter) Validate() error {
@@ -45,3 +49,11 @@ func (qf *QueryFilter) WithEndCreatedDate(endDate time.Time) {
 	d := endDate.UTC()
 	qf.EndCreatedDate = &d
 }
+
+func (qf *QueryFilter) WithUserIDs(userIDs []uuid.UUID) {
+	qf.IDs = userIDs
+}
+
+func (qf *QueryFilter) WithDateCreatedRange(dateCreated query.Range[time.Time]) {
+	qf.DateCreatedRange = dateCreated
+}
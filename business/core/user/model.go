@@ -9,18 +9,30 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID
-	Name         string
-	Email        mail.Address
-	Roles        []Role
-	PasswordHash []byte
-	Department   string
-	Enabled      bool
-	DateCreated  time.Time
-	DateUpdated  time.Time
+	ID            uuid.UUID
+	TenantID      uuid.UUID
+	Name          string
+	Email         mail.Address
+	EmailVerified bool
+	Roles         []Role
+	PasswordHash  []byte
+	Department    string
+	Enabled       bool
+	FailedLogins  int
+	LockedUntil   time.Time
+	DateCreated   time.Time
+	DateUpdated   time.Time
+	Version       int
+}
+
+// Locked reports whether the user is locked out of logging in at the
+// specified time.
+func (u User) Locked(now time.Time) bool {
+	return u.LockedUntil.After(now)
 }
 
 type NewUser struct {
+	TenantID        uuid.UUID
 	Name            string
 	Email           mail.Address
 	Roles           []Role
@@ -1,6 +1,11 @@
 package user
 
-import "github.com/diegomagalhaes-dev/go-service/business/data/order"
+import (
+	"strconv"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
+)
 
 // DefaultOrderBy represents the default way we sort.
 var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
@@ -14,3 +19,23 @@ const (
 	OrderByRoles   = "roles"
 	OrderByEnabled = "enabled"
 )
+
+// cursorKey returns the key of the user within the ordering by the field.
+func cursorKey(usr User, field string) page.Key {
+	key := page.Key{
+		ID: usr.ID.String(),
+	}
+
+	switch field {
+	case OrderByID:
+		key.Value = usr.ID.String()
+	case OrderByName:
+		key.Value = usr.Name
+	case OrderByEmail:
+		key.Value = usr.Email.Address
+	case OrderByEnabled:
+		key.Value = strconv.FormatBool(usr.Enabled)
+	}
+
+	return key
+}
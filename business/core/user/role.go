@@ -1,32 +1,33 @@
 package user
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
 
-// Set of possible roles for a user.
+// Set of built in roles for a user. Other roles are stored in the database
+// along with the permissions they grant.
 var (
 	RoleAdmin = Role{"ADMIN"}
 	RoleUser  = Role{"USER"}
 )
 
-// Set of known roles.
-var roles = map[string]Role{
-	RoleAdmin.name: RoleAdmin,
-	RoleUser.name:  RoleUser,
-}
+// roleName is the format of the name of a role.
+var roleName = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
 
 // Role represents a role in the system.
 type Role struct {
 	name string
 }
 
-// ParseRole parses the string value and returns a role if one exists.
+// ParseRole parses the string value and returns a role if the value is a
+// properly formed role name. Whether the role exists is up to the caller.
 func ParseRole(value string) (Role, error) {
-	role, exists := roles[value]
-	if !exists {
+	if !roleName.MatchString(value) {
 		return Role{}, fmt.Errorf("invalid role %q", value)
 	}
 
-	return role, nil
+	return Role{value}, nil
 }
 
 // MustParseRole parses the string value and returns a role if one exists. If
@@ -45,6 +46,11 @@ func (r Role) Name() string {
 	return r.name
 }
 
+// BuiltIn reports whether the role is one the system depends on.
+func (r Role) BuiltIn() bool {
+	return r == RoleAdmin || r == RoleUser
+}
+
 // UnmarshalText implement the unmarshal interface for JSON conversions.
 func (r *Role) UnmarshalText(data []byte) error {
 	role, err := ParseRole(string(data))
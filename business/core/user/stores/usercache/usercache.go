@@ -7,8 +7,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/google/uuid"
@@ -34,9 +36,17 @@ func NewStore(log *logger.Logger, storer user.Storer) *Store {
 }
 
 // ExecuteUnderTransaction constructs a new Store value replacing the sqlx DB
-// value with a sqlx DB value that is currently inside a transaction.
+// value with a sqlx DB value that is currently inside a transaction. The
+// cache is bypassed inside the transaction and the users written through it
+// are evicted once it commits, so a stale version of them is not served
+// afterwards.
 func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (user.Storer, error) {
-	return s.storer.ExecuteUnderTransaction(tx)
+	storer, err := s.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txStore{Storer: storer, cache: s, tx: tx}, nil
 }
 
 // Create inserts a new user into the database.
@@ -63,6 +73,41 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 	return nil
 }
 
+// UpdateLogin replaces the failed logins and lockout of a user in the database.
+func (s *Store) UpdateLogin(ctx context.Context, usr user.User) error {
+	if err := s.storer.UpdateLogin(ctx, usr); err != nil {
+		return err
+	}
+
+	ttl := 10 * time.Minute
+	s.writeCache(usr, ttl)
+
+	return nil
+}
+
+// AddFailedLogin counts a failed login of a user in the database.
+func (s *Store) AddFailedLogin(ctx context.Context, usr user.User) (int, error) {
+	failures, err := s.storer.AddFailedLogin(ctx, usr)
+	if err != nil {
+		return 0, err
+	}
+
+	s.deleteCache(usr)
+
+	return failures, nil
+}
+
+// LockLogin locks a user out of logging in.
+func (s *Store) LockLogin(ctx context.Context, usr user.User) error {
+	if err := s.storer.LockLogin(ctx, usr); err != nil {
+		return err
+	}
+
+	s.deleteCache(usr)
+
+	return nil
+}
+
 // Delete removes a user from the database.
 func (s *Store) Delete(ctx context.Context, usr user.User) error {
 	if err := s.storer.Delete(ctx, usr); err != nil {
@@ -79,6 +124,11 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 	return s.storer.Query(ctx, filter, orderBy, pageNumber, rowsPerPage)
 }
 
+// QueryByCursor retrieves a list of existing users past the key of the cursor.
+func (s *Store) QueryByCursor(ctx context.Context, filter user.QueryFilter, cur page.Cursor, rowsPerPage int) ([]user.User, error) {
+	return s.storer.QueryByCursor(ctx, filter, cur, rowsPerPage)
+}
+
 // Count returns the total number of cards in the DB.
 func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error) {
 	return s.storer.Count(ctx, filter)
@@ -86,7 +136,7 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 
 // QueryByID gets the specified user from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error) {
-	cachedUsr, ok := s.readCache(userID.String())
+	cachedUsr, ok := s.readCache(ctx, userID.String())
 	if ok {
 		return cachedUsr, nil
 	}
@@ -114,7 +164,7 @@ func (s *Store) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]user.Use
 
 // QueryByEmail gets the specified user from the database by email.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User, error) {
-	cachedUsr, ok := s.readCache(email.Address)
+	cachedUsr, ok := s.readCache(ctx, email.Address)
 	if ok {
 		return cachedUsr, nil
 	}
@@ -132,8 +182,10 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User
 
 // =============================================================================
 
-// readCache performs a safe search in the cache for the specified key.
-func (s *Store) readCache(key string) (user.User, bool) {
+// readCache performs a safe search in the cache for the specified key. A user
+// of another tenant than the one of the context is treated as a miss so the
+// scoped store decides the outcome.
+func (s *Store) readCache(ctx context.Context, key string) (user.User, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -149,6 +201,11 @@ func (s *Store) readCache(key string) (user.User, bool) {
 		return user.User{}, false
 	}
 
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil || (scoped && usr.TenantID != tenantID) {
+		return user.User{}, false
+	}
+
 	return usr, true
 }
 
@@ -174,3 +231,78 @@ func (s *Store) deleteCache(usr user.User) {
 	delete(s.expiration, usr.ID.String())
 	delete(s.expiration, usr.Email.Address)
 }
+
+// =============================================================================
+
+// txStore uses the transaction for every call and evicts the users it writes
+// from the cache. The eviction waits for the commit, a user evicted before
+// could be cached again from its committed row by a concurrent read and be
+// served stale until it expires.
+type txStore struct {
+	user.Storer
+	cache *Store
+	tx    transaction.Transaction
+}
+
+// Update replaces a user document in the database.
+func (s *txStore) Update(ctx context.Context, usr user.User) error {
+	if err := s.Storer.Update(ctx, usr); err != nil {
+		return err
+	}
+
+	s.evict(usr)
+
+	return nil
+}
+
+// UpdateLogin replaces the failed logins and lockout of a user in the database.
+func (s *txStore) UpdateLogin(ctx context.Context, usr user.User) error {
+	if err := s.Storer.UpdateLogin(ctx, usr); err != nil {
+		return err
+	}
+
+	s.evict(usr)
+
+	return nil
+}
+
+// AddFailedLogin counts a failed login of a user in the database.
+func (s *txStore) AddFailedLogin(ctx context.Context, usr user.User) (int, error) {
+	failures, err := s.Storer.AddFailedLogin(ctx, usr)
+	if err != nil {
+		return 0, err
+	}
+
+	s.evict(usr)
+
+	return failures, nil
+}
+
+// LockLogin locks a user out of logging in.
+func (s *txStore) LockLogin(ctx context.Context, usr user.User) error {
+	if err := s.Storer.LockLogin(ctx, usr); err != nil {
+		return err
+	}
+
+	s.evict(usr)
+
+	return nil
+}
+
+// Delete removes a user from the database.
+func (s *txStore) Delete(ctx context.Context, usr user.User) error {
+	if err := s.Storer.Delete(ctx, usr); err != nil {
+		return err
+	}
+
+	s.evict(usr)
+
+	return nil
+}
+
+// evict removes the user from the cache once the transaction commits.
+func (s *txStore) evict(usr user.User) {
+	transaction.AfterCommit(s.tx, func() {
+		s.cache.deleteCache(usr)
+	})
+}
@@ -2,15 +2,16 @@ package userdb
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
 )
 
-func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(ctx context.Context, filter user.QueryFilter, data map[string]interface{}, buf *bytes.Buffer, wc ...string) error {
 	if filter.ID != nil {
 		data["user_id"] = *filter.ID
 		wc = append(wc, "user_id = :user_id")
@@ -18,12 +19,12 @@ func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}
 
 	if filter.Name != nil {
 		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
-		wc = append(wc, "name LIKE :name")
+		wc = append(wc, "name ILIKE :name")
 	}
 
 	if filter.Email != nil {
-		data["email"] = (*filter.Email).String()
-		wc = append(wc, "email = :email")
+		data["email"] = filter.Email.Address
+		wc = append(wc, "LOWER(email) = LOWER(:email)")
 	}
 
 	if filter.StartCreatedDate != nil {
@@ -36,8 +37,43 @@ func (s *Store) applyFilter(filter user.QueryFilter, data map[string]interface{}
 		wc = append(wc, "date_created <= :end_date_created")
 	}
 
-	if len(wc) > 0 {
-		buf.WriteString(" WHERE ")
-		buf.WriteString(strings.Join(wc, " AND "))
+	if len(filter.IDs) > 0 {
+		ids := make([]string, len(filter.IDs))
+		for i, id := range filter.IDs {
+			ids[i] = id.String()
+		}
+
+		data["user_ids"] = dbarray.Array(ids)
+		wc = append(wc, "user_id = ANY(:user_ids)")
+	}
+
+	wc = append(wc, filter.DateCreatedRange.Where("date_created", "date_created", data)...)
+
+	clause, err := where(ctx, data, wc...)
+	if err != nil {
+		return err
+	}
+
+	buf.WriteString(clause)
+	return nil
+}
+
+// where returns the where clause for the conditions, scoped to the tenant of
+// the context unless the context is marked as unscoped.
+func where(ctx context.Context, data map[string]interface{}, wc ...string) (string, error) {
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
 	}
+
+	if len(wc) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(wc, " AND "), nil
 }
@@ -12,15 +12,20 @@ import (
 )
 
 type dbUser struct {
-	ID           uuid.UUID      `db:"user_id"`
-	Name         string         `db:"name"`
-	Email        string         `db:"email"`
-	Roles        dbarray.String `db:"roles"`
-	PasswordHash []byte         `db:"password_hash"`
-	Department   sql.NullString `db:"department"`
-	Enabled      bool           `db:"enabled"`
-	DateCreated  time.Time      `db:"date_created"`
-	DateUpdated  time.Time      `db:"date_updated"`
+	ID            uuid.UUID      `db:"user_id"`
+	TenantID      uuid.UUID      `db:"tenant_id"`
+	Name          string         `db:"name"`
+	Email         string         `db:"email"`
+	EmailVerified bool           `db:"email_verified"`
+	Roles         dbarray.String `db:"roles"`
+	PasswordHash  []byte         `db:"password_hash"`
+	Department    sql.NullString `db:"department"`
+	Enabled       bool           `db:"enabled"`
+	FailedLogins  int            `db:"failed_logins"`
+	LockedUntil   sql.NullTime   `db:"date_locked_until"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
+	Version       int            `db:"version"`
 }
 
 func toDBUser(usr user.User) dbUser {
@@ -30,18 +35,26 @@ func toDBUser(usr user.User) dbUser {
 	}
 
 	return dbUser{
-		ID:           usr.ID,
-		Name:         usr.Name,
-		Email:        usr.Email.Address,
-		Roles:        roles,
-		PasswordHash: usr.PasswordHash,
+		ID:            usr.ID,
+		TenantID:      usr.TenantID,
+		Name:          usr.Name,
+		Email:         usr.Email.Address,
+		EmailVerified: usr.EmailVerified,
+		Roles:         roles,
+		PasswordHash:  usr.PasswordHash,
 		Department: sql.NullString{
 			String: usr.Department,
 			Valid:  usr.Department != "",
 		},
-		Enabled:     usr.Enabled,
+		Enabled:      usr.Enabled,
+		FailedLogins: usr.FailedLogins,
+		LockedUntil: sql.NullTime{
+			Time:  usr.LockedUntil.UTC(),
+			Valid: !usr.LockedUntil.IsZero(),
+		},
 		DateCreated: usr.DateCreated.UTC(),
 		DateUpdated: usr.DateUpdated.UTC(),
+		Version:     usr.Version,
 	}
 }
 
@@ -60,15 +73,23 @@ func toCoreUser(dbUsr dbUser) (user.User, error) {
 	}
 
 	usr := user.User{
-		ID:           dbUsr.ID,
-		Name:         dbUsr.Name,
-		Email:        addr,
-		Roles:        roles,
-		PasswordHash: dbUsr.PasswordHash,
-		Enabled:      dbUsr.Enabled,
-		Department:   dbUsr.Department.String,
-		DateCreated:  dbUsr.DateCreated.In(time.Local),
-		DateUpdated:  dbUsr.DateUpdated.In(time.Local),
+		ID:            dbUsr.ID,
+		TenantID:      dbUsr.TenantID,
+		Name:          dbUsr.Name,
+		Email:         addr,
+		EmailVerified: dbUsr.EmailVerified,
+		Roles:         roles,
+		PasswordHash:  dbUsr.PasswordHash,
+		Enabled:       dbUsr.Enabled,
+		Department:    dbUsr.Department.String,
+		DateCreated:   dbUsr.DateCreated.In(time.Local),
+		DateUpdated:   dbUsr.DateUpdated.In(time.Local),
+		FailedLogins:  dbUsr.FailedLogins,
+		Version:       dbUsr.Version,
+	}
+
+	if dbUsr.LockedUntil.Valid {
+		usr.LockedUntil = dbUsr.LockedUntil.Time.In(time.Local)
 	}
 
 	return usr, nil
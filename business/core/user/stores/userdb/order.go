@@ -2,9 +2,11 @@ package userdb
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 )
 
 var orderByFields = map[string]string{
@@ -16,10 +18,55 @@ var orderByFields = map[string]string{
 }
 
 func orderByClause(orderBy order.By) (string, error) {
-	by, exists := orderByFields[orderBy.Field]
-	if !exists {
-		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	fields := orderBy.Fields()
+
+	clauses := make([]string, len(fields))
+	for i, field := range fields {
+		by, exists := orderByFields[field.Field]
+		if !exists {
+			return "", fmt.Errorf("field %q does not exist", field.Field)
+		}
+
+		clauses[i] = by + " " + field.Direction
+	}
+
+	return " ORDER BY " + strings.Join(clauses, ", "), nil
+}
+
+// keysetFields holds the fields the users can be read through a cursor
+// by, along with the type the value of a key is cast to.
+var keysetFields = map[string]string{
+	user.OrderByID:      "UUID",
+	user.OrderByName:    "TEXT",
+	user.OrderByEmail:   "TEXT",
+	user.OrderByEnabled: "BOOLEAN",
+}
+
+// keysetClause returns the conditions selecting the users past the key of
+// the cursor and the order they must be read in. The id breaks the ties so
+// the key points at a single row.
+func keysetClause(cur page.Cursor, data map[string]interface{}) ([]string, string, error) {
+	by, exists := orderByFields[cur.OrderBy.Field]
+	typ, supported := keysetFields[cur.OrderBy.Field]
+	if !exists || !supported || len(cur.OrderBy.Then) > 0 {
+		return nil, "", fmt.Errorf("field %q: %w", cur.OrderBy.Field, page.ErrCursorOrder)
 	}
 
-	return " ORDER BY " + by + " " + orderBy.Direction, nil
+	op, direction := cur.Seek()
+	orderBy := " ORDER BY " + by + " " + direction + ", user_id " + direction
+
+	if cur.Key == nil {
+		return nil, orderBy, nil
+	}
+
+	if err := cur.Key.Check(typ); err != nil {
+		return nil, "", err
+	}
+
+	data["cursor_value"] = cur.Key.Value
+	data["cursor_id"] = cur.Key.ID
+
+	wc := fmt.Sprintf("(%s, user_id) %s (CAST(:cursor_value AS %s), CAST(:cursor_id AS UUID))", by, op, typ)
+
+	return []string{wc}, orderBy, nil
 }
@@ -4,8 +4,6 @@ package userdb
 import (
 	"bytes"
 	"context"
-	"database/sql"
-	"database/sql/driver"
 	"errors"
 	"fmt"
 	"net/mail"
@@ -14,6 +12,7 @@ import (
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/google/uuid"
@@ -54,9 +53,9 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (user.Storer
 func (s *Store) Create(ctx context.Context, usr user.User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, enabled, department, date_created, date_updated)
+		(user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, date_created, date_updated, version)
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :enabled, :department, :date_created, :date_updated)`
+		(:user_id, :tenant_id, :name, :email, :email_verified, :password_hash, :roles, :enabled, :department, :date_created, :date_updated, :version)`
 
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		if errors.Is(err, db.ErrDBDuplicatedEntry) {
@@ -68,7 +67,9 @@ func (s *Store) Create(ctx context.Context, usr user.User) error {
 	return nil
 }
 
-// Update replaces a user document in the database.
+// Update replaces a user document in the database. The user carries the
+// version it is moving to, so the update only happens when the stored version
+// is the one preceding it.
 func (s *Store) Update(ctx context.Context, usr user.User) error {
 	const q = `
 	UPDATE
@@ -76,17 +77,95 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 	SET 
 		"name" = :name,
 		"email" = :email,
+		"email_verified" = :email_verified,
 		"roles" = :roles,
 		"password_hash" = :password_hash,
 		"department" = :department,
-		"date_updated" = :date_updated
+		"enabled" = :enabled,
+		"date_updated" = :date_updated,
+		"version" = :version
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND tenant_id = :tenant_id AND version = :version - 1`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, toDBUser(usr))
+	if err != nil {
 		if errors.Is(err, db.ErrDBDuplicatedEntry) {
 			return user.ErrUniqueEmail
 		}
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", user.ErrConcurrentModification)
+	}
+
+	return nil
+}
+
+// UpdateLogin replaces the failed logins and lockout of a user in the database.
+func (s *Store) UpdateLogin(ctx context.Context, usr user.User) error {
+	const q = `
+	UPDATE
+		users
+	SET
+		"failed_logins" = :failed_logins,
+		"date_locked_until" = :date_locked_until
+	WHERE
+		user_id = :user_id AND tenant_id = :tenant_id`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+// AddFailedLogin counts a failed login of a user in the database and returns
+// the failed logins of the user, so concurrent failures are all counted.
+func (s *Store) AddFailedLogin(ctx context.Context, usr user.User) (int, error) {
+	data := struct {
+		UserID   string `db:"user_id"`
+		TenantID string `db:"tenant_id"`
+	}{
+		UserID:   usr.ID.String(),
+		TenantID: usr.TenantID.String(),
+	}
+
+	const q = `
+	UPDATE
+		users
+	SET
+		"failed_logins" = failed_logins + 1
+	WHERE
+		user_id = :user_id AND tenant_id = :tenant_id
+	RETURNING
+		failed_logins`
+
+	var dest struct {
+		FailedLogins int `db:"failed_logins"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dest); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return 0, fmt.Errorf("namedquerystruct: %w", user.ErrNotFound)
+		}
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return dest.FailedLogins, nil
+}
+
+// LockLogin locks a user out of logging in until the lockout of the user. A
+// lockout that ends later is kept.
+func (s *Store) LockLogin(ctx context.Context, usr user.User) error {
+	const q = `
+	UPDATE
+		users
+	SET
+		"date_locked_until" = GREATEST(date_locked_until, :date_locked_until)
+	WHERE
+		user_id = :user_id AND tenant_id = :tenant_id`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -96,19 +175,28 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 // Delete removes a user from the database.
 func (s *Store) Delete(ctx context.Context, usr user.User) error {
 	data := struct {
-		UserID string `db:"user_id"`
+		UserID   string `db:"user_id"`
+		TenantID string `db:"tenant_id"`
+		Version  int    `db:"version"`
 	}{
-		UserID: usr.ID.String(),
+		UserID:   usr.ID.String(),
+		TenantID: usr.TenantID.String(),
+		Version:  usr.Version,
 	}
 
 	const q = `
 	DELETE FROM
 		users
 	WHERE
-		user_id = :user_id`
+		user_id = :user_id AND tenant_id = :tenant_id AND version = :version`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
-		return fmt.Errorf("namedexeccontext: %w", err)
+	n, err := db.NamedExecContextWithCount(ctx, s.log, s.db, q, data)
+	if err != nil {
+		return fmt.Errorf("namedexeccontextwithcount: %w", err)
+	}
+
+	if n == 0 {
+		return fmt.Errorf("namedexeccontextwithcount: %w", user.ErrConcurrentModification)
 	}
 
 	return nil
@@ -123,12 +211,14 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 
 	const q = `
 	SELECT
-		user_id, name, email, password_hash, roles, enabled, department, date_created, date_updated
+		user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, failed_logins, date_locked_until, date_created, date_updated, version
 	FROM
 		users`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, err
+	}
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -151,6 +241,45 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 	return usrs, nil
 }
 
+// QueryByCursor gets the Users from the database past the key of the
+// cursor, in the order the cursor reads them.
+func (s *Store) QueryByCursor(ctx context.Context, filter user.QueryFilter, cur page.Cursor, rowsPerPage int) ([]user.User, error) {
+	data := map[string]interface{}{
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, failed_logins, date_locked_until, date_created, date_updated, version
+	FROM
+		users`
+
+	wc, orderByClause, err := keysetClause(cur, data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf, wc...); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbUsrs []dbUser
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	usrs, err := toCoreUserSlice(dbUsrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return usrs, nil
+}
+
 // Count returns the total number of users in the DB.
 func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error) {
 	data := map[string]interface{}{}
@@ -162,7 +291,9 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 		users`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, err
+	}
 
 	var count struct {
 		Count int `db:"count"`
@@ -176,22 +307,26 @@ func (s *Store) Count(ctx context.Context, filter user.QueryFilter) (int, error)
 
 // QueryByID gets the specified user from the database.
 func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, error) {
-	data := struct {
-		ID string `db:"user_id"`
-	}{
-		ID: userID.String(),
+	data := map[string]interface{}{
+		"user_id": userID.String(),
 	}
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, enabled, department, date_created, date_updated
+        user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, failed_logins, date_locked_until, date_created, date_updated, version
 	FROM
-		users
-	WHERE 
-		user_id = :user_id`
+		users`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = :user_id")
+	if err != nil {
+		return user.User{}, err
+	}
+
+	buf.WriteString(clause)
 
 	var dbUsr dbUser
-	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbUsr); err != nil {
 		if errors.Is(err, db.ErrDBNotFound) {
 			return user.User{}, fmt.Errorf("namedquerystruct: %w", user.ErrNotFound)
 		}
@@ -208,22 +343,26 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, err
 
 // QueryByEmail gets the specified user from the database by email.
 func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User, error) {
-	data := struct {
-		Email string `db:"email"`
-	}{
-		Email: email.Address,
+	data := map[string]interface{}{
+		"email": email.Address,
 	}
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, enabled, department, date_created, date_updated
+        user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, failed_logins, date_locked_until, date_created, date_updated, version
 	FROM
-		users
-	WHERE
-		email = :email`
+		users`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "email = :email")
+	if err != nil {
+		return user.User{}, err
+	}
+
+	buf.WriteString(clause)
 
 	var dbUsr dbUser
-	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbUsr); err != nil {
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &dbUsr); err != nil {
 		if errors.Is(err, db.ErrDBNotFound) {
 			return user.User{}, fmt.Errorf("namedquerystruct: %w", user.ErrNotFound)
 		}
@@ -245,25 +384,26 @@ func (s *Store) QueryByIDs(ctx context.Context, userIDs []uuid.UUID) ([]user.Use
 		ids[i] = userID.String()
 	}
 
-	data := struct {
-		UserID interface {
-			driver.Valuer
-			sql.Scanner
-		} `db:"user_id"`
-	}{
-		UserID: dbarray.Array(ids),
+	data := map[string]interface{}{
+		"user_id": dbarray.Array(ids),
 	}
 
 	const q = `
 	SELECT
-        user_id, name, email, password_hash, roles, enabled, department, date_created, date_updated
+        user_id, tenant_id, name, email, email_verified, password_hash, roles, enabled, department, failed_logins, date_locked_until, date_created, date_updated, version
 	FROM
-		users
-	WHERE
-		user_id = ANY(:user_id)`
+		users`
+
+	buf := bytes.NewBufferString(q)
+	clause, err := where(ctx, data, "user_id = ANY(:user_id)")
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(clause)
 
 	var dbUsrs []dbUser
-	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbUsrs); err != nil {
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbUsrs); err != nil {
 		if errors.Is(err, db.ErrDBNotFound) {
 			return nil, user.ErrNotFound
 		}
@@ -10,8 +10,11 @@ import (
 	"net/mail"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/business/data/transaction"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/google/uuid"
@@ -23,8 +26,62 @@ var (
 	ErrNotFound              = errors.New("user not found")
 	ErrUniqueEmail           = errors.New("email is not unique")
 	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrAccountLocked         = errors.New("account locked")
+
+	// ErrConcurrentModification is returned when the user was changed by
+	// someone else since it was read.
+	ErrConcurrentModification = errors.New("user was modified concurrently")
 )
 
+// LockedError is returned when a user is locked out of logging in. It
+// matches ErrAccountLocked and carries the time the lockout ends.
+type LockedError struct {
+	Until time.Time
+}
+
+// Error implements the error interface.
+func (le *LockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrAccountLocked, le.Until.Format(time.RFC3339))
+}
+
+// Unwrap allows errors.Is to match the error with ErrAccountLocked.
+func (le *LockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// LockoutConfig defines how failed logins lock a user out. Once Threshold
+// consecutive failures are reached the user is locked for BaseDelay, and the
+// lockout doubles with every further failure up to MaxDelay.
+type LockoutConfig struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultLockout is the lockout used when none is configured.
+var DefaultLockout = LockoutConfig{
+	Threshold: 5,
+	BaseDelay: time.Minute,
+	MaxDelay:  time.Hour,
+}
+
+// WithLockout sets the lockout used by Authenticate. Zero fields fall back to
+// the values of DefaultLockout.
+func WithLockout(cfg LockoutConfig) func(c *Core) {
+	return func(c *Core) {
+		if cfg.Threshold <= 0 {
+			cfg.Threshold = DefaultLockout.Threshold
+		}
+		if cfg.BaseDelay <= 0 {
+			cfg.BaseDelay = DefaultLockout.BaseDelay
+		}
+		if cfg.MaxDelay <= 0 {
+			cfg.MaxDelay = DefaultLockout.MaxDelay
+		}
+		c.lockout = cfg
+	}
+}
+
 // Storer interface defines methods to interact with the data layer for user operations.
 type Storer interface {
 	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
@@ -32,26 +89,40 @@ type Storer interface {
 	Update(ctx context.Context, usr User) error
 	Delete(ctx context.Context, usr User) error
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]User, error)
+	QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]User, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, userID uuid.UUID) (User, error)
 	QueryByIDs(ctx context.Context, userID []uuid.UUID) ([]User, error)
 	QueryByEmail(ctx context.Context, email mail.Address) (User, error)
+	UpdateLogin(ctx context.Context, usr User) error
+	AddFailedLogin(ctx context.Context, usr User) (int, error)
+	LockLogin(ctx context.Context, usr User) error
 }
 
 // Core manages user-related operations and business logic.
 type Core struct {
 	storer  Storer
 	evnCore *event.Core
+	audCore *audit.Core
 	log     *logger.Logger
+	lockout LockoutConfig
 }
 
 // NewCore constructs a core for user API access.
-func NewCore(log *logger.Logger, evnCore *event.Core, storer Storer) *Core {
-	return &Core{
+func NewCore(log *logger.Logger, evnCore *event.Core, audCore *audit.Core, storer Storer, options ...func(c *Core)) *Core {
+	c := Core{
 		storer:  storer,
 		evnCore: evnCore,
+		audCore: audCore,
 		log:     log,
+		lockout: DefaultLockout,
+	}
+
+	for _, option := range options {
+		option(&c)
 	}
+
+	return &c
 }
 
 // ExecuteUnderTransaction constructs a new Core value that will use the
@@ -62,10 +133,22 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 		return nil, err
 	}
 
+	evnCore, err := c.evnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	audCore, err := c.audCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	c = &Core{
 		storer:  trS,
-		evnCore: c.evnCore,
+		evnCore: evnCore,
+		audCore: audCore,
 		log:     c.log,
+		lockout: c.lockout,
 	}
 
 	return c, nil
@@ -78,10 +161,26 @@ func (c *Core) Create(ctx context.Context, nu NewUser) (User, error) {
 		return User{}, fmt.Errorf("generatefrompassword: %w", err)
 	}
 
+	// Users are created in the tenant of the context unless one is specified,
+	// or in the default tenant when the context is marked as unscoped.
+	tenantID := nu.TenantID
+	if tenantID == uuid.Nil {
+		id, scoped, err := tenant.Get(ctx)
+		if err != nil {
+			return User{}, err
+		}
+
+		tenantID = tenant.Default
+		if scoped {
+			tenantID = id
+		}
+	}
+
 	now := time.Now()
 
 	usr := User{
 		ID:           uuid.New(),
+		TenantID:     tenantID,
 		Name:         nu.Name,
 		Email:        nu.Email,
 		PasswordHash: hash,
@@ -90,22 +189,40 @@ func (c *Core) Create(ctx context.Context, nu NewUser) (User, error) {
 		Enabled:      true,
 		DateCreated:  now,
 		DateUpdated:  now,
+		Version:      1,
 	}
 
 	if err := c.storer.Create(ctx, usr); err != nil {
 		return User{}, fmt.Errorf("create: %w", err)
 	}
 
+	ne := audit.NewEntry{
+		TenantID: usr.TenantID,
+		Entity:   AuditEntity,
+		EntityID: usr.ID,
+		Action:   audit.ActionCreate,
+		After:    toAuditUser(usr),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return User{}, fmt.Errorf("record: %w", err)
+	}
+
 	return usr, nil
 }
 
 // Update modifies information about a user.
 func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error) {
+	before := usr
+
 	if uu.Name != nil {
 		usr.Name = *uu.Name
 	}
 
 	if uu.Email != nil {
+		if uu.Email.Address != usr.Email.Address {
+			usr.EmailVerified = false
+		}
 		usr.Email = *uu.Email
 	}
 
@@ -129,6 +246,7 @@ func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error
 		usr.Enabled = *uu.Enabled
 	}
 	usr.DateUpdated = time.Now()
+	usr.Version++
 
 	if err := c.storer.Update(ctx, usr); err != nil {
 		return User{}, fmt.Errorf("update: %w", err)
@@ -138,6 +256,29 @@ func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error
 		return User{}, fmt.Errorf("failed to send a `%s` event: %w", EventUpdated, err)
 	}
 
+	if err := c.recordUpdate(ctx, before, usr); err != nil {
+		return User{}, fmt.Errorf("record: %w", err)
+	}
+
+	return usr, nil
+}
+
+// VerifyEmail marks the current email of the user as verified.
+func (c *Core) VerifyEmail(ctx context.Context, usr User) (User, error) {
+	before := usr
+
+	usr.EmailVerified = true
+	usr.DateUpdated = time.Now()
+	usr.Version++
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := c.recordUpdate(ctx, before, usr); err != nil {
+		return User{}, fmt.Errorf("record: %w", err)
+	}
+
 	return usr, nil
 }
 
@@ -147,6 +288,18 @@ func (c *Core) Delete(ctx context.Context, usr User) error {
 		return fmt.Errorf("delete: %w", err)
 	}
 
+	ne := audit.NewEntry{
+		TenantID: usr.TenantID,
+		Entity:   AuditEntity,
+		EntityID: usr.ID,
+		Action:   audit.ActionDelete,
+		Before:   toAuditUser(usr),
+	}
+
+	if err := c.audCore.Record(ctx, ne); err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+
 	return nil
 }
 
@@ -160,6 +313,21 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 	return users, nil
 }
 
+// QueryByCursor retrieves a page of existing users past the key of the
+// cursor, along with the cursors to the pages around it.
+func (c *Core) QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]User, page.Cursors, error) {
+	users, err := c.storer.QueryByCursor(ctx, filter, cur, rowsPerPage+1)
+	if err != nil {
+		return nil, page.Cursors{}, fmt.Errorf("querybycursor: %w", err)
+	}
+
+	users, cursors := page.Keyset(users, cur, rowsPerPage, func(usr User) page.Key {
+		return cursorKey(usr, cur.OrderBy.Field)
+	})
+
+	return users, cursors, nil
+}
+
 // QueryByID finds the user by the specified ID.
 func (c *Core) QueryByID(ctx context.Context, userID uuid.UUID) (User, error) {
 	user, err := c.storer.QueryByID(ctx, userID)
@@ -198,15 +366,89 @@ func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 // Authenticate finds a user by their email and verifies their password. On
 // success it returns a Claims User representing this user. The claims can be
 // used to generate a token for future authentication.
+//
+// Failed attempts are counted on the user by the store, so concurrent
+// failures are all counted, and lock the user out once the lockout threshold
+// is reached. A locked out user is rejected with a
+// LockedError before the password is compared. The failures are written
+// straight to the store, so this must not run in a transaction that is rolled
+// back on error.
 func (c *Core) Authenticate(ctx context.Context, email mail.Address, password string) (User, error) {
 	usr, err := c.QueryByEmail(ctx, email)
 	if err != nil {
 		return User{}, fmt.Errorf("query: email[%s]: %w", email, err)
 	}
 
+	now := time.Now()
+
+	if usr.Locked(now) {
+		return User{}, fmt.Errorf("locked: email[%s]: %w", email, &LockedError{Until: usr.LockedUntil})
+	}
+
 	if err := bcrypt.CompareHashAndPassword(usr.PasswordHash, []byte(password)); err != nil {
+		failures, err := c.storer.AddFailedLogin(ctx, usr)
+		if err != nil {
+			return User{}, fmt.Errorf("addfailedlogin: email[%s]: %w", email, err)
+		}
+
+		usr.FailedLogins = failures
+
+		if failures >= c.lockout.Threshold {
+			usr.LockedUntil = now.Add(c.lockoutDelay(failures))
+
+			if err := c.storer.LockLogin(ctx, usr); err != nil {
+				return User{}, fmt.Errorf("locklogin: email[%s]: %w", email, err)
+			}
+		}
+
+		if usr.Locked(now) {
+			return User{}, fmt.Errorf("locked: email[%s]: %w", email, &LockedError{Until: usr.LockedUntil})
+		}
+
 		return User{}, fmt.Errorf("comparehashandpassword: %w", ErrAuthenticationFailure)
 	}
 
+	if usr.FailedLogins != 0 || !usr.LockedUntil.IsZero() {
+		usr.FailedLogins = 0
+		usr.LockedUntil = time.Time{}
+
+		if err := c.storer.UpdateLogin(ctx, usr); err != nil {
+			return User{}, fmt.Errorf("updatelogin: email[%s]: %w", email, err)
+		}
+	}
+
+	return usr, nil
+}
+
+// Unlock clears the failed logins of the user and lifts any lockout.
+func (c *Core) Unlock(ctx context.Context, usr User) (User, error) {
+	before := usr
+
+	usr.FailedLogins = 0
+	usr.LockedUntil = time.Time{}
+
+	if err := c.storer.UpdateLogin(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("updatelogin: userID[%s]: %w", usr.ID, err)
+	}
+
+	if err := c.recordUpdate(ctx, before, usr); err != nil {
+		return User{}, fmt.Errorf("record: %w", err)
+	}
+
 	return usr, nil
 }
+
+// lockoutDelay returns how long the user is locked out for after the
+// specified number of failed logins.
+func (c *Core) lockoutDelay(failures int) time.Duration {
+	delay := c.lockout.BaseDelay
+	for i := c.lockout.Threshold; i < failures && delay < c.lockout.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > c.lockout.MaxDelay {
+		return c.lockout.MaxDelay
+	}
+
+	return delay
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
@@ -33,6 +34,7 @@ func TestMain(m *testing.M) {
 func Test_User(t *testing.T) {
 	t.Run("crud", crud)
 	t.Run("paging", paging)
+	t.Run("lockout", lockout)
 }
 
 // =============================================================================
@@ -60,7 +62,7 @@ func crud(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	t.Log("Go seeding ...")
@@ -167,7 +169,7 @@ func paging(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	// -------------------------------------------------------------------------
@@ -224,3 +226,61 @@ func paging(t *testing.T) {
 		t.Errorf("Should have different users")
 	}
 }
+
+func lockout(t *testing.T) {
+	test := dbtest.NewTest(t, c)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
+	defer cancel()
+
+	email := mail.Address{Address: "user@example.com"}
+
+	for i := 1; i < user.DefaultLockout.Threshold; i++ {
+		if _, err := api.User.Authenticate(ctx, email, "wrong-password"); !errors.Is(err, user.ErrAuthenticationFailure) {
+			t.Fatalf("Should fail authentication before the threshold : %v", err)
+		}
+	}
+
+	_, err := api.User.Authenticate(ctx, email, "wrong-password")
+
+	var le *user.LockedError
+	if !errors.As(err, &le) || !errors.Is(err, user.ErrAccountLocked) {
+		t.Fatalf("Should be locked out once the threshold is reached : %v", err)
+	}
+
+	if d := time.Until(le.Until); d <= 0 || d > user.DefaultLockout.BaseDelay {
+		t.Fatalf("Should be locked out for the base delay : %s", d)
+	}
+
+	if _, err := api.User.Authenticate(ctx, email, "gophers"); !errors.Is(err, user.ErrAccountLocked) {
+		t.Fatalf("Should NOT be able to authenticate while locked out : %v", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	usr, err := api.User.QueryByEmail(ctx, email)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by email : %s", err)
+	}
+
+	if usr.FailedLogins != user.DefaultLockout.Threshold {
+		t.Fatalf("Should persist the failed logins : got %d, exp %d", usr.FailedLogins, user.DefaultLockout.Threshold)
+	}
+
+	if _, err := api.User.Unlock(ctx, usr); err != nil {
+		t.Fatalf("Should be able to unlock the user : %s", err)
+	}
+
+	if _, err := api.User.Authenticate(ctx, email, "gophers"); err != nil {
+		t.Fatalf("Should be able to authenticate after the unlock : %s", err)
+	}
+}
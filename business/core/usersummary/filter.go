@@ -3,6 +3,7 @@ package usersummary
 import (
 	"fmt"
 
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 	"github.com/google/uuid"
 )
@@ -11,6 +12,10 @@ import (
 type QueryFilter struct {
 	UserID   *uuid.UUID `validate:"omitempty,uuid4"`
 	UserName *string    `validate:"omitempty,min=3"`
+	UserIDs  []uuid.UUID
+
+	TotalCountRange query.Range[int]
+	TotalCostRange  query.Range[float64]
 }
 
 // Validate checks the data in the model is considered clean.
@@ -30,3 +35,18 @@ func (qf *QueryFilter) WithUserID(userID uuid.UUID) {
 func (qf *QueryFilter) WithUserName(userName string) {
 	qf.UserName = &userName
 }
+
+// WithUserIDs sets the UserIDs field of the QueryFilter value.
+func (qf *QueryFilter) WithUserIDs(userIDs []uuid.UUID) {
+	qf.UserIDs = userIDs
+}
+
+// WithTotalCountRange sets the TotalCountRange field of the QueryFilter value.
+func (qf *QueryFilter) WithTotalCountRange(totalCount query.Range[int]) {
+	qf.TotalCountRange = totalCount
+}
+
+// WithTotalCostRange sets the TotalCostRange field of the QueryFilter value.
+func (qf *QueryFilter) WithTotalCostRange(totalCost query.Range[float64]) {
+	qf.TotalCostRange = totalCost
+}
@@ -1,6 +1,9 @@
 package usersummary
 
-import "github.com/diegomagalhaes-dev/go-service/business/data/order"
+import (
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
+)
 
 // DefaultOrderBy represents the default way we sort.
 var DefaultOrderBy = order.NewBy(OrderByUserID, order.ASC)
@@ -11,3 +14,19 @@ const (
 	OrderByUserID   = "user_id"
 	OrderByUserName = "user_name"
 )
+
+// cursorKey returns the key of the summary within the ordering by the field.
+func cursorKey(smm Summary, field string) page.Key {
+	key := page.Key{
+		ID: smm.UserID.String(),
+	}
+
+	switch field {
+	case OrderByUserID:
+		key.Value = smm.UserID.String()
+	case OrderByUserName:
+		key.Value = smm.UserName
+	}
+
+	return key
+}
@@ -2,15 +2,16 @@ package usersummarydb
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
+	"github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx/dbarray"
 )
 
-func (s *Store) applyFilter(filter usersummary.QueryFilter, data map[string]interface{}, buf *bytes.Buffer) {
-	var wc []string
-
+func (s *Store) applyFilter(ctx context.Context, filter usersummary.QueryFilter, data map[string]interface{}, buf *bytes.Buffer, wc ...string) error {
 	if filter.UserID != nil {
 		data["user_id"] = *filter.UserID
 		wc = append(wc, "user_id = :user_id")
@@ -18,11 +19,36 @@ func (s *Store) applyFilter(filter usersummary.QueryFilter, data map[string]inte
 
 	if filter.UserName != nil {
 		data["user_name"] = fmt.Sprintf("%%%s%%", *filter.UserName)
-		wc = append(wc, "user_name LIKE :user_name")
+		wc = append(wc, "user_name ILIKE :user_name")
+	}
+
+	if len(filter.UserIDs) > 0 {
+		ids := make([]string, len(filter.UserIDs))
+		for i, id := range filter.UserIDs {
+			ids[i] = id.String()
+		}
+
+		data["user_ids"] = dbarray.Array(ids)
+		wc = append(wc, "user_id = ANY(:user_ids)")
+	}
+
+	wc = append(wc, filter.TotalCountRange.Where("total_count", "total_count", data)...)
+	wc = append(wc, filter.TotalCostRange.Where("total_cost", "total_cost", data)...)
+
+	tenantID, scoped, err := tenant.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if scoped {
+		data["tenant_id"] = tenantID.String()
+		wc = append(wc, "tenant_id = :tenant_id")
 	}
 
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
 		buf.WriteString(strings.Join(wc, " AND "))
 	}
+
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
 	db "github.com/diegomagalhaes-dev/go-service/business/data/dbsql/pgx"
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/jmoiron/sqlx"
 )
@@ -36,12 +37,14 @@ func (s *Store) Query(ctx context.Context, filter usersummary.QueryFilter, order
 
 	const q = `
 	SELECT
-		*
+		user_id, user_name, total_count, total_cost
 	FROM
 		user_summary`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return nil, err
+	}
 
 	orderByClause, err := orderByClause(orderBy)
 	if err != nil {
@@ -59,6 +62,40 @@ func (s *Store) Query(ctx context.Context, filter usersummary.QueryFilter, order
 	return toCoreSummarySlice(dbSmm), nil
 }
 
+// QueryByCursor gets the Summaries from the database past the key of the
+// cursor, in the order the cursor reads them.
+func (s *Store) QueryByCursor(ctx context.Context, filter usersummary.QueryFilter, cur page.Cursor, rowsPerPage int) ([]usersummary.Summary, error) {
+	data := map[string]interface{}{
+		"rows_per_page": rowsPerPage,
+	}
+
+	const q = `
+	SELECT
+		user_id, user_name, total_count, total_cost
+	FROM
+		user_summary`
+
+	wc, orderByClause, err := keysetClause(cur, data)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(q)
+	if err := s.applyFilter(ctx, filter, data, buf, wc...); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbSmm []dbSummary
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbSmm); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreSummarySlice(dbSmm), nil
+}
+
 // Count returns the total number of users in the DB.
 func (s *Store) Count(ctx context.Context, filter usersummary.QueryFilter) (int, error) {
 	data := map[string]interface{}{}
@@ -70,7 +107,9 @@ func (s *Store) Count(ctx context.Context, filter usersummary.QueryFilter) (int,
 		user_summary`
 
 	buf := bytes.NewBufferString(q)
-	s.applyFilter(filter, data, buf)
+	if err := s.applyFilter(ctx, filter, data, buf); err != nil {
+		return 0, err
+	}
 
 	var count struct {
 		Count int `db:"count"`
@@ -7,12 +7,14 @@ import (
 	"fmt"
 
 	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 )
 
 // Storer interface declares the behavior this package needs to perists and
 // retrieve data.
 type Storer interface {
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, pageNumber int, rowsPerPage int) ([]Summary, error)
+	QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]Summary, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 }
 
@@ -40,6 +42,21 @@ func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By,
 	return users, nil
 }
 
+// QueryByCursor retrieves a page of existing summaries past the key of the
+// cursor, along with the cursors to the pages around it.
+func (c *Core) QueryByCursor(ctx context.Context, filter QueryFilter, cur page.Cursor, rowsPerPage int) ([]Summary, page.Cursors, error) {
+	smms, err := c.storer.QueryByCursor(ctx, filter, cur, rowsPerPage+1)
+	if err != nil {
+		return nil, page.Cursors{}, fmt.Errorf("querybycursor: %w", err)
+	}
+
+	smms, cursors := page.Keyset(smms, cur, rowsPerPage, func(smm Summary) page.Key {
+		return cursorKey(smm, cur.OrderBy.Field)
+	})
+
+	return smms, cursors, nil
+}
+
 // Count returns the total number of users in the store.
 func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
 	return c.storer.Count(ctx, filter)
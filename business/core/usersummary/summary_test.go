@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
 	"github.com/diegomagalhaes-dev/go-service/business/data/dbtest"
@@ -72,7 +73,7 @@ func paging(t *testing.T) {
 
 	api := test.CoreAPIs
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(tenant.Unscoped(context.Background()), 10*time.Second)
 	defer cancel()
 
 	t.Log("Go seeding ...")
@@ -43,4 +43,334 @@ FROM
 JOIN
     products AS p ON p.user_id = u.user_id
 GROUP BY
-    u.user_id
\ No newline at end of file
+    u.user_id
+
+-- Version: 1.04
+-- Description: Create table orders
+CREATE TABLE orders (
+	order_id     UUID           NOT NULL,
+	user_id      UUID           NOT NULL,
+	status       TEXT           NOT NULL,
+	total        NUMERIC(10, 2) NOT NULL,
+	date_created TIMESTAMP      NOT NULL,
+	date_updated TIMESTAMP      NOT NULL,
+
+	PRIMARY KEY (order_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Version: 1.05
+-- Description: Create table order_items
+CREATE TABLE order_items (
+	order_id   UUID           NOT NULL,
+	product_id UUID           NOT NULL,
+	quantity   INT            NOT NULL,
+	cost       NUMERIC(10, 2) NOT NULL,
+
+	PRIMARY KEY (order_id, product_id),
+	FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE
+);
+
+-- Version: 1.06
+-- Description: Create table event_outbox
+CREATE TABLE event_outbox (
+	event_id       UUID      NOT NULL,
+	source         TEXT      NOT NULL,
+	type           TEXT      NOT NULL,
+	raw_params     BYTEA     NULL,
+	status         TEXT      NOT NULL,
+	attempts       INT       NOT NULL DEFAULT 0,
+	last_error     TEXT      NULL,
+	date_created   TIMESTAMP NOT NULL,
+	next_attempt   TIMESTAMP NOT NULL,
+	date_delivered TIMESTAMP NULL,
+
+	PRIMARY KEY (event_id)
+);
+
+CREATE INDEX event_outbox_status_next_attempt_idx ON event_outbox (status, next_attempt);
+
+-- Version: 1.07
+-- Description: Add active status to products
+ALTER TABLE products ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Version: 1.08
+-- Description: Create table refresh_tokens
+CREATE TABLE refresh_tokens (
+	token_id     UUID      NOT NULL,
+	family_id    UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	token_hash   TEXT      UNIQUE NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+	date_revoked TIMESTAMP NULL,
+
+	PRIMARY KEY (token_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+
+-- Version: 1.09
+-- Description: Create table revoked_tokens
+CREATE TABLE revoked_tokens (
+	jti          TEXT      NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (jti)
+);
+
+-- Version: 1.10
+-- Description: Create table api_keys
+CREATE TABLE api_keys (
+	key_id         UUID      NOT NULL,
+	user_id        UUID      NOT NULL,
+	name           TEXT      NOT NULL,
+	prefix         TEXT      UNIQUE NOT NULL,
+	key_hash       TEXT      NOT NULL,
+	roles          TEXT[]    NOT NULL,
+	date_created   TIMESTAMP NOT NULL,
+	date_expires   TIMESTAMP NULL,
+	date_last_used TIMESTAMP NULL,
+	date_revoked   TIMESTAMP NULL,
+
+	PRIMARY KEY (key_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
+
+-- Version: 1.11
+-- Description: Add login lockout state to users
+ALTER TABLE users
+	ADD COLUMN failed_logins     INT       NOT NULL DEFAULT 0,
+	ADD COLUMN date_locked_until TIMESTAMP NULL;
+
+-- Version: 1.12
+-- Description: Create tables for multi-factor authentication
+CREATE TABLE mfa_enrollments (
+	user_id        UUID      NOT NULL,
+	secret         TEXT      NOT NULL,
+	last_step      BIGINT    NOT NULL DEFAULT 0,
+	date_created   TIMESTAMP NOT NULL,
+	date_confirmed TIMESTAMP NULL,
+
+	PRIMARY KEY (user_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mfa_recovery_codes (
+	code_id      UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	code_hash    TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_used    TIMESTAMP NULL,
+
+	PRIMARY KEY (code_id),
+	UNIQUE (user_id, code_hash),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mfa_challenges (
+	challenge_id   UUID      NOT NULL,
+	user_id        UUID      NOT NULL,
+	challenge_hash TEXT      UNIQUE NOT NULL,
+	attempts       INT       NOT NULL DEFAULT 0,
+	date_created   TIMESTAMP NOT NULL,
+	date_expires   TIMESTAMP NOT NULL,
+	date_used      TIMESTAMP NULL,
+
+	PRIMARY KEY (challenge_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+ALTER TABLE refresh_tokens ADD COLUMN amr TEXT[] NOT NULL DEFAULT '{}';
+
+-- Version: 1.13
+-- Description: Create table user_tokens and add email verification to users
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE user_tokens (
+	token_id     UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	email        TEXT      NOT NULL,
+	purpose      TEXT      NOT NULL,
+	token_hash   TEXT      UNIQUE NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+	date_used    TIMESTAMP NULL,
+
+	PRIMARY KEY (token_id),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_tokens_user_id_idx ON user_tokens (user_id);
+
+-- Version: 1.14
+-- Description: Create tables user_identities and oidc_logins
+CREATE TABLE user_identities (
+	identity_id  UUID      NOT NULL,
+	user_id      UUID      NOT NULL,
+	issuer       TEXT      NOT NULL,
+	subject      TEXT      NOT NULL,
+	email        TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (identity_id),
+	UNIQUE (issuer, subject),
+	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE oidc_logins (
+	state_hash   TEXT      NOT NULL,
+	nonce        TEXT      NOT NULL,
+	verifier     TEXT      NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+	date_expires TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (state_hash)
+);
+
+-- Version: 1.15
+-- Description: Create table roles
+CREATE TABLE roles (
+	name         TEXT      NOT NULL,
+	description  TEXT      NOT NULL DEFAULT '',
+	permissions  TEXT[]    NOT NULL DEFAULT '{}',
+	date_created TIMESTAMP NOT NULL,
+	date_updated TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (name)
+);
+
+INSERT INTO roles (name, description, permissions, date_created, date_updated) VALUES
+	('ADMIN', 'Administrators of the system', '{*}', NOW() AT TIME ZONE 'UTC', NOW() AT TIME ZONE 'UTC'),
+	('USER', 'Users of the system', '{product:read,product:write,order:read,user:read}', NOW() AT TIME ZONE 'UTC', NOW() AT TIME ZONE 'UTC');
+
+-- Version: 1.16
+-- Description: Create table tenants and scope users and products by tenant
+CREATE TABLE tenants (
+	tenant_id    UUID        NOT NULL,
+	name         TEXT UNIQUE NOT NULL,
+	date_created TIMESTAMP   NOT NULL,
+	date_updated TIMESTAMP   NOT NULL,
+
+	PRIMARY KEY (tenant_id)
+);
+
+INSERT INTO tenants (tenant_id, name, date_created, date_updated) VALUES
+	('f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21', 'Default', NOW() AT TIME ZONE 'UTC', NOW() AT TIME ZONE 'UTC');
+
+ALTER TABLE users ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+ALTER TABLE products ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+
+CREATE INDEX users_tenant_id_idx ON users (tenant_id);
+CREATE INDEX products_tenant_id_idx ON products (tenant_id);
+
+CREATE OR REPLACE VIEW user_summary AS
+SELECT
+	u.user_id   AS user_id,
+	u.name      AS user_name,
+	COUNT(p.*)  AS total_count,
+	SUM(p.cost) AS total_cost,
+	u.tenant_id AS tenant_id
+FROM
+	users AS u
+JOIN
+	products AS p ON p.user_id = u.user_id
+GROUP BY
+	u.user_id;
+
+-- Version: 1.17
+-- Description: Create table audit_log
+CREATE TABLE audit_log (
+	audit_id     UUID      NOT NULL,
+	tenant_id    UUID      NOT NULL,
+	actor        TEXT      NOT NULL,
+	trace_id     TEXT      NOT NULL,
+	entity       TEXT      NOT NULL,
+	entity_id    UUID      NOT NULL,
+	action       TEXT      NOT NULL,
+	diff         JSONB     NOT NULL,
+	date_created TIMESTAMP NOT NULL,
+
+	PRIMARY KEY (audit_id)
+);
+
+CREATE INDEX audit_log_tenant_id_date_created_idx ON audit_log (tenant_id, date_created);
+CREATE INDEX audit_log_entity_id_idx ON audit_log (entity_id);
+
+-- Version: 1.18
+-- Description: Add a version to users and products for optimistic concurrency
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE products ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- Version: 1.19
+-- Description: Add full text and trigram search to products
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE products ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', name)) STORED;
+
+CREATE INDEX products_search_vector_idx ON products USING GIN (search_vector);
+CREATE INDEX products_name_trgm_idx ON products USING GIN (name gin_trgm_ops);
+
+-- Version: 1.20
+-- Description: Scope orders, order items, api keys and refresh tokens by tenant
+ALTER TABLE orders ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+ALTER TABLE order_items ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+ALTER TABLE api_keys ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+ALTER TABLE refresh_tokens ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+
+UPDATE orders AS o SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = o.user_id;
+UPDATE order_items AS i SET tenant_id = o.tenant_id FROM orders AS o WHERE o.order_id = i.order_id;
+UPDATE api_keys AS k SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = k.user_id;
+UPDATE refresh_tokens AS t SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = t.user_id;
+
+ALTER TABLE orders ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE order_items ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE refresh_tokens ALTER COLUMN tenant_id DROP DEFAULT;
+
+CREATE INDEX orders_tenant_id_idx ON orders (tenant_id);
+CREATE INDEX api_keys_tenant_id_idx ON api_keys (tenant_id);
+CREATE INDEX refresh_tokens_tenant_id_idx ON refresh_tokens (tenant_id);
+
+-- Version: 1.21
+-- Description: Scope roles by tenant and grant the platform permissions to the default administrators
+ALTER TABLE roles ADD COLUMN tenant_id UUID NOT NULL DEFAULT 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' REFERENCES tenants(tenant_id);
+ALTER TABLE roles ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE roles DROP CONSTRAINT roles_pkey;
+ALTER TABLE roles ADD PRIMARY KEY (tenant_id, name);
+
+INSERT INTO roles (tenant_id, name, description, permissions, date_created, date_updated)
+	SELECT t.tenant_id, r.name, r.description, r.permissions, r.date_created, r.date_updated
+	FROM tenants AS t CROSS JOIN roles AS r
+	WHERE t.tenant_id <> 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21';
+
+UPDATE roles SET permissions = array_append(permissions, 'platform:*')
+	WHERE tenant_id = 'f0e81b1a-3b7e-4c1d-8a9e-2d6c5b4a3f21' AND name = 'ADMIN';
+
+-- Version: 1.22
+-- Description: Count the failed second factor attempts of users across challenges
+ALTER TABLE mfa_enrollments
+	ADD COLUMN failed_attempts  INT       NOT NULL DEFAULT 0,
+	ADD COLUMN date_last_failed TIMESTAMP NULL;
+
+-- Version: 1.23
+-- Description: Add tenant_id to the mfa tables
+ALTER TABLE mfa_enrollments ADD COLUMN tenant_id UUID NULL REFERENCES tenants(tenant_id);
+UPDATE mfa_enrollments AS m SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = m.user_id;
+ALTER TABLE mfa_enrollments ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE mfa_recovery_codes ADD COLUMN tenant_id UUID NULL REFERENCES tenants(tenant_id);
+UPDATE mfa_recovery_codes AS m SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = m.user_id;
+ALTER TABLE mfa_recovery_codes ALTER COLUMN tenant_id SET NOT NULL;
+
+ALTER TABLE mfa_challenges ADD COLUMN tenant_id UUID NULL REFERENCES tenants(tenant_id);
+UPDATE mfa_challenges AS m SET tenant_id = u.tenant_id FROM users AS u WHERE u.user_id = m.user_id;
+ALTER TABLE mfa_challenges ALTER COLUMN tenant_id SET NOT NULL;
+
+-- Version: 1.24
+-- Description: Grant the write permissions of orders and users to the USER roles
+UPDATE roles SET permissions = permissions || '{order:write,user:write}'
+	WHERE name = 'USER';
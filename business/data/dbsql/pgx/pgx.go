@@ -4,6 +4,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"net/url"
@@ -115,30 +116,48 @@ func ExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, qu
 // NamedExecContext is a helper function to execute a CUD operation with
 // logging and tracing where field replacement is necessary.
 func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) error {
+	_, err := namedExecContext(ctx, log, db, query, data)
+	return err
+}
+
+// NamedExecContextWithCount is a helper function to execute a CUD operation
+// with logging and tracing where field replacement is necessary. It returns
+// the number of rows affected by the operation.
+func NamedExecContextWithCount(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) (int64, error) {
+	return namedExecContext(ctx, log, db, query, data)
+}
+
+func namedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any) (int64, error) {
 	q := queryString(query, data)
 
 	if _, ok := data.(struct{}); ok {
-		log.Infoc(ctx, 5, "database.NamedExecContext", "query", q)
+		log.Infoc(ctx, 6, "database.NamedExecContext", "query", q)
 	} else {
-		log.Infoc(ctx, 4, "database.NamedExecContext", "query", q)
+		log.Infoc(ctx, 5, "database.NamedExecContext", "query", q)
 	}
 
 	ctx, span := web.AddSpan(ctx, "business.sys.database.exec", attribute.String("query", q))
 	defer span.End()
 
-	if _, err := sqlx.NamedExecContext(ctx, db, query, data); err != nil {
+	result, err := sqlx.NamedExecContext(ctx, db, query, data)
+	if err != nil {
 		if pqerr, ok := err.(*pgconn.PgError); ok {
 			switch pqerr.Code {
 			case undefinedTable:
-				return ErrUndefinedTable
+				return 0, ErrUndefinedTable
 			case uniqueViolation:
-				return ErrDBDuplicatedEntry
+				return 0, ErrDBDuplicatedEntry
 			}
 		}
-		return err
+		return 0, err
 	}
 
-	return nil
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
 }
 
 // QuerySlice is a helper function for executing queries that return a
@@ -214,6 +233,86 @@ func namedQuerySlice[T any](ctx context.Context, log *logger.Logger, db sqlx.Ext
 	return nil
 }
 
+// NamedQueryCursor is a helper function for executing queries that return a
+// collection of data too large to be held in a slice where field replacement
+// is necessary. The rows are read through a server-side cursor, fetchSize rows
+// at a time, and handed to fn one by one. A cursor only lives within a
+// transaction, so a db that is not already in one gets a read only
+// transaction for the time of the query.
+func NamedQueryCursor[T any](ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, data any, fetchSize int, fn func(T) error) error {
+	q := queryString(query, data)
+
+	log.Infoc(ctx, 5, "database.NamedQueryCursor", "query", q)
+
+	ctx, span := web.AddSpan(ctx, "business.sys.database.querycursor", attribute.String("query", q))
+	defer span.End()
+
+	if sqlxDB, ok := db.(*sqlx.DB); ok {
+		tx, err := sqlxDB.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		db = tx
+	}
+
+	named, args, err := sqlx.Named(query, data)
+	if err != nil {
+		return err
+	}
+
+	const cursor = "query_cursor"
+
+	if _, err := db.ExecContext(ctx, "DECLARE "+cursor+" NO SCROLL CURSOR FOR "+db.Rebind(named), args...); err != nil {
+		if pqerr, ok := err.(*pgconn.PgError); ok && pqerr.Code == undefinedTable {
+			return ErrUndefinedTable
+		}
+		return err
+	}
+
+	// The cursor is closed even when the request is gone, so a transaction
+	// that carries on does not hold it.
+	defer db.ExecContext(context.WithoutCancel(ctx), "CLOSE "+cursor)
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", fetchSize, cursor)
+
+	for {
+		batch, err := func() ([]T, error) {
+			rows, err := db.QueryxContext(ctx, fetch)
+			if err != nil {
+				return nil, err
+			}
+			defer rows.Close()
+
+			batch := make([]T, 0, fetchSize)
+			for rows.Next() {
+				v := new(T)
+				if err := rows.StructScan(v); err != nil {
+					return nil, err
+				}
+				batch = append(batch, *v)
+			}
+
+			return batch, rows.Err()
+		}()
+
+		if err != nil {
+			return err
+		}
+
+		for _, v := range batch {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < fetchSize {
+			return nil
+		}
+	}
+}
+
 // QueryStruct is a helper function for executing queries that return a
 // single value to be unmarshalled into a struct type where field replacement is necessary.
 func QueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContext, query string, dest any) error {
@@ -284,7 +383,17 @@ func namedQueryStruct(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 	return nil
 }
 
+// Redacted is a parameter whose value is kept out of the logs and traces of
+// the queries, such as a secret.
+type Redacted string
+
+// Value implements the driver.Valuer interface.
+func (r Redacted) Value() (driver.Value, error) {
+	return string(r), nil
+}
+
 // queryString provides a pretty print version of the query and parameters.
+// The value of Redacted parameters is left out.
 func queryString(query string, args any) string {
 	query, params, err := sqlx.Named(query, args)
 	if err != nil {
@@ -294,6 +403,8 @@ func queryString(query string, args any) string {
 	for _, param := range params {
 		var value string
 		switch v := param.(type) {
+		case Redacted:
+			value = "'[REDACTED]'"
 		case string:
 			value = fmt.Sprintf("'%s'", v)
 		case []byte:
@@ -23,7 +23,42 @@ func NewBeginner(sqlxDB *sqlx.DB) transaction.Beginner {
 // Begin start a transaction and returns a value that implements
 // the core transactor interface.
 func (db *dbBeginner) Begin() (transaction.Transaction, error) {
-	return db.sqlxDB.Beginx()
+	tx, err := db.sqlxDB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbTx{Tx: tx}, nil
+}
+
+// dbTx is a transaction running the functions registered with AfterCommit
+// once it has been committed.
+type dbTx struct {
+	*sqlx.Tx
+	hooks []func()
+}
+
+// AfterCommit registers a function to run once the transaction has been
+// committed.
+func (tx *dbTx) AfterCommit(fn func()) {
+	tx.hooks = append(tx.hooks, fn)
+}
+
+// Commit commits the transaction and runs the functions registered with
+// AfterCommit.
+func (tx *dbTx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+
+	hooks := tx.hooks
+	tx.hooks = nil
+
+	for _, fn := range hooks {
+		fn()
+	}
+
+	return nil
 }
 
 // GetExtContext is a helper function that extracts the sqlx value
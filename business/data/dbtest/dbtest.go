@@ -10,9 +10,28 @@ import (
 	"testing"
 	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/account/stores/accountdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey/stores/apikeydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity"
+	"github.com/diegomagalhaes-dev/go-service/business/core/identity/stores/identitydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa"
+	"github.com/diegomagalhaes-dev/go-service/business/core/mfa/stores/mfadb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/order"
+	"github.com/diegomagalhaes-dev/go-service/business/core/order/stores/orderdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product"
 	"github.com/diegomagalhaes-dev/go-service/business/core/product/stores/productdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role/stores/roledb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session/stores/sessiondb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant/stores/tenantdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/usersummary"
@@ -22,8 +41,10 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
 	"github.com/diegomagalhaes-dev/go-service/foundation/docker"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/mailer"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -58,6 +79,7 @@ func StopDB(c *docker.Container) {
 type Test struct {
 	DB       *sqlx.DB
 	Log      *logger.Logger
+	Mailer   *mailer.Memory
 	CoreAPIs CoreAPIs
 	Teardown func()
 	t        *testing.T
@@ -134,7 +156,8 @@ func NewTest(t *testing.T, c *docker.Container) *Test {
 	var buf bytes.Buffer
 	log := logger.New(&buf, logger.LevelInfo, "TEST", func(context.Context) string { return web.GetTraceID(ctx) })
 
-	coreAPIs := newCoreAPIs(log, db)
+	mailer := mailer.NewMemory()
+	coreAPIs := newCoreAPIs(log, db, mailer)
 
 	t.Log("Ready for testing ...")
 
@@ -144,6 +167,7 @@ func NewTest(t *testing.T, c *docker.Container) *Test {
 		Log:       log,
 		DB:        db,
 		KeyLookup: &keyStore{},
+		Issuer:    "service project",
 	}
 	a, err := auth.New(cfg)
 	if err != nil {
@@ -166,6 +190,7 @@ func NewTest(t *testing.T, c *docker.Container) *Test {
 	test := Test{
 		DB:       db,
 		Log:      log,
+		Mailer:   mailer,
 		CoreAPIs: coreAPIs,
 		Teardown: teardown,
 		t:        t,
@@ -186,22 +211,30 @@ func (test *Test) TokenV1(email string, pass string) string {
 	addr, _ := mail.ParseAddress(email)
 
 	store := userdb.NewStore(test.Log, test.DB)
-	dbUsr, err := store.QueryByEmail(context.Background(), *addr)
+	dbUsr, err := store.QueryByEmail(tenant.Unscoped(context.Background()), *addr)
 	if err != nil {
 		return ""
 	}
 
+	perms, err := test.V1.Auth.Permissions(context.Background(), dbUsr.TenantID, dbUsr.Roles)
+	if err != nil {
+		test.t.Fatal(err)
+	}
+
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   dbUsr.ID.String(),
 			Issuer:    "service project",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
 		},
-		Roles: dbUsr.Roles,
+		TenantID:    dbUsr.TenantID.String(),
+		Roles:       dbUsr.Roles,
+		Permissions: perms,
 	}
 
-	token, err := test.V1.Auth.GenerateToken(kid, claims)
+	token, err := test.V1.Auth.GenerateToken(claims)
 	if err != nil {
 		test.t.Fatal(err)
 	}
@@ -232,25 +265,61 @@ func FloatPointer(f float64) *float64 {
 	return &f
 }
 
+// BoolPointer is a helper to get a *bool from a bool. It is in the tests
+// package because we normally don't want to deal with pointers to basic types
+// but it's useful in some tests.
+func BoolPointer(b bool) *bool {
+	return &b
+}
+
 // =============================================================================
 
 // CoreAPIs represents all the core api's needed for testing.
 type CoreAPIs struct {
+	Event       *event.Core
 	User        *user.Core
 	Product     *product.Core
 	UserSummary *usersummary.Core
+	Order       *order.Core
+	Session     *session.Core
+	APIKey      *apikey.Core
+	MFA         *mfa.Core
+	Account     *account.Core
+	Identity    *identity.Core
+	Role        *role.Core
+	Tenant      *tenant.Core
+	Audit       *audit.Core
 }
 
-func newCoreAPIs(log *logger.Logger, db *sqlx.DB) CoreAPIs {
-	evnCore := event.NewCore(log)
-	usrCore := user.NewCore(log, evnCore, userdb.NewStore(log, db))
-	prdCore := product.NewCore(log, evnCore, usrCore, productdb.NewStore(log, db))
+func newCoreAPIs(log *logger.Logger, db *sqlx.DB, mailer account.Mailer) CoreAPIs {
+	evnCore := event.NewCore(log, eventdb.NewStore(log, db))
+	audCore := audit.NewCore(log, auditdb.NewStore(log, db), audit.WithActor(auth.GetSubject), audit.WithTraceID(web.GetTraceID))
+	usrCore := user.NewCore(log, evnCore, audCore, userdb.NewStore(log, db))
+	prdCore := product.NewCore(log, evnCore, audCore, usrCore, productdb.NewStore(log, db))
 	usmCore := usersummary.NewCore(usersummarydb.NewStore(log, db))
+	ordCore := order.NewCore(log, usrCore, prdCore, orderdb.NewStore(log, db))
+	sesCore := session.NewCore(log, sessiondb.NewStore(log, db))
+	apkCore := apikey.NewCore(log, usrCore, apikeydb.NewStore(log, db))
+	mfaCore := mfa.NewCore(log, mfadb.NewStore(log, db))
+	accCore := account.NewCore(log, evnCore, usrCore, sesCore, apkCore, mailer, accountdb.NewStore(log, db))
+	idnCore := identity.NewCore(log, usrCore, identitydb.NewStore(log, db))
+	rolCore := role.NewCore(log, roledb.NewStore(log, db))
+	tntCore := tenant.NewCore(log, tenantdb.NewStore(log, db))
 
 	return CoreAPIs{
+		Event:       evnCore,
 		User:        usrCore,
 		Product:     prdCore,
 		UserSummary: usmCore,
+		Order:       ordCore,
+		Session:     sesCore,
+		APIKey:      apkCore,
+		MFA:         mfaCore,
+		Account:     accCore,
+		Identity:    idnCore,
+		Role:        rolCore,
+		Tenant:      tntCore,
+		Audit:       audCore,
 	}
 }
 
@@ -266,6 +335,14 @@ func (ks *keyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+func (ks *keyStore) KIDs() ([]string, error) {
+	return []string{kid}, nil
+}
+
+func (ks *keyStore) ActiveKID() (string, error) {
+	return kid, nil
+}
+
 // =============================================================================
 
 const (
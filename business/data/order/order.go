@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
@@ -20,9 +21,12 @@ var directions = map[string]string{
 	DESC: "DESC",
 }
 
+// By represents a field used to order by and direction. The fields in Then
+// break the ties left by the ones before them, in order.
 type By struct {
 	Field     string
 	Direction string
+	Then      []By
 }
 
 func NewBy(field string, direction string) By {
@@ -32,13 +36,63 @@ func NewBy(field string, direction string) By {
 	}
 }
 
+// Fields returns the field of the ordering followed by the fields breaking
+// its ties.
+func (b By) Fields() []By {
+	fields := []By{NewBy(b.Field, b.Direction)}
+	return append(fields, b.Then...)
+}
+
+// Map returns the ordering with its fields renamed through the specified
+// fields. A field missing from them is reported as a field error.
+func (b By) Map(fields map[string]string) (By, error) {
+	var by By
+
+	for i, f := range b.Fields() {
+		name, exists := fields[f.Field]
+		if !exists {
+			return By{}, validate.NewFieldsError(f.Field, errors.New("order field does not exist"))
+		}
+
+		if i == 0 {
+			by = NewBy(name, f.Direction)
+			continue
+		}
+		by.Then = append(by.Then, NewBy(name, f.Direction))
+	}
+
+	return by, nil
+}
+
+// Parse parses the ordering of the request in the form field,DIR with a
+// semicolon separating the fields ordering by several of them, as in
+// orderBy=cost,DESC;name,ASC.
 func Parse(r *http.Request, defaultOrder By) (By, error) {
-	v := r.URL.Query().Get("orderBy")
+	v := queryValue(r, "orderBy")
 
 	if v == "" {
 		return defaultOrder, nil
 	}
 
+	var by By
+	for i, field := range strings.Split(v, ";") {
+		f, err := parseField(field)
+		if err != nil {
+			return By{}, validate.NewFieldsError(v, err)
+		}
+
+		if i == 0 {
+			by = f
+			continue
+		}
+		by.Then = append(by.Then, f)
+	}
+
+	return by, nil
+}
+
+// parseField parses a single field of the ordering in the form field,DIR.
+func parseField(v string) (By, error) {
 	orderParts := strings.Split(v, ",")
 
 	var by By
@@ -48,12 +102,38 @@ func Parse(r *http.Request, defaultOrder By) (By, error) {
 	case 2:
 		by = NewBy(strings.Trim(orderParts[0], " "), strings.Trim(orderParts[1], " "))
 	default:
-		return By{}, validate.NewFieldsError(v, errors.New("unknown order field"))
+		return By{}, errors.New("unknown order field")
 	}
 
 	if _, exists := directions[by.Direction]; !exists {
-		return By{}, validate.NewFieldsError(v, fmt.Errorf("unknown direction: %s", by.Direction))
+		return By{}, fmt.Errorf("unknown direction: %s", by.Direction)
 	}
 
 	return by, nil
 }
+
+// queryValue returns the value of the key in the query string. A pair holding
+// an unescaped semicolon is dropped when the query is parsed, so the raw query
+// is searched for the key when the parsed one misses it.
+func queryValue(r *http.Request, key string) string {
+	values := r.URL.Query()
+	if values.Has(key) {
+		return values.Get(key)
+	}
+
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		k, v, _ := strings.Cut(pair, "=")
+		if k != key {
+			continue
+		}
+
+		value, err := url.QueryUnescape(v)
+		if err != nil {
+			return ""
+		}
+
+		return value
+	}
+
+	return ""
+}
@@ -0,0 +1,91 @@
+package order_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Parse(t *testing.T) {
+	def := order.NewBy("id", order.ASC)
+
+	tests := []struct {
+		name string
+		url  string
+		exp  order.By
+		fail bool
+	}{
+		{
+			name: "default",
+			url:  "/products",
+			exp:  def,
+		},
+		{
+			name: "single",
+			url:  "/products?orderBy=cost,DESC",
+			exp:  order.NewBy("cost", order.DESC),
+		},
+		{
+			name: "multiple",
+			url:  "/products?orderBy=cost,DESC%3Bname",
+			exp:  order.By{Field: "cost", Direction: order.DESC, Then: []order.By{order.NewBy("name", order.ASC)}},
+		},
+		{
+			name: "semicolon",
+			url:  "/products?page=1&orderBy=cost,DESC;name,ASC",
+			exp:  order.By{Field: "cost", Direction: order.DESC, Then: []order.By{order.NewBy("name", order.ASC)}},
+		},
+		{
+			name: "direction",
+			url:  "/products?orderBy=cost,DESC;name,UP",
+			fail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := order.Parse(httptest.NewRequest(http.MethodGet, tt.url, nil), def)
+			if tt.fail {
+				if err == nil {
+					t.Fatalf("Should NOT be able to parse the order : %+v", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Should be able to parse the order : %s", err)
+			}
+
+			if diff := cmp.Diff(got, tt.exp); diff != "" {
+				t.Fatalf("Should get the expected order. Diff:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_Map(t *testing.T) {
+	fields := map[string]string{
+		"cost": "product_cost",
+		"name": "product_name",
+	}
+
+	by := order.By{Field: "cost", Direction: order.DESC, Then: []order.By{order.NewBy("name", order.ASC)}}
+
+	got, err := by.Map(fields)
+	if err != nil {
+		t.Fatalf("Should be able to map the order : %s", err)
+	}
+
+	exp := order.By{Field: "product_cost", Direction: order.DESC, Then: []order.By{order.NewBy("product_name", order.ASC)}}
+	if diff := cmp.Diff(got, exp); diff != "" {
+		t.Fatalf("Should get the mapped order. Diff:\n%s", diff)
+	}
+
+	by.Then = append(by.Then, order.NewBy("unknown", order.ASC))
+	if _, err := by.Map(fields); err == nil {
+		t.Fatalf("Should NOT be able to map an unknown field")
+	}
+}
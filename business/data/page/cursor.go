@@ -0,0 +1,217 @@
+package page
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/google/uuid"
+)
+
+// Set of error variables for cursor paging.
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrCursorOrder   = errors.New("order field does not support cursors")
+)
+
+// Key represents the position of a row within an ordering. The value of the
+// ordered field is followed by the id of the row, which breaks the ties
+// between rows sharing the same value.
+type Key struct {
+	Value string
+	ID    string
+}
+
+// Check validates the key holds a value of the specified database type and a
+// uuid for the id. The tokens of cursors aren't signed, so a key has to be
+// checked before the database casts its value, otherwise a tampered token
+// fails the query instead of being rejected.
+func (k Key) Check(typ string) error {
+	if !isUUID(k.ID) {
+		return fmt.Errorf("id: %w", ErrInvalidCursor)
+	}
+
+	var valid bool
+	switch typ {
+	case "UUID":
+		valid = isUUID(k.Value)
+	case "TEXT":
+		valid = utf8.ValidString(k.Value) && !strings.ContainsRune(k.Value, 0)
+	case "INT":
+		_, err := strconv.ParseInt(k.Value, 10, 32)
+		valid = err == nil
+	case "NUMERIC":
+		f, err := strconv.ParseFloat(k.Value, 64)
+		valid = err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	case "BOOLEAN":
+		_, err := strconv.ParseBool(k.Value)
+		valid = err == nil
+	}
+
+	if !valid {
+		return fmt.Errorf("value: %w", ErrInvalidCursor)
+	}
+
+	return nil
+}
+
+// isUUID reports whether the value is a uuid in its canonical form, the one
+// the database accepts.
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil && len(value) == 36
+}
+
+// =============================================================================
+
+// Cursor represents a position within a set of ordered rows. A cursor without
+// a key points at the start of the rows, a backward cursor reads the rows
+// preceding its key.
+type Cursor struct {
+	OrderBy  order.By
+	Key      *Key
+	Backward bool
+}
+
+// cursorToken is the form of a cursor carried by a token.
+type cursorToken struct {
+	Field     string `json:"f"`
+	Direction string `json:"d"`
+	Value     string `json:"v"`
+	ID        string `json:"i"`
+	Backward  bool   `json:"b,omitempty"`
+}
+
+// Encode returns the opaque token of the cursor. The token of a cursor
+// without a key is empty.
+func (c Cursor) Encode() string {
+	if c.Key == nil {
+		return ""
+	}
+
+	tkn := cursorToken{
+		Field:     c.OrderBy.Field,
+		Direction: c.OrderBy.Direction,
+		Value:     c.Key.Value,
+		ID:        c.Key.ID,
+		Backward:  c.Backward,
+	}
+
+	data, err := json.Marshal(tkn)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses the token of a cursor. An empty token is the cursor
+// pointing at the start of the rows.
+func DecodeCursor(token string) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("decode: %w", ErrInvalidCursor)
+	}
+
+	var tkn cursorToken
+	if err := json.Unmarshal(data, &tkn); err != nil {
+		return Cursor{}, fmt.Errorf("unmarshal: %w", ErrInvalidCursor)
+	}
+
+	if tkn.Field == "" || tkn.ID == "" || (tkn.Direction != order.ASC && tkn.Direction != order.DESC) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	cur := Cursor{
+		OrderBy:  order.NewBy(tkn.Field, tkn.Direction),
+		Key:      &Key{Value: tkn.Value, ID: tkn.ID},
+		Backward: tkn.Backward,
+	}
+
+	return cur, nil
+}
+
+// Ordered returns the cursor using the specified ordering when it points at
+// the start of the rows. A cursor within the rows keeps the ordering it was
+// issued for, since its key means nothing in another one.
+func (c Cursor) Ordered(orderBy order.By) Cursor {
+	if c.Key == nil {
+		c.OrderBy = orderBy
+	}
+
+	return c
+}
+
+// Seek returns the comparison operator selecting the rows past the key of the
+// cursor and the direction the rows must be read in. Reading backward flips
+// both, the rows are put back in order by Keyset.
+func (c Cursor) Seek() (string, string) {
+	forward := c.OrderBy.Direction != order.DESC
+	if c.Backward {
+		forward = !forward
+	}
+
+	if forward {
+		return ">", order.ASC
+	}
+
+	return "<", order.DESC
+}
+
+// =============================================================================
+
+// Cursors holds the tokens of the pages around a page of rows. A token is
+// empty when there is no page in that direction.
+type Cursors struct {
+	Next string
+	Prev string
+}
+
+// Keyset takes the rows read for the cursor, which are expected to hold one
+// row more than the page to tell if there are more rows, and returns the page
+// in order along with the cursors to the pages around it.
+func Keyset[T any](rows []T, cur Cursor, rowsPerPage int, key func(T) Key) ([]T, Cursors) {
+	more := len(rows) > rowsPerPage
+	if more {
+		rows = rows[:rowsPerPage]
+	}
+
+	if cur.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	hasNext, hasPrev := more, cur.Key != nil
+	if cur.Backward {
+		hasNext, hasPrev = true, more
+	}
+
+	first, last := cur.Key, cur.Key
+	if len(rows) > 0 {
+		firstKey, lastKey := key(rows[0]), key(rows[len(rows)-1])
+		first, last = &firstKey, &lastKey
+	}
+
+	var cursors Cursors
+
+	if hasNext && last != nil {
+		cursors.Next = Cursor{OrderBy: cur.OrderBy, Key: last}.Encode()
+	}
+
+	if hasPrev && first != nil {
+		cursors.Prev = Cursor{OrderBy: cur.OrderBy, Key: first, Backward: true}.Encode()
+	}
+
+	return rows, cursors
+}
@@ -0,0 +1,143 @@
+package page_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/order"
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Cursor(t *testing.T) {
+	cur := page.Cursor{
+		OrderBy:  order.NewBy("name", order.DESC),
+		Key:      &page.Key{Value: "Comic Books", ID: "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"},
+		Backward: true,
+	}
+
+	got, err := page.DecodeCursor(cur.Encode())
+	if err != nil {
+		t.Fatalf("Should be able to decode the cursor : %s", err)
+	}
+
+	if diff := cmp.Diff(got, cur); diff != "" {
+		t.Fatalf("Should get back the same cursor. Diff:\n%s", diff)
+	}
+
+	if _, err := page.DecodeCursor("not-a-cursor"); !errors.Is(err, page.ErrInvalidCursor) {
+		t.Fatalf("Should NOT be able to decode an invalid cursor : %v", err)
+	}
+
+	if op, direction := cur.Seek(); op != ">" || direction != order.ASC {
+		t.Fatalf("Should read a descending order backward in ascending order : %s %s", op, direction)
+	}
+}
+
+func Test_KeyCheck(t *testing.T) {
+	const id = "45b5fbd3-755f-4379-8f07-a58d4a30fa2f"
+
+	tests := []struct {
+		typ   string
+		value string
+		id    string
+		valid bool
+	}{
+		{typ: "UUID", value: id, id: id, valid: true},
+		{typ: "UUID", value: "not-a-uuid", id: id},
+		{typ: "TEXT", value: "Comic Books", id: id, valid: true},
+		{typ: "TEXT", value: "Comic\x00Books", id: id},
+		{typ: "INT", value: "42", id: id, valid: true},
+		{typ: "INT", value: "99999999999", id: id},
+		{typ: "NUMERIC", value: "10.5", id: id, valid: true},
+		{typ: "NUMERIC", value: "NaN", id: id},
+		{typ: "BOOLEAN", value: "true", id: id, valid: true},
+		{typ: "BOOLEAN", value: "yes", id: id},
+		{typ: "TEXT", value: "Comic Books", id: "1"},
+	}
+
+	for _, tt := range tests {
+		err := page.Key{Value: tt.value, ID: tt.id}.Check(tt.typ)
+
+		if tt.valid && err != nil {
+			t.Fatalf("Should accept the %s key %q : %s", tt.typ, tt.value, err)
+		}
+
+		if !tt.valid && !errors.Is(err, page.ErrInvalidCursor) {
+			t.Fatalf("Should reject the %s key %q id %q : %v", tt.typ, tt.value, tt.id, err)
+		}
+	}
+}
+
+func Test_Keyset(t *testing.T) {
+	key := func(v int) page.Key {
+		return page.Key{Value: strconv.Itoa(v), ID: strconv.Itoa(v)}
+	}
+
+	decode := func(t *testing.T, token string) *page.Cursor {
+		if token == "" {
+			return nil
+		}
+
+		cur, err := page.DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("Should be able to decode the cursor : %s", err)
+		}
+
+		return &cur
+	}
+
+	orderBy := order.NewBy("id", order.ASC)
+
+	tests := []struct {
+		name    string
+		rows    []int
+		cur     page.Cursor
+		expRows []int
+		expNext *page.Key
+		expPrev *page.Key
+	}{
+		{
+			name:    "first",
+			rows:    []int{1, 2, 3},
+			cur:     page.Cursor{OrderBy: orderBy},
+			expRows: []int{1, 2},
+			expNext: &page.Key{Value: "2", ID: "2"},
+		},
+		{
+			name:    "last",
+			rows:    []int{3, 4},
+			cur:     page.Cursor{OrderBy: orderBy, Key: &page.Key{Value: "2", ID: "2"}},
+			expRows: []int{3, 4},
+			expPrev: &page.Key{Value: "3", ID: "3"},
+		},
+		{
+			name:    "backward",
+			rows:    []int{2, 1},
+			cur:     page.Cursor{OrderBy: orderBy, Key: &page.Key{Value: "3", ID: "3"}, Backward: true},
+			expRows: []int{1, 2},
+			expNext: &page.Key{Value: "2", ID: "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, cursors := page.Keyset(tt.rows, tt.cur, 2, key)
+
+			if diff := cmp.Diff(rows, tt.expRows); diff != "" {
+				t.Fatalf("Should get the expected rows. Diff:\n%s", diff)
+			}
+
+			next := decode(t, cursors.Next)
+			if (next == nil) != (tt.expNext == nil) || (next != nil && (*next.Key != *tt.expNext || next.Backward)) {
+				t.Fatalf("Should get the expected next cursor : %+v", next)
+			}
+
+			prev := decode(t, cursors.Prev)
+			if (prev == nil) != (tt.expPrev == nil) || (prev != nil && (*prev.Key != *tt.expPrev || !prev.Backward)) {
+				t.Fatalf("Should get the expected prev cursor : %+v", prev)
+			}
+		})
+	}
+}
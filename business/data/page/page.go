@@ -8,14 +8,17 @@ import (
 	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
 )
 
-// Page represents the requested page and rows per page.
+// Page represents the requested page and rows per page. The cursor is set
+// when the request pages through the rows with cursors instead of numbers.
 type Page struct {
 	Number      int
 	RowsPerPage int
+	Cursor      *Cursor
 }
 
-// Parse parses the request for the page and rows query string. The
-// defaults are provided as well.
+// Parse parses the request for the page, rows and cursor query string. The
+// defaults are provided as well. An empty cursor asks for the first page of
+// the rows with cursors.
 func Parse(r *http.Request) (Page, error) {
 	values := r.URL.Query()
 
@@ -37,8 +40,18 @@ func Parse(r *http.Request) (Page, error) {
 		}
 	}
 
+	var cursor *Cursor
+	if values.Has("cursor") {
+		cur, err := DecodeCursor(values.Get("cursor"))
+		if err != nil {
+			return Page{}, validate.NewFieldsError("cursor", err)
+		}
+		cursor = &cur
+	}
+
 	return Page{
 		Number:      number,
 		RowsPerPage: rowsPerPage,
+		Cursor:      cursor,
 	}, nil
 }
@@ -0,0 +1,144 @@
+// Package query provides support for describing the conditions a query
+// filters data by beyond a plain match.
+package query
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/validate"
+)
+
+// Set of operators a field can be filtered with. These are the names used in
+// the query string, as in cost[gte]=10.
+const (
+	GT  = "gt"
+	GTE = "gte"
+	LT  = "lt"
+	LTE = "lte"
+	IN  = "in"
+)
+
+// Range represents the bounds a value is filtered by. A nil bound is not
+// applied.
+type Range[T any] struct {
+	GT  *T
+	GTE *T
+	LT  *T
+	LTE *T
+}
+
+// IsZero reports whether the range has no bound.
+func (r Range[T]) IsZero() bool {
+	return r.GT == nil && r.GTE == nil && r.LT == nil && r.LTE == nil
+}
+
+// Where returns the conditions bounding the column by the range. The bounds
+// are added to the data under the name followed by their operator, the column
+// is expected to be a constant of the caller and never user input.
+func (r Range[T]) Where(column string, name string, data map[string]interface{}) []string {
+	var wc []string
+
+	bounds := []struct {
+		op    string
+		sql   string
+		value *T
+	}{
+		{GT, ">", r.GT},
+		{GTE, ">=", r.GTE},
+		{LT, "<", r.LT},
+		{LTE, "<=", r.LTE},
+	}
+
+	for _, b := range bounds {
+		if b.value == nil {
+			continue
+		}
+
+		key := name + "_" + b.op
+		data[key] = *b.value
+		wc = append(wc, column+" "+b.sql+" :"+key)
+	}
+
+	return wc
+}
+
+// =============================================================================
+
+// ParseRange parses the bounds of the field given in the query string in the
+// form field[op]=value, using parse to convert the values.
+func ParseRange[T any](values url.Values, field string, parse func(string) (T, error)) (Range[T], error) {
+	var r Range[T]
+
+	bounds := []struct {
+		op    string
+		value **T
+	}{
+		{GT, &r.GT},
+		{GTE, &r.GTE},
+		{LT, &r.LT},
+		{LTE, &r.LTE},
+	}
+
+	for _, b := range bounds {
+		key := field + "[" + b.op + "]"
+
+		v := values.Get(key)
+		if v == "" {
+			continue
+		}
+
+		value, err := parse(v)
+		if err != nil {
+			return Range[T]{}, validate.NewFieldsError(key, err)
+		}
+		*b.value = &value
+	}
+
+	return r, nil
+}
+
+// ParseList parses the values of the field given in the query string as a
+// comma separated list in the form field[in]=a,b,c, using parse to convert the
+// values.
+func ParseList[T any](values url.Values, field string, parse func(string) (T, error)) ([]T, error) {
+	key := field + "[" + IN + "]"
+
+	v := values.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(v, ",")
+
+	list := make([]T, len(parts))
+	for i, part := range parts {
+		value, err := parse(strings.TrimSpace(part))
+		if err != nil {
+			return nil, validate.NewFieldsError(key, err)
+		}
+		list[i] = value
+	}
+
+	return list, nil
+}
+
+// =============================================================================
+
+// ParseFloat converts a value of the query string to a float.
+func ParseFloat(v string) (float64, error) {
+	return strconv.ParseFloat(v, 64)
+}
+
+// ParseTime converts a value of the query string in the RFC3339 format to a
+// time in UTC.
+func ParseTime(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.UTC(), nil
+}
@@ -0,0 +1,65 @@
+package query_test
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/query"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Range(t *testing.T) {
+	values, err := url.ParseQuery("cost[gte]=10&cost[lt]=20.5&quantity[gt]=x")
+	if err != nil {
+		t.Fatalf("Should be able to parse the query : %s", err)
+	}
+
+	cost, err := query.ParseRange(values, "cost", query.ParseFloat)
+	if err != nil {
+		t.Fatalf("Should be able to parse the range : %s", err)
+	}
+
+	if cost.GTE == nil || *cost.GTE != 10 || cost.LT == nil || *cost.LT != 20.5 || cost.GT != nil || cost.LTE != nil {
+		t.Fatalf("Should get the bounds of the range : %+v", cost)
+	}
+
+	data := map[string]interface{}{}
+
+	wc := cost.Where("cost", "cost", data)
+	if diff := cmp.Diff(wc, []string{"cost >= :cost_gte", "cost < :cost_lt"}); diff != "" {
+		t.Fatalf("Should get the conditions of the range. Diff:\n%s", diff)
+	}
+
+	if data["cost_gte"] != 10.0 || data["cost_lt"] != 20.5 {
+		t.Fatalf("Should add the bounds to the data : %v", data)
+	}
+
+	if _, err := query.ParseRange(values, "quantity", strconv.Atoi); err == nil {
+		t.Fatalf("Should NOT be able to parse an invalid bound")
+	}
+
+	if r, err := query.ParseRange(values, "name", strconv.Atoi); err != nil || !r.IsZero() {
+		t.Fatalf("Should get an empty range for a missing field : %+v : %v", r, err)
+	}
+}
+
+func Test_List(t *testing.T) {
+	values, err := url.ParseQuery("quantity[in]=1,%202,3&cost[in]=1,x")
+	if err != nil {
+		t.Fatalf("Should be able to parse the query : %s", err)
+	}
+
+	list, err := query.ParseList(values, "quantity", strconv.Atoi)
+	if err != nil {
+		t.Fatalf("Should be able to parse the list : %s", err)
+	}
+
+	if diff := cmp.Diff(list, []int{1, 2, 3}); diff != "" {
+		t.Fatalf("Should get the values of the list. Diff:\n%s", diff)
+	}
+
+	if _, err := query.ParseList(values, "cost", strconv.Atoi); err == nil {
+		t.Fatalf("Should NOT be able to parse an invalid value")
+	}
+}
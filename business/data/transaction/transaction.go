@@ -21,6 +21,24 @@ type Beginner interface {
 	Begin() (Transaction, error)
 }
 
+// Hooker represents a transaction that can run functions once it has been
+// committed.
+type Hooker interface {
+	AfterCommit(fn func())
+}
+
+// AfterCommit runs the function once the transaction has been committed, the
+// function is dropped when the transaction is rolled back. A transaction that
+// can't hold functions runs it right away.
+func AfterCommit(tx Transaction, fn func()) {
+	if h, ok := tx.(Hooker); ok {
+		h.AfterCommit(fn)
+		return
+	}
+
+	fn()
+}
+
 // =============================================================================
 
 type ctxKey int
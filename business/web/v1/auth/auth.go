@@ -9,8 +9,20 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey"
+	"github.com/diegomagalhaes-dev/go-service/business/core/apikey/stores/apikeydb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit"
+	"github.com/diegomagalhaes-dev/go-service/business/core/audit/stores/auditdb"
 	"github.com/diegomagalhaes-dev/go-service/business/core/event"
+	"github.com/diegomagalhaes-dev/go-service/business/core/event/stores/eventdb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role"
+	"github.com/diegomagalhaes-dev/go-service/business/core/role/stores/roledb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session"
+	"github.com/diegomagalhaes-dev/go-service/business/core/session/stores/sessiondb"
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/core/user/stores/userdb"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
@@ -26,23 +38,46 @@ var ErrForbidden = errors.New("attempted action is not allowed")
 // Claims represents the authorization claims transmitted via a JWT.
 type Claims struct {
 	jwt.RegisteredClaims
-	Roles []user.Role `json:"roles"`
+	TenantID      string      `json:"tenant_id,omitempty"`
+	Roles         []user.Role `json:"roles"`
+	Permissions   []string    `json:"permissions,omitempty"`
+	AMR           []string    `json:"amr,omitempty"`
+	EmailVerified bool        `json:"email_verified"`
 }
 
 // KeyLookup declares a method set of behavior for looking up
 // private and public keys for JWT use. The return could be a
-// PEM encoded string or a JWS based key.
+// PEM encoded string or a JWS based key. The lookup acts as a key
+// ring: ActiveKID is the only kid used for signing and KIDs lists
+// the kids that can still verify tokens so the public keys can be
+// published. PublicKey must fail for a kid that has been retired.
 type KeyLookup interface {
 	PrivateKey(kid string) (key string, err error)
 	PublicKey(kid string) (key string, err error)
+	KIDs() (kids []string, err error)
+	ActiveKID() (kid string, err error)
 }
 
-// Config represents information required to initialize auth.
+// DefaultKeyCacheTTL is the amount of time a public key is cached before the
+// key lookup is asked for it again.
+const DefaultKeyCacheTTL = 5 * time.Minute
+
+// Config represents information required to initialize auth. When a
+// PolicyDir is provided, the OPA policies are loaded from that directory
+// instead of using the embedded ones.
 type Config struct {
-	Log       *logger.Logger
-	DB        *sqlx.DB
-	KeyLookup KeyLookup
-	Issuer    string
+	Log         *logger.Logger
+	DB          *sqlx.DB
+	KeyLookup   KeyLookup
+	Issuer      string
+	KeyCacheTTL time.Duration
+	PolicyDir   string
+}
+
+// cachedKey represents a public key held in the cache until it expires.
+type cachedKey struct {
+	pem     string
+	expires time.Time
 }
 
 // Auth is used to authenticate clients. It can generate a token for a
@@ -51,52 +86,84 @@ type Auth struct {
 	log       *logger.Logger
 	keyLookup KeyLookup
 	usrCore   *user.Core
-	method    jwt.SigningMethod
+	sesCore   *session.Core
+	apkCore   *apikey.Core
+	rolCore   *role.Core
 	parser    *jwt.Parser
 	issuer    string
+	cacheTTL  time.Duration
+	policyDir string
+	policies  atomic.Pointer[policySet]
 	mu        sync.RWMutex
-	cache     map[string]string
+	cache     map[string]cachedKey
 }
 
 // New creates an Auth to support authentication/authorization.
 func New(cfg Config) (*Auth, error) {
 
 	// If a database connection is not provided, we won't perform the
-	// user enabled check.
+	// user enabled and token revocation checks, api keys can't be used and
+	// roles grant no permissions.
 	var usrCore *user.Core
+	var sesCore *session.Core
+	var apkCore *apikey.Core
+	var rolCore *role.Core
 	if cfg.DB != nil {
-		evnCore := event.NewCore(cfg.Log)
-		usrCore = user.NewCore(cfg.Log, evnCore, userdb.NewStore(cfg.Log, cfg.DB))
+		evnCore := event.NewCore(cfg.Log, eventdb.NewStore(cfg.Log, cfg.DB))
+		audCore := audit.NewCore(cfg.Log, auditdb.NewStore(cfg.Log, cfg.DB), audit.WithActor(GetSubject))
+		usrCore = user.NewCore(cfg.Log, evnCore, audCore, userdb.NewStore(cfg.Log, cfg.DB))
+		sesCore = session.NewCore(cfg.Log, sessiondb.NewStore(cfg.Log, cfg.DB))
+		apkCore = apikey.NewCore(cfg.Log, usrCore, apikeydb.NewStore(cfg.Log, cfg.DB))
+		rolCore = role.NewCore(cfg.Log, roledb.NewStore(cfg.Log, cfg.DB))
+	}
+
+	if cfg.KeyCacheTTL <= 0 {
+		cfg.KeyCacheTTL = DefaultKeyCacheTTL
 	}
 
 	a := Auth{
 		log:       cfg.Log,
 		keyLookup: cfg.KeyLookup,
 		usrCore:   usrCore,
-		method:    jwt.GetSigningMethod(jwt.SigningMethodRS256.Name),
-		parser:    jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Name})),
+		sesCore:   sesCore,
+		apkCore:   apkCore,
+		rolCore:   rolCore,
+		parser:    jwt.NewParser(jwt.WithValidMethods(supportedMethods)),
 		issuer:    cfg.Issuer,
-		cache:     make(map[string]string),
+		cacheTTL:  cfg.KeyCacheTTL,
+		policyDir: cfg.PolicyDir,
+		cache:     make(map[string]cachedKey),
+	}
+
+	if err := a.ReloadPolicies(context.Background()); err != nil {
+		return nil, fmt.Errorf("loading policies: %w", err)
 	}
 
 	return &a, nil
 }
 
 // GenerateToken generates a signed JWT token string representing the user Claims.
-func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
-	token := jwt.NewWithClaims(a.method, claims)
-	token.Header["kid"] = kid
+// The token is signed with the active kid of the key ring and the signing
+// algorithm is selected by the type of the key behind the kid.
+func (a *Auth) GenerateToken(claims Claims) (string, error) {
+	kid, err := a.keyLookup.ActiveKID()
+	if err != nil {
+		return "", fmt.Errorf("active kid: %w", err)
+	}
 
 	privateKeyPEM, err := a.keyLookup.PrivateKey(kid)
 	if err != nil {
 		return "", fmt.Errorf("private key: %w", err)
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	privateKey, method, err := parsePrivateKey(privateKeyPEM)
 	if err != nil {
 		return "", fmt.Errorf("parsing private pem: %w", err)
 	}
 
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
 	str, err := token.SignedString(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
@@ -135,16 +202,53 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, fmt.Errorf("failed to fetch public key: %w", err)
 	}
 
+	publicKey, method, err := parsePublicKey(pem)
+	if err != nil {
+		return Claims{}, fmt.Errorf("parsing public pem: %w", err)
+	}
+
+	// The token must be signed with the algorithm of the kid's key, otherwise
+	// the key could be used with an algorithm it wasn't meant for.
+
+	if token.Method.Alg() != method.Alg() {
+		return Claims{}, fmt.Errorf("alg[%s] doesn't match the kid alg[%s]", token.Method.Alg(), method.Alg())
+	}
+
+	// OPA can't verify EdDSA signatures so that check is performed here and
+	// the policy validates the rest of the token.
+
+	var signatureVerified bool
+	if method == jwt.SigningMethodEdDSA {
+		if err := verifySignature(parts[1], method, publicKey); err != nil {
+			return Claims{}, fmt.Errorf("authentication failed : %w", err)
+		}
+		signatureVerified = true
+	}
+
 	input := map[string]any{
-		"Key":   pem,
-		"Token": parts[1],
-		"ISS":   a.issuer,
+		"Key":               pem,
+		"Token":             parts[1],
+		"ISS":               a.issuer,
+		"ALG":               method.Alg(),
+		"SignatureVerified": signatureVerified,
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, opaAuthentication, RuleAuthenticate, input); err != nil {
+	if err := a.opaPolicyEvaluation(ctx, RuleAuthenticate, input); err != nil {
 		return Claims{}, fmt.Errorf("authentication failed : %w", err)
 	}
 
+	// Tokens are revoked by their jti, a token without one could never be.
+
+	if claims.ID == "" {
+		return Claims{}, errors.New("jti missing from claims")
+	}
+
+	// Check the database to verify this token has not been revoked.
+
+	if err := a.isTokenRevoked(ctx, claims); err != nil {
+		return Claims{}, fmt.Errorf("token revoked : %w", err)
+	}
+
 	// Check the database for this user to verify they are still enabled.
 
 	if err := a.isUserEnabled(ctx, claims); err != nil {
@@ -154,17 +258,123 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 	return claims, nil
 }
 
+// AuthenticateAPIKey validates the api key and returns claims for the user
+// the key belongs to. The roles in the claims are the roles of the key that
+// the user still holds, so a key never grants more than its user has.
+func (a *Auth) AuthenticateAPIKey(ctx context.Context, rawKey string) (Claims, error) {
+	if a.apkCore == nil {
+		return Claims{}, errors.New("api keys are not supported")
+	}
+
+	// The tenant is only known once the key is found.
+	ctx = tenant.Unscoped(ctx)
+
+	key, err := a.apkCore.Authenticate(ctx, rawKey)
+	if err != nil {
+		return Claims{}, fmt.Errorf("authenticate: %w", err)
+	}
+
+	usr, err := a.usrCore.QueryByID(tenant.Set(ctx, key.TenantID), key.UserID)
+	if err != nil {
+		return Claims{}, fmt.Errorf("query user: %w", err)
+	}
+
+	if !usr.Enabled {
+		return Claims{}, fmt.Errorf("user not enabled : userID[%s]", usr.ID)
+	}
+
+	roles := make([]user.Role, 0, len(key.Roles))
+	for _, role := range key.Roles {
+		for _, usrRole := range usr.Roles {
+			if role == usrRole {
+				roles = append(roles, role)
+				break
+			}
+		}
+	}
+
+	perms, err := a.Permissions(ctx, usr.TenantID, roles)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: usr.ID.String(),
+			Issuer:  a.issuer,
+		},
+		TenantID:      usr.TenantID.String(),
+		Roles:         roles,
+		Permissions:   perms,
+		AMR:           []string{AMRAPIKey},
+		EmailVerified: usr.EmailVerified,
+	}
+
+	return claims, nil
+}
+
+// Permissions returns the permissions granted by the roles of the tenant,
+// which are embedded in the claims when a token is generated. Changes to a
+// role apply to the tokens generated afterwards.
+func (a *Auth) Permissions(ctx context.Context, tenantID uuid.UUID, roles []user.Role) ([]string, error) {
+	if a.rolCore == nil {
+		return nil, nil
+	}
+
+	perms, err := a.rolCore.Permissions(tenant.Set(ctx, tenantID), roles)
+	if err != nil {
+		return nil, fmt.Errorf("permissions: %w", err)
+	}
+
+	return perms, nil
+}
+
 // Authorize attempts to authorize the user with the provided input roles, if
 // none of the input roles are within the user's claims, we return an error
 // otherwise the user is authorized.
 func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, rule string) error {
+	input := map[string]any{
+		"Roles":         claims.Roles,
+		"Subject":       claims.Subject,
+		"UserID":        userID,
+		"AMR":           claims.AMR,
+		"EmailVerified": claims.EmailVerified,
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, rule, input); err != nil {
+		return fmt.Errorf("rego evaluation failed : %w", err)
+	}
+
+	return nil
+}
+
+// AuthorizeOwner attempts to authorize the user for a resource owned by the
+// specified user. Admins are authorized for every resource, other users only
+// for the resources they own.
+func (a *Auth) AuthorizeOwner(ctx context.Context, claims Claims, ownerID uuid.UUID) error {
 	input := map[string]any{
 		"Roles":   claims.Roles,
 		"Subject": claims.Subject,
-		"UserID":  userID,
+		"OwnerID": ownerID.String(),
+	}
+
+	if err := a.opaPolicyEvaluation(ctx, RuleAdminOrOwner, input); err != nil {
+		return fmt.Errorf("rego evaluation failed : %w", err)
+	}
+
+	return nil
+}
+
+// AuthorizePermission attempts to authorize the user for the permission. The
+// permission is granted when the claims hold it, hold a wildcard for its
+// resource or hold the * permission.
+func (a *Auth) AuthorizePermission(ctx context.Context, claims Claims, permission string) error {
+	input := map[string]any{
+		"Permissions": claims.Permissions,
+		"Permission":  permission,
 	}
 
-	if err := a.opaPolicyEvaluation(ctx, opaAuthorization, rule, input); err != nil {
+	if err := a.opaPolicyEvaluation(ctx, RulePermission, input); err != nil {
 		return fmt.Errorf("rego evaluation failed : %w", err)
 	}
 
@@ -174,16 +384,21 @@ func (a *Auth) Authorize(ctx context.Context, claims Claims, userID uuid.UUID, r
 // =============================================================================
 
 // publicKeyLookup performs a lookup for the public pem for the specified kid.
+// Cached keys expire so a retired kid stops verifying tokens once the key
+// lookup no longer provides it.
 func (a *Auth) publicKeyLookup(kid string) (string, error) {
 	pem, err := func() (string, error) {
 		a.mu.RLock()
 		defer a.mu.RUnlock()
 
-		pem, exists := a.cache[kid]
+		key, exists := a.cache[kid]
 		if !exists {
 			return "", errors.New("not found")
 		}
-		return pem, nil
+		if time.Now().After(key.expires) {
+			return "", errors.New("expired")
+		}
+		return key.pem, nil
 	}()
 	if err == nil {
 		return pem, nil
@@ -191,27 +406,29 @@ func (a *Auth) publicKeyLookup(kid string) (string, error) {
 
 	pem, err = a.keyLookup.PublicKey(kid)
 	if err != nil {
+		a.mu.Lock()
+		delete(a.cache, kid)
+		a.mu.Unlock()
+
 		return "", fmt.Errorf("fetching public key: %w", err)
 	}
 
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.cache[kid] = pem
+	a.cache[kid] = cachedKey{
+		pem:     pem,
+		expires: time.Now().Add(a.cacheTTL),
+	}
 
 	return pem, nil
 }
 
-// opaPolicyEvaluation asks opa to evaulate the token against the specified token
-// policy and public key.
-func (a *Auth) opaPolicyEvaluation(ctx context.Context, opaPolicy string, rule string, input any) error {
-	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
-
-	q, err := rego.New(
-		rego.Query(query),
-		rego.Module("policy.rego", opaPolicy),
-	).PrepareForEval(ctx)
-	if err != nil {
-		return err
+// opaPolicyEvaluation asks opa to evaulate the input against the query that
+// was prepared for the specified rule.
+func (a *Auth) opaPolicyEvaluation(ctx context.Context, rule string, input any) error {
+	q, exists := a.policies.Load().queries[rule]
+	if !exists {
+		return fmt.Errorf("rule[%s] is not defined", rule)
 	}
 
 	results, err := q.Eval(ctx, rego.EvalInput(input))
@@ -243,9 +460,34 @@ func (a *Auth) isUserEnabled(ctx context.Context, claims Claims) error {
 		return fmt.Errorf("parse user: %w", err)
 	}
 
-	if _, err := a.usrCore.QueryByID(ctx, userID); err != nil {
+	tenantID, err := uuid.Parse(claims.TenantID)
+	if err != nil {
+		return fmt.Errorf("parse tenant: %w", err)
+	}
+
+	if _, err := a.usrCore.QueryByID(tenant.Set(ctx, tenantID), userID); err != nil {
 		return fmt.Errorf("query user: %w", err)
 	}
 
 	return nil
 }
+
+// isTokenRevoked hits the database and checks the token identified by the jti
+// claim was not revoked. If the no database connection was provided, this
+// check is skipped.
+func (a *Auth) isTokenRevoked(ctx context.Context, claims Claims) error {
+	if a.sesCore == nil {
+		return nil
+	}
+
+	revoked, err := a.sesCore.IsAccessRevoked(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("query revocation: %w", err)
+	}
+
+	if revoked {
+		return fmt.Errorf("jti[%s] is revoked", claims.ID)
+	}
+
+	return nil
+}
@@ -3,13 +3,25 @@ package auth_test
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
+	"github.com/diegomagalhaes-dev/go-service/foundation/keystore"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -39,6 +51,7 @@ func Test_Auth(t *testing.T) {
 
 	claims := auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -48,7 +61,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID := uuid.MustParse(claims.Subject)
 
-	token, err := a.GenerateToken(kid, claims)
+	token, err := a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %s", err)
 	}
@@ -58,6 +71,18 @@ func Test_Auth(t *testing.T) {
 		t.Fatalf("Should be able to authenticate the claims : %s", err)
 	}
 
+	noJTI := claims
+	noJTI.ID = ""
+
+	noJTIToken, err := a.GenerateToken(noJTI)
+	if err != nil {
+		t.Fatalf("Should be able to generate a JWT : %s", err)
+	}
+
+	if _, err := a.Authenticate(context.Background(), "Bearer "+noJTIToken); err == nil {
+		t.Fatal("Should NOT be able to authenticate a token without a jti")
+	}
+
 	err = a.Authorize(context.Background(), parsedClaims, userID, auth.RuleAdminOnly)
 	if err != nil {
 		t.Errorf("Should be able to authorize the RoleAdmin claims : %s", err)
@@ -73,10 +98,55 @@ func Test_Auth(t *testing.T) {
 		t.Errorf("Should be able to authorize the RuleAdminOrSubject claim with RoleAdmin only : %s", err)
 	}
 
+	err = a.Authorize(context.Background(), parsedClaims, userID, auth.RuleAdminMFA)
+	if err == nil {
+		t.Error("Should NOT be able to authorize the RuleAdminMFA claim without the mfa amr")
+	}
+
+	claims.AMR = []string{auth.AMRPassword, auth.AMROTP, auth.AMRMFA}
+
+	token, err = a.GenerateToken(claims)
+	if err != nil {
+		t.Fatalf("Should be able to generate a JWT : %s", err)
+	}
+
+	parsedClaims, err = a.Authenticate(context.Background(), "Bearer "+token)
+	if err != nil {
+		t.Fatalf("Should be able to authenticate the claims : %s", err)
+	}
+
+	err = a.Authorize(context.Background(), parsedClaims, userID, auth.RuleAdminMFA)
+	if err != nil {
+		t.Errorf("Should be able to authorize the RuleAdminMFA claim with the mfa amr : %s", err)
+	}
+
+	err = a.Authorize(context.Background(), parsedClaims, userID, auth.RuleEmailVerified)
+	if err == nil {
+		t.Error("Should NOT be able to authorize the RuleEmailVerified claim without a verified email")
+	}
+
+	claims.EmailVerified = true
+
+	token, err = a.GenerateToken(claims)
+	if err != nil {
+		t.Fatalf("Should be able to generate a JWT : %s", err)
+	}
+
+	parsedClaims, err = a.Authenticate(context.Background(), "Bearer "+token)
+	if err != nil {
+		t.Fatalf("Should be able to authenticate the claims : %s", err)
+	}
+
+	err = a.Authorize(context.Background(), parsedClaims, userID, auth.RuleEmailVerified)
+	if err != nil {
+		t.Errorf("Should be able to authorize the RuleEmailVerified claim with a verified email : %s", err)
+	}
+
 	// -------------------------------------------------------------------------
 
 	claims = auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -86,7 +156,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID = uuid.MustParse(claims.Subject)
 
-	token, err = a.GenerateToken(kid, claims)
+	token, err = a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %v", err)
 	}
@@ -120,6 +190,7 @@ func Test_Auth(t *testing.T) {
 
 	claims = auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -129,7 +200,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID = uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
-	token, err = a.GenerateToken(kid, claims)
+	token, err = a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %s", err)
 	}
@@ -148,6 +219,7 @@ func Test_Auth(t *testing.T) {
 
 	claims = auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -157,7 +229,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID = uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
-	token, err = a.GenerateToken(kid, claims)
+	token, err = a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %s", err)
 	}
@@ -176,6 +248,7 @@ func Test_Auth(t *testing.T) {
 
 	claims = auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -185,7 +258,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID = uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
-	token, err = a.GenerateToken(kid, claims)
+	token, err = a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %s", err)
 	}
@@ -204,6 +277,7 @@ func Test_Auth(t *testing.T) {
 
 	claims = auth.Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    "service project",
 			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
@@ -213,7 +287,7 @@ func Test_Auth(t *testing.T) {
 	}
 	userID = uuid.MustParse("9e979baa-61c9-4b50-81f2-f216d53f5c15")
 
-	token, err = a.GenerateToken(kid, claims)
+	token, err = a.GenerateToken(claims)
 	if err != nil {
 		t.Fatalf("Should be able to generate a JWT : %s", err)
 	}
@@ -227,6 +301,472 @@ func Test_Auth(t *testing.T) {
 	if err != nil {
 		t.Errorf("Should be able to authorize the RuleAny any claim with RoleAdmin only : %s", err)
 	}
+
+	// -------------------------------------------------------------------------
+
+	jwks, err := a.JWKS()
+	if err != nil {
+		t.Fatalf("Should be able to produce the jwks : %s", err)
+	}
+
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != kid || jwks.Keys[0].E != "AQAB" {
+		t.Fatalf("Should get back the public key for the kid : %+v", jwks.Keys)
+	}
+}
+
+func Test_AuthAlgorithms(t *testing.T) {
+	log, db, teardown := newUnit(t)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		teardown()
+	}()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate an ECDSA key : %s", err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate an Ed25519 key : %s", err)
+	}
+
+	_, otherEdKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate an Ed25519 key : %s", err)
+	}
+
+	ks := keystore.NewMap(map[string]keystore.PrivateKey{
+		"es256": toPrivateKey(t, ecKey),
+		"eddsa": toPrivateKey(t, edKey),
+		"other": toPrivateKey(t, otherEdKey),
+	})
+
+	cfg := auth.Config{
+		Log:         log,
+		DB:          db,
+		KeyLookup:   ks,
+		Issuer:      "service project",
+		KeyCacheTTL: time.Millisecond,
+	}
+	a, err := auth.New(cfg)
+	if err != nil {
+		t.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "service project",
+			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Roles: []user.Role{user.RoleAdmin},
+	}
+
+	tests := []struct {
+		kid string
+		alg string
+	}{
+		{kid: "es256", alg: jwt.SigningMethodES256.Alg()},
+		{kid: "eddsa", alg: jwt.SigningMethodEdDSA.Alg()},
+	}
+
+	var esToken string
+	for _, tt := range tests {
+		kid, alg := tt.kid, tt.alg
+
+		if err := ks.Promote(kid, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Should be able to promote kid %q : %s", kid, err)
+		}
+
+		token, err := a.GenerateToken(claims)
+		if err != nil {
+			t.Fatalf("Should be able to generate a %s JWT : %s", alg, err)
+		}
+
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &auth.Claims{})
+		if err != nil {
+			t.Fatalf("Should be able to parse the %s JWT : %s", alg, err)
+		}
+
+		if parsed.Method.Alg() != alg {
+			t.Fatalf("Should sign the token for kid %q with %s : %s", kid, alg, parsed.Method.Alg())
+		}
+
+		if parsed.Header["kid"] != kid {
+			t.Fatalf("Should sign the token with the active kid %q : %v", kid, parsed.Header["kid"])
+		}
+
+		if _, err := a.Authenticate(context.Background(), "Bearer "+token); err != nil {
+			t.Fatalf("Should be able to authenticate the %s claims : %s", alg, err)
+		}
+
+		if kid == "es256" {
+			esToken = token
+		}
+	}
+
+	if _, err := a.Authenticate(context.Background(), "Bearer "+esToken); err != nil {
+		t.Fatalf("Should be able to authenticate a token from a kid that is not retired yet : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	forged.Header["kid"] = "eddsa"
+
+	str, err := forged.SignedString(otherEdKey)
+	if err != nil {
+		t.Fatalf("Should be able to sign the forged JWT : %s", err)
+	}
+
+	if _, err := a.Authenticate(context.Background(), "Bearer "+str); err == nil {
+		t.Fatal("Should NOT be able to authenticate a token signed by another key")
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := ks.Promote("es256", time.Now()); err != nil {
+		t.Fatalf("Should be able to promote kid %q : %s", "es256", err)
+	}
+
+	if err := ks.Promote("other", time.Now()); err != nil {
+		t.Fatalf("Should be able to promote kid %q : %s", "other", err)
+	}
+
+	// Wait for the cached public keys to expire.
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := a.Authenticate(context.Background(), "Bearer "+esToken); err == nil {
+		t.Fatal("Should NOT be able to authenticate a token from a retired kid")
+	}
+
+	// -------------------------------------------------------------------------
+
+	jwks, err := a.JWKS()
+	if err != nil {
+		t.Fatalf("Should be able to produce the jwks : %s", err)
+	}
+
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "other" {
+		t.Fatalf("Should only publish the keys that are not retired : %+v", jwks.Keys)
+	}
+
+	for _, key := range jwks.Keys {
+		switch key.Kid {
+		case "es256":
+			if key.Kty != "EC" || key.Crv != "P-256" || key.Alg != "ES256" || key.X == "" || key.Y == "" {
+				t.Fatalf("Should get back an EC key : %+v", key)
+			}
+		case "eddsa", "other":
+			if key.Kty != "OKP" || key.Crv != "Ed25519" || key.Alg != "EdDSA" || key.X == "" {
+				t.Fatalf("Should get back an OKP key : %+v", key)
+			}
+		}
+	}
+}
+
+func Test_AuthPermission(t *testing.T) {
+	log, db, teardown := newUnit(t)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		teardown()
+	}()
+
+	cfg := auth.Config{
+		Log:       log,
+		DB:        db,
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+	}
+	a, err := auth.New(cfg)
+	if err != nil {
+		t.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		permission  string
+		allowed     bool
+	}{
+		{"exact", []string{"user:read"}, "user:read", true},
+		{"otherAction", []string{"user:read"}, "user:write", false},
+		{"resourceWildcard", []string{"product:*"}, "product:write", true},
+		{"otherResource", []string{"product:*"}, "user:read", false},
+		{"wildcard", []string{"*"}, "role:write", true},
+		{"wildcardPlatform", []string{"*"}, "platform:tenant_write", false},
+		{"platformWildcard", []string{"platform:*"}, "platform:tenant_write", true},
+		{"none", nil, "user:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject: "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+				},
+				Roles:       []user.Role{user.RoleUser},
+				Permissions: tt.permissions,
+			}
+
+			err := a.AuthorizePermission(context.Background(), claims, tt.permission)
+			if tt.allowed && err != nil {
+				t.Fatalf("Should be able to authorize %q with %v : %s", tt.permission, tt.permissions, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("Should NOT be able to authorize %q with %v", tt.permission, tt.permissions)
+			}
+		})
+	}
+
+	// A role outside the built in roles is enough for the rules that accept
+	// any role.
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+		},
+		Roles: []user.Role{user.MustParseRole("EDITOR")},
+	}
+
+	if err := a.Authorize(context.Background(), claims, uuid.UUID{}, auth.RuleAny); err != nil {
+		t.Fatalf("Should be able to authorize the RuleAny claim with a stored role : %s", err)
+	}
+
+	if err := a.Authorize(context.Background(), claims, uuid.UUID{}, auth.RuleAdminOnly); err == nil {
+		t.Fatal("Should NOT be able to authorize the RuleAdminOnly claim with a stored role")
+	}
+}
+
+func Test_AuthOwner(t *testing.T) {
+	log, db, teardown := newUnit(t)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		teardown()
+	}()
+
+	cfg := auth.Config{
+		Log:       log,
+		DB:        db,
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+	}
+	a, err := auth.New(cfg)
+	if err != nil {
+		t.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	owner := uuid.MustParse("45b5fbd3-755f-4379-8f07-a58d4a30fa2f")
+	other := uuid.MustParse("5cf37266-3473-4006-984f-9325122678b7")
+
+	tests := []struct {
+		name    string
+		subject uuid.UUID
+		roles   []user.Role
+		allowed bool
+	}{
+		{"owner", owner, []user.Role{user.RoleUser}, true},
+		{"otherUser", other, []user.Role{user.RoleUser}, false},
+		{"admin", other, []user.Role{user.RoleAdmin}, true},
+		{"noRoles", owner, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Subject: tt.subject.String(),
+				},
+				Roles: tt.roles,
+			}
+
+			err := a.AuthorizeOwner(context.Background(), claims, owner)
+			if tt.allowed && err != nil {
+				t.Fatalf("Should be able to authorize %s with %v : %s", tt.subject, tt.roles, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("Should NOT be able to authorize %s with %v", tt.subject, tt.roles)
+			}
+		})
+	}
+}
+
+func Test_AuthPolicyReload(t *testing.T) {
+	log, db, teardown := newUnit(t)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		teardown()
+	}()
+
+	dir := t.TempDir()
+	for _, name := range []string{"authentication.rego", "authorization.rego"} {
+		b, err := os.ReadFile(filepath.Join("rego", name))
+		if err != nil {
+			t.Fatalf("Should be able to read the policy %s : %s", name, err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatalf("Should be able to write the policy %s : %s", name, err)
+		}
+	}
+
+	cfg := auth.Config{
+		Log:       log,
+		DB:        db,
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+		PolicyDir: dir,
+	}
+	a, err := auth.New(cfg)
+	if err != nil {
+		t.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "45b5fbd3-755f-4379-8f07-a58d4a30fa2f",
+		},
+		Roles: []user.Role{user.RoleUser},
+	}
+	userID := uuid.MustParse(claims.Subject)
+
+	if err := a.Authorize(context.Background(), claims, userID, auth.RuleAdminOnly); err == nil {
+		t.Fatal("Should NOT be able to authorize the RoleUser claim with the loaded policy")
+	}
+
+	// -------------------------------------------------------------------------
+
+	authorization := filepath.Join(dir, "authorization.rego")
+
+	b, err := os.ReadFile(authorization)
+	if err != nil {
+		t.Fatalf("Should be able to read the policy : %s", err)
+	}
+
+	relaxed := strings.Replace(string(b), "default ruleAdminOnly = false", "default ruleAdminOnly = true", 1)
+	if err := os.WriteFile(authorization, []byte(relaxed), 0644); err != nil {
+		t.Fatalf("Should be able to change the policy : %s", err)
+	}
+
+	if err := a.ReloadPolicies(context.Background()); err != nil {
+		t.Fatalf("Should be able to reload the policies : %s", err)
+	}
+
+	if err := a.Authorize(context.Background(), claims, userID, auth.RuleAdminOnly); err != nil {
+		t.Fatalf("Should be able to authorize with the reloaded policy : %s", err)
+	}
+
+	// -------------------------------------------------------------------------
+
+	if err := os.WriteFile(authorization, []byte("package diegom7s.rego\n\nruleAny {"), 0644); err != nil {
+		t.Fatalf("Should be able to break the policy : %s", err)
+	}
+
+	if err := a.ReloadPolicies(context.Background()); err == nil {
+		t.Fatal("Should NOT be able to reload a policy that doesn't compile")
+	}
+
+	if err := a.Authorize(context.Background(), claims, userID, auth.RuleAdminOnly); err != nil {
+		t.Fatalf("Should keep the policies in use when a reload fails : %s", err)
+	}
+}
+
+// =============================================================================
+
+func BenchmarkAuthenticate(b *testing.B) {
+	a, token := newBench(b)
+	bearer := "Bearer " + token
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := a.Authenticate(ctx, bearer); err != nil {
+			b.Fatalf("Should be able to authenticate the claims : %s", err)
+		}
+	}
+}
+
+func BenchmarkAuthorize(b *testing.B) {
+	a, _ := newBench(b)
+	ctx := context.Background()
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "5cf37266-3473-4006-984f-9325122678b7",
+		},
+		Roles: []user.Role{user.RoleAdmin},
+	}
+	userID := uuid.MustParse(claims.Subject)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := a.Authorize(ctx, claims, userID, auth.RuleAdminOrSubject); err != nil {
+			b.Fatalf("Should be able to authorize the claims : %s", err)
+		}
+	}
+}
+
+func BenchmarkReloadPolicies(b *testing.B) {
+	a, _ := newBench(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := a.ReloadPolicies(ctx); err != nil {
+			b.Fatalf("Should be able to compile the policies : %s", err)
+		}
+	}
+}
+
+func newBench(b *testing.B) (*auth.Auth, string) {
+	log := logger.New(io.Discard, logger.LevelInfo, "BENCH", func(context.Context) string { return "00000000-0000-0000-0000-000000000000" })
+
+	a, err := auth.New(auth.Config{
+		Log:       log,
+		KeyLookup: &keyStore{},
+		Issuer:    "service project",
+	})
+	if err != nil {
+		b.Fatalf("Should be able to create an authenticator: %s", err)
+	}
+
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "service project",
+			Subject:   "5cf37266-3473-4006-984f-9325122678b7",
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Roles: []user.Role{user.RoleAdmin},
+	}
+
+	token, err := a.GenerateToken(claims)
+	if err != nil {
+		b.Fatalf("Should be able to generate a JWT : %s", err)
+	}
+
+	return a, token
 }
 
 // =============================================================================
@@ -248,6 +788,23 @@ func newUnit(t *testing.T) (*logger.Logger, *sqlx.DB, func()) {
 	return log, nil, teardown
 }
 
+func toPrivateKey(t *testing.T, pk crypto.Signer) keystore.PrivateKey {
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatalf("Should be able to marshal the private key : %s", err)
+	}
+
+	block := pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}
+
+	return keystore.PrivateKey{
+		PK:  pk,
+		PEM: pem.EncodeToMemory(&block),
+	}
+}
+
 // =============================================================================
 
 type keyStore struct{}
@@ -260,6 +817,14 @@ func (ks *keyStore) PublicKey(kid string) (string, error) {
 	return publicKeyPEM, nil
 }
 
+func (ks *keyStore) KIDs() ([]string, error) {
+	return []string{kid}, nil
+}
+
+func (ks *keyStore) ActiveKID() (string, error) {
+	return kid, nil
+}
+
 // =============================================================================
 
 const (
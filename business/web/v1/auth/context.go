@@ -31,6 +31,12 @@ func GetClaims(ctx context.Context) Claims {
 	return v
 }
 
+// GetSubject returns the subject of the claims from the context, which
+// identifies who performs the request.
+func GetSubject(ctx context.Context) string {
+	return GetClaims(ctx).Subject
+}
+
 // SetUserID stores the user id from the request in the context.
 func SetUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, userKey, userID)
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWK represents a public key as defined by RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS represents the set of public keys that can verify our tokens.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Issuer returns the issuer used when validating tokens.
+func (a *Auth) Issuer() string {
+	return a.issuer
+}
+
+// SigningAlgorithms returns the set of algorithms tokens can be signed with.
+func (a *Auth) SigningAlgorithms() []string {
+	algs := make([]string, len(supportedMethods))
+	copy(algs, supportedMethods)
+
+	return algs
+}
+
+// JWKS returns the public keys for every kid known to the key lookup.
+func (a *Auth) JWKS() (JWKS, error) {
+	kids, err := a.keyLookup.KIDs()
+	if err != nil {
+		return JWKS{}, fmt.Errorf("listing kids: %w", err)
+	}
+
+	jwks := JWKS{
+		Keys: make([]JWK, 0, len(kids)),
+	}
+
+	for _, kid := range kids {
+		pem, err := a.publicKeyLookup(kid)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("kid[%s]: %w", kid, err)
+		}
+
+		jwk, err := toJWK(kid, pem)
+		if err != nil {
+			return JWKS{}, fmt.Errorf("kid[%s]: %w", kid, err)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks, nil
+}
+
+// =============================================================================
+
+func toJWK(kid string, pem string) (JWK, error) {
+	publicKey, method, err := parsePublicKey(pem)
+	if err != nil {
+		return JWK{}, fmt.Errorf("parsing public pem: %w", err)
+	}
+
+	jwk := JWK{
+		Use: "sig",
+		Alg: method.Alg(),
+		Kid: kid,
+	}
+
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = k.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	}
+
+	return jwk, nil
+}
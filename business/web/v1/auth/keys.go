@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// supportedMethods is the set of signing methods tokens can be signed with.
+// The method used for a token is selected by the type of the kid's key.
+var supportedMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// parsePrivateKey decodes a PEM encoded RSA, ECDSA or Ed25519 private key and
+// returns the key with the signing method it must be used with.
+func parsePrivateKey(privatePEM string) (any, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(privatePEM))
+	if block == nil {
+		return nil, nil, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	var key any
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, errors.New("invalid key: key must be a PKCS1, PKCS8 or EC private key")
+			}
+		}
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, jwt.SigningMethodRS256, nil
+
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+		return k, jwt.SigningMethodES256, nil
+
+	case ed25519.PrivateKey:
+		return k, jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// parsePublicKey decodes a PEM encoded RSA, ECDSA or Ed25519 public key and
+// returns the key with the signing method it verifies.
+func parsePublicKey(publicPEM string) (any, jwt.SigningMethod, error) {
+	block, _ := pem.Decode([]byte(publicPEM))
+	if block == nil {
+		return nil, nil, errors.New("invalid key: key must be PEM encoded")
+	}
+
+	var key any
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.New("invalid key: key must be a PKIX or PKCS1 public key")
+		}
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k, jwt.SigningMethodRS256, nil
+
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, nil, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+		return k, jwt.SigningMethodES256, nil
+
+	case ed25519.PublicKey:
+		return k, jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+// verifySignature checks the signature of the token using the public key.
+// This is only needed for the algorithms the OPA policy can't verify.
+func verifySignature(token string, method jwt.SigningMethod, publicKey any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("token contains an invalid number of segments")
+	}
+
+	if err := method.Verify(strings.Join(parts[0:2], "."), parts[2], publicKey); err != nil {
+		return fmt.Errorf("verify: %w", err)
+	}
+
+	return nil
+}
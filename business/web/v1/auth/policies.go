@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// policySet holds the queries prepared for every rule of the policies so
+// they are compiled once and not on every request.
+type policySet struct {
+	queries map[string]rego.PreparedEvalQuery
+	version string
+}
+
+// ReloadPolicies compiles the OPA policies and replaces the ones in use. If
+// the policies fail to compile, the policies in use are kept.
+func (a *Auth) ReloadPolicies(ctx context.Context) error {
+	authentication, authorization, version, err := a.readPolicies()
+	if err != nil {
+		return err
+	}
+
+	ps := policySet{
+		queries: make(map[string]rego.PreparedEvalQuery),
+		version: version,
+	}
+
+	policies := []struct {
+		module string
+		rules  []string
+	}{
+		{module: authentication, rules: authenticationRules},
+		{module: authorization, rules: authorizationRules},
+	}
+
+	for _, policy := range policies {
+		for _, rule := range policy.rules {
+			q, err := prepareQuery(ctx, policy.module, rule)
+			if err != nil {
+				return fmt.Errorf("rule[%s]: %w", rule, err)
+			}
+			ps.queries[rule] = q
+		}
+	}
+
+	a.policies.Store(&ps)
+
+	return nil
+}
+
+// WatchPolicies checks the policy directory on every interval and reloads
+// the policies when the files change. It returns when the context is
+// cancelled. Nothing is watched when the policies are embedded.
+func (a *Auth) WatchPolicies(ctx context.Context, interval time.Duration) {
+	if a.policyDir == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			version, err := a.policyVersion()
+			if err != nil {
+				a.log.Error(ctx, "auth: watch policies", "msg", err)
+				continue
+			}
+
+			if version == a.policies.Load().version {
+				continue
+			}
+
+			if err := a.ReloadPolicies(ctx); err != nil {
+				a.log.Error(ctx, "auth: reload policies", "msg", err)
+				continue
+			}
+
+			a.log.Info(ctx, "auth: policies reloaded", "dir", a.policyDir)
+		}
+	}
+}
+
+// =============================================================================
+
+// readPolicies returns the source of the policies along with a version that
+// identifies the state of the files they were read from.
+func (a *Auth) readPolicies() (string, string, string, error) {
+	if a.policyDir == "" {
+		return opaAuthentication, opaAuthorization, "", nil
+	}
+
+	version, err := a.policyVersion()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	authentication, err := os.ReadFile(filepath.Join(a.policyDir, authenticationFile))
+	if err != nil {
+		return "", "", "", fmt.Errorf("reading %s: %w", authenticationFile, err)
+	}
+
+	authorization, err := os.ReadFile(filepath.Join(a.policyDir, authorizationFile))
+	if err != nil {
+		return "", "", "", fmt.Errorf("reading %s: %w", authorizationFile, err)
+	}
+
+	return string(authentication), string(authorization), version, nil
+}
+
+// policyVersion identifies the state of the policy files by their size and
+// modification time.
+func (a *Auth) policyVersion() (string, error) {
+	var version string
+	for _, name := range []string{authenticationFile, authorizationFile} {
+		info, err := os.Stat(filepath.Join(a.policyDir, name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("policy %s not found in %s", name, a.policyDir)
+			}
+			return "", fmt.Errorf("stat %s: %w", name, err)
+		}
+
+		version += fmt.Sprintf("%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return version, nil
+}
+
+// prepareQuery compiles the query for the rule of the policy.
+func prepareQuery(ctx context.Context, module string, rule string) (rego.PreparedEvalQuery, error) {
+	query := fmt.Sprintf("x = data.%s.%s", opaPackage, rule)
+
+	q, err := rego.New(
+		rego.Query(query),
+		rego.Module("policy.rego", module),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, err
+	}
+
+	return q, nil
+}
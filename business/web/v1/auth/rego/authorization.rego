@@ -4,15 +4,18 @@ default ruleAny = false
 default ruleAdminOnly = false
 default ruleUserOnly = false
 default ruleAdminOrSubject = false
+default ruleAdminMFA = false
+default ruleEmailVerified = false
+default rulePermission = false
+default ruleAdminOrOwner = false
 
 roleUser := "USER"
 roleAdmin := "ADMIN"
-roleAll := {roleAdmin, roleUser}
+amrMFA := "mfa"
+resourcePlatform := "platform"
 
 ruleAny {
-	claim_roles := {role | role := input.Roles[_]}
-	input_roles := roleAll & claim_roles
-	count(input_roles) > 0
+	count(input.Roles) > 0
 }
 
 ruleAdminOnly {
@@ -37,3 +40,42 @@ ruleAdminOrSubject {
 	count(input_user) > 0
 	input.UserID == input.Subject
 }
+
+ruleAdminMFA {
+	claim_roles := {role | role := input.Roles[_]}
+	input_admin := {roleAdmin} & claim_roles
+	count(input_admin) > 0
+	claim_amr := {method | method := input.AMR[_]}
+	claim_amr[amrMFA]
+}
+
+ruleEmailVerified {
+	count(input.Roles) > 0
+	input.EmailVerified == true
+}
+
+rulePermission {
+	claim_permissions := {permission | permission := input.Permissions[_]}
+	claim_permissions[input.Permission]
+}
+
+rulePermission {
+	split(input.Permission, ":")[0] != resourcePlatform
+	claim_permissions := {permission | permission := input.Permissions[_]}
+	claim_permissions["*"]
+}
+
+rulePermission {
+	claim_permissions := {permission | permission := input.Permissions[_]}
+	resource := split(input.Permission, ":")[0]
+	claim_permissions[concat(":", [resource, "*"])]
+}
+
+ruleAdminOrOwner {
+	claim_roles := {role | role := input.Roles[_]}
+	input_admin := {roleAdmin} & claim_roles
+	count(input_admin) > 0
+} else {
+	count(input.Roles) > 0
+	input.OwnerID == input.Subject
+}
@@ -11,6 +11,43 @@ const (
 	RuleAdminOnly      = "ruleAdminOnly"
 	RuleUserOnly       = "ruleUserOnly"
 	RuleAdminOrSubject = "ruleAdminOrSubject"
+	RuleAdminMFA       = "ruleAdminMFA"
+	RuleEmailVerified  = "ruleEmailVerified"
+	RulePermission     = "rulePermission"
+	RuleAdminOrOwner   = "ruleAdminOrOwner"
+)
+
+// Set of permissions checked by the handlers. The platform permissions manage
+// the system as a whole, they aren't granted by * and only the roles of the
+// default tenant may grant them.
+const (
+	PermProductRead  = "product:read"
+	PermProductWrite = "product:write"
+	PermOrderRead    = "order:read"
+	PermOrderWrite   = "order:write"
+	PermUserRead     = "user:read"
+	PermUserWrite    = "user:write"
+	PermRoleRead     = "role:read"
+	PermRoleWrite    = "role:write"
+	PermTenantRead   = "platform:tenant_read"
+	PermTenantWrite  = "platform:tenant_write"
+	PermAuditRead    = "audit:read"
+)
+
+// Authentication methods recorded in the amr claim of a token.
+const (
+	AMRPassword  = "pwd"
+	AMROTP       = "otp"
+	AMRMFA       = "mfa"
+	AMRFederated = "fed"
+	AMRAPIKey    = "key"
+)
+
+// Rules defined by each policy. A query is prepared for each rule when the
+// policies are loaded.
+var (
+	authenticationRules = []string{RuleAuthenticate}
+	authorizationRules  = []string{RuleAny, RuleAdminOnly, RuleUserOnly, RuleAdminOrSubject, RuleAdminMFA, RuleEmailVerified, RulePermission, RuleAdminOrOwner}
 )
 
 // Package name of our rego code.
@@ -18,6 +55,12 @@ const (
 	opaPackage string = "diegom7s.rego"
 )
 
+// File names of the policies when they are loaded from a directory.
+const (
+	authenticationFile = "authentication.rego"
+	authorizationFile  = "authorization.rego"
+)
+
 // Core OPA policies.
 var (
 	//go:embed rego/authentication.rego
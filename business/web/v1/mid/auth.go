@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/response"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
@@ -16,16 +17,33 @@ var (
 	ErrInvalidID = errors.New("ID is not in its proper form")
 )
 
-// Authenticate validates a JWT from the `Authorization` header.
+// Authenticate validates a JWT from the `Authorization` header or, for
+// machine clients, an api key from the `X-API-Key` header. The calls made
+// by the handler are scoped to the tenant in the claims.
 func Authenticate(a *auth.Auth) web.Middleware {
 	m := func(handler web.Handler) web.Handler {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-			claims, err := a.Authenticate(ctx, r.Header.Get("authorization"))
+			var claims auth.Claims
+			var err error
+
+			switch key := r.Header.Get("X-API-Key"); {
+			case key != "":
+				claims, err = a.AuthenticateAPIKey(ctx, key)
+			default:
+				claims, err = a.Authenticate(ctx, r.Header.Get("authorization"))
+			}
+
 			if err != nil {
 				return auth.NewAuthError("authenticate: failed: %s", err)
 			}
 
+			tenantID, err := uuid.Parse(claims.TenantID)
+			if err != nil {
+				return auth.NewAuthError("authenticate: invalid tenant in claims")
+			}
+
 			ctx = auth.SetClaims(ctx, claims)
+			ctx = tenant.Set(ctx, tenantID)
 
 			return handler(ctx, w, r)
 		}
@@ -70,3 +88,26 @@ func Authorize(a *auth.Auth, rule string) web.Middleware {
 
 	return m
 }
+
+// AuthorizePermission validates that an authenticated user has been granted
+// the specified permission through the roles they hold.
+func AuthorizePermission(a *auth.Auth, permission string) web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			claims := auth.GetClaims(ctx)
+			if claims.Subject == "" {
+				return auth.NewAuthError("authorize: you are not authorized for that action, no claims")
+			}
+
+			if err := a.AuthorizePermission(ctx, claims, permission); err != nil {
+				return auth.NewAuthError("authorize: you are not authorized for that action, claims[%v] permission[%v]: %s", claims.Permissions, permission, err)
+			}
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -13,7 +13,8 @@ func Cors(origin string) web.Middleware {
 		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, If-Match, If-None-Match, If-Modified-Since")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified")
 			w.Header().Set("Access-Control-Max-Age", "86400")
 
 			return handler(ctx, w, r)
@@ -24,6 +24,14 @@ func Errors(log *logger.Logger) web.Middleware {
 				span.RecordError(err)
 				span.End()
 
+				// The response of a stream is already on its way to the
+				// client, there is nothing left to respond with. The
+				// connection is aborted so the client can tell the
+				// response is truncated instead of taking it as complete.
+				if web.IsStreamError(err) {
+					panic(http.ErrAbortHandler)
+				}
+
 				var er response.ErrorDocument
 				var status int
 
@@ -46,6 +54,14 @@ func Errors(log *logger.Logger) web.Middleware {
 					}
 					status = reqErr.Status
 
+				case validate.IsFieldErrors(err):
+					fieldErrors := validate.GetFieldErrors(err)
+					er = response.ErrorDocument{
+						Error:  "data validation error",
+						Fields: fieldErrors.Fields(),
+					}
+					status = http.StatusBadRequest
+
 				case auth.IsAuthError(err):
 					er = response.ErrorDocument{
 						Error: http.StatusText(http.StatusUnauthorized),
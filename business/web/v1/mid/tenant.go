@@ -0,0 +1,26 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/diegomagalhaes-dev/go-service/business/core/tenant"
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+// Unscoped marks the calls made by the handler as not scoped to a tenant. It
+// is meant for the routes that run before the tenant of the caller is known,
+// such as logins, which find the user across every tenant.
+func Unscoped() web.Middleware {
+	m := func(handler web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			ctx = tenant.Unscoped(ctx)
+
+			return handler(ctx, w, r)
+		}
+
+		return h
+	}
+
+	return m
+}
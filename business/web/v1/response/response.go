@@ -3,6 +3,9 @@ package response
 
 import (
 	"errors"
+	"strconv"
+
+	"github.com/diegomagalhaes-dev/go-service/business/data/page"
 )
 
 // PageDocument is the form used for API responses from query API calls.
@@ -23,6 +26,38 @@ func NewPageDocument[T any](items []T, total int, page int, rowsPrePage int) Pag
 	}
 }
 
+// CursorDocument is the form used for API responses from query API calls
+// paging with cursors. A cursor is left out when there is no page in its
+// direction.
+type CursorDocument[T any] struct {
+	Items       []T    `json:"items"`
+	Next        string `json:"next,omitempty"`
+	Prev        string `json:"prev,omitempty"`
+	RowsPerPage int    `json:"rowsPerPage"`
+}
+
+// NewCursorDocument constructs a response value for a web cursor paging
+// response.
+func NewCursorDocument[T any](items []T, cursors page.Cursors, rowsPerPage int) CursorDocument[T] {
+	return CursorDocument[T]{
+		Items:       items,
+		Next:        cursors.Next,
+		Prev:        cursors.Prev,
+		RowsPerPage: rowsPerPage,
+	}
+}
+
+// =============================================================================
+
+// CacheRevalidate is the Cache-Control of the responses a client can keep as
+// long as it revalidates them with the API before using them.
+const CacheRevalidate = "private, no-cache"
+
+// ETag returns the strong entity tag of a resource at the specified version.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
 // =============================================================================
 
 // ErrorDocument is the form used for API responses from failures in the API.
@@ -5,9 +5,12 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/diegomagalhaes-dev/go-service/business/core/account"
+	"github.com/diegomagalhaes-dev/go-service/business/core/user"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/auth"
 	"github.com/diegomagalhaes-dev/go-service/business/web/v1/mid"
 	"github.com/diegomagalhaes-dev/go-service/foundation/logger"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
 	"github.com/diegomagalhaes-dev/go-service/foundation/web"
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/trace"
@@ -34,6 +37,13 @@ type APIMuxConfig struct {
 	Auth        *auth.Auth
 	DB          *sqlx.DB
 	Tracer      trace.Tracer
+	PublicURL   string
+	Lockout     user.LockoutConfig
+	Mailer      account.Mailer
+	ResetURL    string
+	VerifyURL   string
+	OIDC        *oidc.Provider
+	OIDCRoles   []user.Role
 }
 
 // RouteAdder defines behavior that sets the routes to bind for an instance
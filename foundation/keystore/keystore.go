@@ -4,7 +4,7 @@ package keystore
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
@@ -12,35 +12,50 @@ import (
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// PrivateKey represents key information.
+// PrivateKey represents key information. The key can be a RSA, ECDSA or
+// Ed25519 private key.
 type PrivateKey struct {
-	PK  *rsa.PrivateKey
+	PK  crypto.Signer
 	PEM []byte
 }
 
 // KeyStore represents an in memory store implementation of the
-// KeyLookup interface for use with the auth package.
+// KeyLookup interface for use with the auth package. The store acts as a
+// key ring with one active kid used for signing and a set of retired kids
+// that stop verifying once their retirement date passes.
 type KeyStore struct {
-	store map[string]PrivateKey
+	mu      sync.RWMutex
+	store   map[string]PrivateKey
+	active  string
+	retired map[string]time.Time
 }
 
 // New constructs an empty KeyStore ready for use.
 func New() *KeyStore {
 	return &KeyStore{
-		store: make(map[string]PrivateKey),
+		store:   make(map[string]PrivateKey),
+		retired: make(map[string]time.Time),
 	}
 }
 
-// NewMap constructs a KeyStore with an initial set of keys.
+// NewMap constructs a KeyStore with an initial set of keys. When the map
+// holds a single key, that key is the active one.
 func NewMap(store map[string]PrivateKey) *KeyStore {
-	return &KeyStore{
-		store: store,
+	ks := KeyStore{
+		store:   store,
+		retired: make(map[string]time.Time),
 	}
+	ks.active = ks.singleKID()
+
+	return &ks
 }
 
 // NewFS constructs a KeyStore based on a set of PEM files rooted inside
@@ -77,7 +92,7 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 			return fmt.Errorf("reading auth private key: %w", err)
 		}
 
-		pk, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		pk, err := parsePrivateKey(pem)
 		if err != nil {
 			return fmt.Errorf("parsing auth private key: %w", err)
 		}
@@ -96,11 +111,16 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 		return nil, fmt.Errorf("walking directory: %w", err)
 	}
 
+	ks.active = ks.singleKID()
+
 	return ks, nil
 }
 
 // PrivateKey searches the key store for a given kid and returns the private key.
 func (ks *KeyStore) PrivateKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
 	privateKey, found := ks.store[kid]
 	if !found {
 		return "", errors.New("kid lookup failed")
@@ -110,13 +130,21 @@ func (ks *KeyStore) PrivateKey(kid string) (string, error) {
 }
 
 // PublicKey searches the key store for a given kid and returns the public key.
+// Retired kids are not returned once their retirement date has passed.
 func (ks *KeyStore) PublicKey(kid string) (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
 	privateKey, found := ks.store[kid]
 	if !found {
 		return "", errors.New("kid lookup failed")
 	}
 
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PK.PublicKey)
+	if ks.isRetired(kid, time.Now()) {
+		return "", fmt.Errorf("kid %q is retired", kid)
+	}
+
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.PK.Public())
 	if err != nil {
 		return "", fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -133,3 +161,111 @@ func (ks *KeyStore) PublicKey(kid string) (string, error) {
 
 	return b.String(), nil
 }
+
+// KIDs returns the sorted list of key ids held by the key store that can
+// still verify tokens.
+func (ks *KeyStore) KIDs() ([]string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	now := time.Now()
+
+	kids := make([]string, 0, len(ks.store))
+	for kid := range ks.store {
+		if !ks.isRetired(kid, now) {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+
+	return kids, nil
+}
+
+// ActiveKID returns the kid used to sign new tokens.
+func (ks *KeyStore) ActiveKID() (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.active == "" {
+		return "", errors.New("no active kid")
+	}
+
+	return ks.active, nil
+}
+
+// Add adds a private key to the key store. The key can verify tokens but is
+// not used for signing until it is promoted, unless it's the first key.
+func (ks *KeyStore) Add(kid string, pk PrivateKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.store[kid] = pk
+	if ks.active == "" {
+		ks.active = ks.singleKID()
+	}
+}
+
+// Promote makes the specified kid the active signing kid. The kid that was
+// active before remains available for verification until retireAt.
+func (ks *KeyStore) Promote(kid string, retireAt time.Time) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, found := ks.store[kid]; !found {
+		return errors.New("kid lookup failed")
+	}
+
+	if ks.active != "" && ks.active != kid {
+		ks.retired[ks.active] = retireAt
+	}
+
+	ks.active = kid
+	delete(ks.retired, kid)
+
+	return nil
+}
+
+// =============================================================================
+
+// isRetired reports whether the kid's retirement date has passed. The caller
+// must hold the lock.
+func (ks *KeyStore) isRetired(kid string, now time.Time) bool {
+	retireAt, exists := ks.retired[kid]
+	return exists && !now.Before(retireAt)
+}
+
+// singleKID returns the kid when the store holds a single key.
+func (ks *KeyStore) singleKID() string {
+	if len(ks.store) != 1 {
+		return ""
+	}
+
+	for kid := range ks.store {
+		return kid
+	}
+
+	return ""
+}
+
+// parsePrivateKey parses a PEM encoded RSA, ECDSA or Ed25519 private key.
+func parsePrivateKey(pem []byte) (crypto.Signer, error) {
+	if pk, err := jwt.ParseRSAPrivateKeyFromPEM(pem); err == nil {
+		return pk, nil
+	}
+
+	if pk, err := jwt.ParseECPrivateKeyFromPEM(pem); err == nil {
+		return pk, nil
+	}
+
+	pk, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, errors.New("key must be a PEM encoded RSA, ECDSA or Ed25519 private key")
+	}
+
+	signer, ok := pk.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("key is not a signing key")
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,136 @@
+// Package mailer provides support for sending email over SMTP, along with an
+// in-memory mailer for tests.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message represents an email that was sent.
+type Message struct {
+	To      mail.Address
+	Subject string
+	Body    string
+}
+
+// =============================================================================
+
+// SMTPConfig represents the settings of the SMTP server to send email with.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     mail.Address
+}
+
+// SMTP sends email through an SMTP server.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from mail.Address
+}
+
+// NewSMTP constructs a mailer for the SMTP server. Authentication is only
+// used when a username is provided.
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTP{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		auth: auth,
+		from: cfg.From,
+	}
+}
+
+// Send sends a plain text email to the address.
+func (s *SMTP) Send(ctx context.Context, to mail.Address, subject string, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg, err := buildMessage(s.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from.Address, []string{to.Address}, msg); err != nil {
+		return fmt.Errorf("sendmail: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage formats the email with the headers an SMTP server expects.
+func buildMessage(from mail.Address, to mail.Address, subject string, body string) ([]byte, error) {
+	if strings.ContainsAny(subject, "\r\n") {
+		return nil, errors.New("subject can't contain line breaks")
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return b.Bytes(), nil
+}
+
+// =============================================================================
+
+// Memory keeps the email it's asked to send in memory. It's meant for tests.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemory constructs an in-memory mailer.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Send records the email.
+func (m *Memory) Send(ctx context.Context, to mail.Address, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+
+	return nil
+}
+
+// Messages returns the email sent to the address, oldest first.
+func (m *Memory) Messages(to string) []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var msgs []Message
+	for _, msg := range m.messages {
+		if msg.To.Address == to {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs
+}
@@ -0,0 +1,360 @@
+// Package oidc provides support for logging users in through an OpenID Connect
+// provider using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrInvalidToken is returned when the id token of the provider can't be
+// trusted.
+var ErrInvalidToken = errors.New("id token not valid")
+
+// Config represents the information required to talk to the provider.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Client       *http.Client
+}
+
+// Claims represents the claims of an id token the login flow depends on.
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name"`
+	AMR           []string `json:"amr"`
+}
+
+// Provider is the client for an OpenID Connect provider. The discovery
+// document and the keys of the provider are fetched on first use, so the
+// provider doesn't have to be reachable when the service starts.
+type Provider struct {
+	cfg Config
+
+	mu        sync.Mutex
+	doc       *discovery
+	keys      map[string]any
+	keysFetch time.Time
+}
+
+// New constructs a provider for the specified configuration.
+func New(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Provider{
+		cfg: cfg,
+	}
+}
+
+// AuthCodeURL returns the url of the provider the user is sent to for
+// logging in.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, challenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing authorization endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange trades the authorization code for the tokens of the provider and
+// returns the claims of the verified id token.
+func (p *Provider) Exchange(ctx context.Context, code string, verifier string, nonce string) (Claims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tkn struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.do(req, &tkn); err != nil {
+		return Claims{}, fmt.Errorf("token request: %w", err)
+	}
+
+	if tkn.IDToken == "" {
+		return Claims{}, fmt.Errorf("token response: %w: missing id token", ErrInvalidToken)
+	}
+
+	return p.verify(ctx, doc, tkn.IDToken, nonce)
+}
+
+// =============================================================================
+
+// RandomString returns a random url safe value used for the state, nonce and
+// PKCE verifier of a login.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating random value: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 PKCE challenge for the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// =============================================================================
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (p *Provider) discover(ctx context.Context) (discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.doc != nil {
+		return *p.doc, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return discovery{}, fmt.Errorf("creating discovery request: %w", err)
+	}
+
+	var doc discovery
+	if err := p.do(req, &doc); err != nil {
+		return discovery{}, fmt.Errorf("discovery request: %w", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.cfg.IssuerURL, "/") {
+		return discovery{}, fmt.Errorf("discovery issuer[%s] doesn't match configured issuer[%s]", doc.Issuer, p.cfg.IssuerURL)
+	}
+
+	p.doc = &doc
+
+	return doc, nil
+}
+
+// key returns the public key for the kid. The keys are fetched again when the
+// kid is unknown so rotated keys are picked up, but not more than once a
+// minute.
+func (p *Provider) key(ctx context.Context, doc discovery, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, exists := p.keys[kid]; exists {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetch) < time.Minute {
+		return nil, fmt.Errorf("kid[%s] not found", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating jwks request: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.do(req, &set); err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := toPublicKey(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	p.keys = keys
+	p.keysFetch = time.Now()
+
+	key, exists := p.keys[kid]
+	if !exists {
+		return nil, fmt.Errorf("kid[%s] not found", kid)
+	}
+
+	return key, nil
+}
+
+func (p *Provider) verify(ctx context.Context, doc discovery, idToken string, nonce string) (Claims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "ES256"}))
+
+	var claims Claims
+	keyFunc := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, doc, kid)
+	}
+
+	if _, err := parser.ParseWithClaims(idToken, &claims, keyFunc); err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	if !claims.VerifyIssuer(doc.Issuer, true) {
+		return Claims{}, fmt.Errorf("%w: issuer[%s] not expected", ErrInvalidToken, claims.Issuer)
+	}
+
+	if !claims.VerifyAudience(p.cfg.ClientID, true) {
+		return Claims{}, fmt.Errorf("%w: audience%v not expected", ErrInvalidToken, claims.Audience)
+	}
+
+	if !claims.VerifyExpiresAt(time.Now(), true) {
+		return Claims{}, fmt.Errorf("%w: missing expiration", ErrInvalidToken)
+	}
+
+	if claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%w: nonce doesn't match", ErrInvalidToken)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) do(req *http.Request, dest any) error {
+	resp, err := p.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status[%d]: %s", resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, dest); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+func toPublicKey(k jwk) (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding n: %w", err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding e: %w", err)
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("curve[%s] not supported", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding x: %w", err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decoding y: %w", err)
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("key type[%s] not supported", k.Kty)
+}
@@ -0,0 +1,204 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const redirectURL = "http://localhost:3000/v1/oidc/callback"
+
+func Test_Exchange(t *testing.T) {
+	idp, err := oidctest.NewServer("sales")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider : %s", err)
+	}
+	defer idp.Close()
+
+	idp.SetUser(oidctest.User{
+		Subject:       "subject-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	})
+
+	prv := oidc.New(oidc.Config{
+		IssuerURL:   idp.URL,
+		ClientID:    "sales",
+		RedirectURL: redirectURL,
+	})
+
+	ctx := context.Background()
+
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		t.Fatalf("Should be able to generate a verifier : %s", err)
+	}
+
+	code := authorize(t, prv, "state", "nonce", oidc.Challenge(verifier))
+
+	claims, err := prv.Exchange(ctx, code, verifier, "nonce")
+	if err != nil {
+		t.Fatalf("Should be able to exchange the code : %s", err)
+	}
+
+	if claims.Subject != "subject-1" || claims.Email != "jane@example.com" || !claims.EmailVerified || claims.Name != "Jane Doe" {
+		t.Fatalf("Should get back the claims of the user : %+v", claims)
+	}
+
+	// -------------------------------------------------------------------------
+
+	code = authorize(t, prv, "state", "nonce", oidc.Challenge(verifier))
+
+	if _, err := prv.Exchange(ctx, code, "wrong-verifier", "nonce"); err == nil {
+		t.Fatalf("Should NOT be able to exchange the code with the wrong verifier")
+	}
+
+	// -------------------------------------------------------------------------
+
+	code = authorize(t, prv, "state", "nonce", oidc.Challenge(verifier))
+
+	if _, err := prv.Exchange(ctx, code, verifier, "other"); !errors.Is(err, oidc.ErrInvalidToken) {
+		t.Fatalf("Should NOT accept an id token for another nonce : %v", err)
+	}
+}
+
+func Test_Verify(t *testing.T) {
+	idp, err := oidctest.NewServer("sales")
+	if err != nil {
+		t.Fatalf("Should be able to start the provider : %s", err)
+	}
+	defer idp.Close()
+
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims oidc.Claims
+	}{
+		{
+			name: "audience",
+			claims: oidc.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    idp.URL,
+					Subject:   "subject-1",
+					Audience:  jwt.ClaimStrings{"other"},
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+				},
+			},
+		},
+		{
+			name: "issuer",
+			claims: oidc.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    "http://other.example.com",
+					Subject:   "subject-1",
+					Audience:  jwt.ClaimStrings{"sales"},
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+				},
+			},
+		},
+		{
+			name: "expired",
+			claims: oidc.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    idp.URL,
+					Subject:   "subject-1",
+					Audience:  jwt.ClaimStrings{"sales"},
+					ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idToken, err := idp.Sign(tt.claims)
+			if err != nil {
+				t.Fatalf("Should be able to sign the token : %s", err)
+			}
+
+			tokenEndpoint := http.NewServeMux()
+			tokenEndpoint.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id_token":"` + idToken + `"}`))
+			})
+
+			prv := oidc.New(oidc.Config{
+				IssuerURL:   idp.URL,
+				ClientID:    "sales",
+				RedirectURL: redirectURL,
+				Client: &http.Client{
+					Transport: tokenTransport{token: tokenEndpoint},
+				},
+			})
+
+			if _, err := prv.Exchange(context.Background(), "code", "verifier", ""); !errors.Is(err, oidc.ErrInvalidToken) {
+				t.Fatalf("Should NOT accept the id token : %v", err)
+			}
+		})
+	}
+}
+
+// =============================================================================
+
+// authorize runs the authorization request against the provider and returns
+// the code it redirects back with.
+func authorize(t *testing.T, prv *oidc.Provider, state string, nonce string, challenge string) string {
+	authURL, err := prv.AuthCodeURL(context.Background(), state, nonce, challenge)
+	if err != nil {
+		t.Fatalf("Should be able to build the authorization url : %s", err)
+	}
+
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("Should be able to call the authorization endpoint : %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Should be redirected back : got %d", resp.StatusCode)
+	}
+
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Should be able to parse the redirect : %s", err)
+	}
+
+	if loc.Query().Get("state") != state {
+		t.Fatalf("Should get back the state : got %q", loc.Query().Get("state"))
+	}
+
+	return loc.Query().Get("code")
+}
+
+// tokenTransport answers the token request with a fixed handler and sends
+// every other request to the provider.
+type tokenTransport struct {
+	token http.Handler
+}
+
+func (tt tokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Path != "/token" {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+
+	rec := httptest.NewRecorder()
+	tt.token.ServeHTTP(rec, r)
+
+	return rec.Result(), nil
+}
@@ -0,0 +1,235 @@
+// Package oidctest provides a stub OpenID Connect provider for tests. The
+// provider logs in the configured user without asking for credentials.
+package oidctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/oidc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const kid = "oidctest"
+
+// User represents the user the provider logs in.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AMR           []string
+}
+
+type grant struct {
+	clientID    string
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        User
+}
+
+// Server is a stub provider running on a local listener.
+type Server struct {
+	*httptest.Server
+	ClientID string
+
+	key    *ecdsa.PrivateKey
+	mu     sync.Mutex
+	user   User
+	grants map[string]grant
+}
+
+// NewServer starts a provider that accepts the client id. The caller must
+// call Close when done.
+func NewServer(clientID string) (*Server, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	s := Server{
+		ClientID: clientID,
+		key:      key,
+		grants:   make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+
+	s.Server = httptest.NewServer(mux)
+
+	return &s, nil
+}
+
+// SetUser sets the user logged in by the following authorization requests.
+func (s *Server) SetUser(usr User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.user = usr
+}
+
+// =============================================================================
+
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	doc := map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	}
+
+	respond(w, doc, http.StatusOK)
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	switch {
+	case q.Get("response_type") != "code":
+		http.Error(w, "unsupported response type", http.StatusBadRequest)
+		return
+	case q.Get("client_id") != s.ClientID:
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		http.Error(w, "missing pkce challenge", http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	code, err := oidc.RandomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	s.grants[code] = grant{
+		clientID:    q.Get("client_id"),
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		user:        s.user,
+	}
+	s.mu.Unlock()
+
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := r.PostForm.Get("code")
+
+	// Codes can only be used once.
+	s.mu.Lock()
+	g, exists := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	switch {
+	case !exists:
+		respond(w, map[string]string{"error": "invalid_grant"}, http.StatusBadRequest)
+		return
+	case r.PostForm.Get("client_id") != g.clientID || r.PostForm.Get("redirect_uri") != g.redirectURI:
+		respond(w, map[string]string{"error": "invalid_grant"}, http.StatusBadRequest)
+		return
+	case oidc.Challenge(r.PostForm.Get("code_verifier")) != g.challenge:
+		respond(w, map[string]string{"error": "invalid_grant"}, http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+
+	claims := oidc.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.URL,
+			Subject:   g.user.Subject,
+			Audience:  jwt.ClaimStrings{g.clientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce:         g.nonce,
+		Email:         g.user.Email,
+		EmailVerified: g.user.EmailVerified,
+		Name:          g.user.Name,
+		AMR:           g.user.AMR,
+	}
+
+	idToken, err := s.Sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := map[string]any{
+		"access_token": code,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	}
+
+	respond(w, resp, http.StatusOK)
+}
+
+func (s *Server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+
+	set := map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "EC",
+				"use": "sig",
+				"alg": "ES256",
+				"kid": kid,
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	}
+
+	respond(w, set, http.StatusOK)
+}
+
+// Sign returns an id token for the claims signed with the key of the
+// provider. It allows tests to present tokens the provider would never issue.
+func (s *Server) Sign(claims oidc.Claims) (string, error) {
+	tkn := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tkn.Header["kid"] = kid
+
+	return tkn.SignedString(s.key)
+}
+
+func respond(w http.ResponseWriter, data any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,109 @@
+// Package totp implements time-based one-time passwords as described by
+// RFC 6238, using the defaults authenticator apps expect: HMAC-SHA1, six
+// digits and a 30 second period.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Settings of the generated codes.
+const (
+	Digits = 6
+	Period = 30 * time.Second
+)
+
+// secretSize is the number of random bytes in a secret, as recommended by
+// RFC 4226 for HMAC-SHA1.
+const secretSize = 20
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating secret: %w", err)
+	}
+
+	return encoding.EncodeToString(b), nil
+}
+
+// URI returns the otpauth provisioning URI for the secret. The URI is what
+// authenticator apps read from a QR code.
+func URI(issuer string, account string, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// Step returns the time step the specified time falls in.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code of the secret for the specified time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("decoding secret: %w", err)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod), nil
+}
+
+// Validate checks the code against the secret at the specified time. One
+// step of clock drift is accepted in either direction. On success the step
+// the code belongs to is returned so callers can reject a code that was
+// already used.
+func Validate(secret string, code string, t time.Time) (int64, error) {
+	if len(code) != Digits {
+		return 0, errors.New("invalid code")
+	}
+
+	now := Step(t)
+	for step := now - 1; step <= now+1; step++ {
+		exp, err := Code(secret, step)
+		if err != nil {
+			return 0, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(exp), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+
+	return 0, errors.New("invalid code")
+}
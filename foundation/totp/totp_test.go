@@ -0,0 +1,69 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/totp"
+)
+
+func Test_Code(t *testing.T) {
+	// Test vectors from RFC 6238 appendix B for SHA1, truncated to six digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := totp.Code(secret, totp.Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Should be able to generate a code : %s", err)
+		}
+
+		if code != tt.code {
+			t.Fatalf("Should get the expected code for %d : got %s, exp %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("Should be able to generate a secret : %s", err)
+	}
+
+	now := time.Now()
+
+	code, err := totp.Code(secret, totp.Step(now.Add(-totp.Period)))
+	if err != nil {
+		t.Fatalf("Should be able to generate a code : %s", err)
+	}
+
+	step, err := totp.Validate(secret, code, now)
+	if err != nil {
+		t.Fatalf("Should accept a code from the previous step : %s", err)
+	}
+
+	if step != totp.Step(now)-1 {
+		t.Fatalf("Should get back the step of the code : got %d, exp %d", step, totp.Step(now)-1)
+	}
+
+	if _, err := totp.Validate(secret, code, now.Add(2*totp.Period)); err == nil {
+		t.Fatalf("Should NOT accept a code outside of the drift window")
+	}
+
+	uri := totp.URI("sales", "user@example.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/sales:user@example.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("Should get a provisioning uri : %s", uri)
+	}
+}
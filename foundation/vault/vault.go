@@ -5,7 +5,7 @@ package vault
 import (
 	"bytes"
 	"context"
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
@@ -130,8 +130,25 @@ func (v *Vault) PrivateKey(kid string) (string, error) {
 }
 
 // PublicKey searches the key store for a given kid and returns
-// the public key in pem format.
+// the public key in pem format. Retired kids are not returned once
+// their retirement date has passed.
 func (v *Vault) PublicKey(kid string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ring, err := v.retrieveKeyRing(ctx)
+	if err != nil {
+		return "", fmt.Errorf("key ring lookup failed: %w", err)
+	}
+
+	if ring.isRetired(kid, time.Now()) {
+		v.mu.Lock()
+		delete(v.store, kid)
+		v.mu.Unlock()
+
+		return "", fmt.Errorf("kid %q is retired", kid)
+	}
+
 	if pem, err := v.keyLookup(kid); err == nil {
 		return pem, nil
 	}
@@ -155,6 +172,97 @@ func (v *Vault) PublicKey(kid string) (string, error) {
 	return publicPEM, nil
 }
 
+// KIDs returns the list of key ids stored under the mount path that can
+// still verify tokens.
+func (v *Vault) KIDs() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	kids, err := v.listKIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kid list failed: %w", err)
+	}
+
+	ring, err := v.retrieveKeyRing(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("key ring lookup failed: %w", err)
+	}
+
+	now := time.Now()
+
+	active := make([]string, 0, len(kids))
+	for _, kid := range kids {
+		if !ring.isRetired(kid, now) {
+			active = append(active, kid)
+		}
+	}
+
+	return active, nil
+}
+
+// ActiveKID returns the kid used to sign new tokens. When no kid has been
+// promoted and vault holds a single key, that key is the active one.
+func (v *Vault) ActiveKID() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ring, err := v.retrieveKeyRing(ctx)
+	if err != nil {
+		return "", fmt.Errorf("key ring lookup failed: %w", err)
+	}
+
+	if ring.ActiveKID != "" {
+		return ring.ActiveKID, nil
+	}
+
+	kids, err := v.listKIDs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("kid list failed: %w", err)
+	}
+
+	if len(kids) != 1 {
+		return "", errors.New("no active kid")
+	}
+
+	return kids[0], nil
+}
+
+// Promote makes the specified kid the active signing kid. The kid that was
+// active before remains available for verification until retireAt. When no
+// kid was promoted before, every other kid is retired at retireAt.
+func (v *Vault) Promote(ctx context.Context, kid string, retireAt time.Time) error {
+	if _, err := v.retrieveKID(ctx, kid); err != nil {
+		return fmt.Errorf("kid lookup failed: %w", err)
+	}
+
+	ring, err := v.retrieveKeyRing(ctx)
+	if err != nil {
+		return fmt.Errorf("key ring lookup failed: %w", err)
+	}
+
+	previous := []string{ring.ActiveKID}
+	if ring.ActiveKID == "" {
+		if previous, err = v.listKIDs(ctx); err != nil {
+			return fmt.Errorf("kid list failed: %w", err)
+		}
+	}
+
+	for _, prev := range previous {
+		if prev != kid {
+			ring.Retired[prev] = retireAt.UTC()
+		}
+	}
+
+	ring.ActiveKID = kid
+	delete(ring.Retired, kid)
+
+	if err := v.storeKeyRing(ctx, ring); err != nil {
+		return fmt.Errorf("key ring store failed: %w", err)
+	}
+
+	return nil
+}
+
 // =============================================================================
 
 // Error variables for this set of API calls.
@@ -435,6 +543,121 @@ func (v *Vault) CreateToken(ctx context.Context, id string, policies []string, d
 
 // =============================================================================
 
+// keyRingPath is where the key ring is stored under the mount path. Since it
+// lives in a folder it is not listed as a kid.
+const keyRingPath = "keyring/state"
+
+// keyRing represents the lifecycle of the keys stored in vault.
+type keyRing struct {
+	ActiveKID string               `json:"active_kid"`
+	Retired   map[string]time.Time `json:"retired"`
+}
+
+// isRetired reports whether the kid's retirement date has passed.
+func (kr keyRing) isRetired(kid string, now time.Time) bool {
+	retireAt, exists := kr.Retired[kid]
+	return exists && !now.Before(retireAt)
+}
+
+// retrieveKeyRing performs the HTTP call against the Vault service to read
+// the key ring. An empty key ring is returned when none was stored yet.
+func (v *Vault) retrieveKeyRing(ctx context.Context) (keyRing, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.mountPath, keyRingPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return keyRing{}, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return keyRing{}, fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	ring := keyRing{
+		Retired: make(map[string]time.Time),
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ring, nil
+	default:
+		return keyRing{}, fmt.Errorf("status code: %s", resp.Status)
+	}
+
+	var data struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return keyRing{}, fmt.Errorf("decoding: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(data.Data.Data["ring"]), &ring); err != nil {
+		return keyRing{}, fmt.Errorf("decoding ring: %w", err)
+	}
+
+	if ring.Retired == nil {
+		ring.Retired = make(map[string]time.Time)
+	}
+
+	return ring, nil
+}
+
+// storeKeyRing performs the HTTP call against the Vault service to write
+// the key ring.
+func (v *Vault) storeKeyRing(ctx context.Context, ring keyRing) error {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", v.address, v.mountPath, keyRingPath)
+
+	r, err := json.Marshal(ring)
+	if err != nil {
+		return fmt.Errorf("encode ring: %w", err)
+	}
+
+	data := struct {
+		M map[string]string `json:"data"`
+	}{
+		M: map[string]string{
+			"ring": string(r),
+		},
+	}
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(data); err != nil {
+		return fmt.Errorf("encode data: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, &b)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // keyLookup performs a safe lookup in the store map.
 func (v *Vault) keyLookup(kid string) (string, error) {
 	v.mu.RLock()
@@ -494,6 +717,57 @@ func (v *Vault) retrieveKID(ctx context.Context, kid string) (string, error) {
 	return pem, nil
 }
 
+// listKIDs performs the HTTP call against the Vault service to list the keys
+// stored under the mount path.
+func (v *Vault) listKIDs(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("%s/v1/%s/metadata?list=true", v.address, v.mountPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// Vault responds with a 404 when nothing is stored under the path.
+		return []string{}, nil
+	default:
+		return nil, fmt.Errorf("status code: %s", resp.Status)
+	}
+
+	var data struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decoding: %w", err)
+	}
+
+	// Entries ending with a slash are folders and not keys.
+	kids := make([]string, 0, len(data.Data.Keys))
+	for _, key := range data.Data.Keys {
+		if !strings.HasSuffix(key, "/") {
+			kids = append(kids, key)
+		}
+	}
+
+	return kids, nil
+}
+
 // listMounts returns the set of mount points that exist.
 func (v *Vault) listMounts(ctx context.Context) (map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/v1/sys/mounts", v.address)
@@ -529,12 +803,12 @@ func (v *Vault) listMounts(ctx context.Context) (map[string]interface{}, error)
 // =============================================================================
 
 // toPublicPEM was taken from the JWT package to reduce the dependency. It
-// accepts a PEM encoding of a RSA private key and converts to a PEM encoded
-// public key.
+// accepts a PEM encoding of a RSA, ECDSA or Ed25519 private key and converts
+// to a PEM encoded public key.
 func toPublicPEM(privateKeyPEM string) (string, error) {
 	var block *pem.Block
 	if block, _ = pem.Decode([]byte(privateKeyPEM)); block == nil {
-		return "", errors.New("invalid key: Key must be a PEM encoded PKCS1 or PKCS8 key")
+		return "", errors.New("invalid key: Key must be a PEM encoded PKCS1, PKCS8 or EC key")
 	}
 
 	var parsedKey interface{}
@@ -542,16 +816,19 @@ func toPublicPEM(privateKeyPEM string) (string, error) {
 	if err != nil {
 		parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
 		if err != nil {
-			return "", err
+			parsedKey, err = x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return "", err
+			}
 		}
 	}
 
-	privateKey, ok := parsedKey.(*rsa.PrivateKey)
+	privateKey, ok := parsedKey.(crypto.Signer)
 	if !ok {
-		return "", errors.New("key is not a valid RSA private key")
+		return "", errors.New("key is not a valid RSA, ECDSA or Ed25519 private key")
 	}
 
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
 	if err != nil {
 		return "", fmt.Errorf("marshaling public key: %w", err)
 	}
@@ -79,4 +79,48 @@ func Test_Vault(t *testing.T) {
 		t.Logf("exp: %s", expPEM.String())
 		t.Error("Should be able to see the keys match")
 	}
+
+	kids, err := vault.KIDs()
+	if err != nil {
+		t.Fatalf("Should be able to list the kids from Vault : %s", err)
+	}
+
+	if len(kids) != 1 || kids[0] != key {
+		t.Errorf("Should be able to see the stored kid : got %v exp %v", kids, []string{key})
+	}
+
+	// -------------------------------------------------------------------------
+
+	activeKID, err := vault.ActiveKID()
+	if err != nil || activeKID != key {
+		t.Fatalf("Should see the only kid as the active kid : %q %v", activeKID, err)
+	}
+
+	const newKey = "9a0b4b45-ddc3-45e9-9f5b-3f1b1e0d8e2a"
+
+	if err := vault.AddPrivateKey(context.Background(), newKey, expPEM.Bytes()); err != nil {
+		t.Fatalf("Should be able to put the new PEM into Vault : %s", err)
+	}
+
+	if err := vault.Promote(context.Background(), newKey, time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("Should be able to promote the new kid : %s", err)
+	}
+
+	activeKID, err = vault.ActiveKID()
+	if err != nil || activeKID != newKey {
+		t.Fatalf("Should see the promoted kid as the active kid : %q %v", activeKID, err)
+	}
+
+	kids, err = vault.KIDs()
+	if err != nil {
+		t.Fatalf("Should be able to list the kids from Vault : %s", err)
+	}
+
+	if len(kids) != 1 || kids[0] != newKey {
+		t.Errorf("Should only see the promoted kid : got %v exp %v", kids, []string{newKey})
+	}
+
+	if _, err := vault.PublicKey(key); err == nil {
+		t.Error("Should NOT be able to get the public key of a retired kid")
+	}
 }
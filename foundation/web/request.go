@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/dimfeld/httptreemux/v5"
 )
@@ -37,3 +38,22 @@ func Decode(r *http.Request, val any) error {
 
 	return nil
 }
+
+// IfMatch reports whether the If-Match header of the request matches the
+// specified entity tag. A request without the header always matches so the
+// precondition is only enforced for clients that send it.
+func IfMatch(r *http.Request, etag string) bool {
+	v := r.Header.Get("If-Match")
+	if v == "" {
+		return true
+	}
+
+	for _, tag := range strings.Split(v, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
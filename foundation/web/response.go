@@ -2,8 +2,12 @@ package web
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -34,3 +38,97 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 
 	return nil
 }
+
+// CacheConfig defines the caching headers of a conditional response.
+type CacheConfig struct {
+	CacheControl string
+	LastModified time.Time
+}
+
+// RespondConditional converts a Go value to JSON and sends it to the client
+// with a status code of 200 unless the client already has it. A strong ETag
+// is computed over the body unless the handler already set one, and a client
+// sending a matching If-None-Match, or an If-Modified-Since that is not
+// older than the last modification when it sends no If-None-Match, receives
+// a 304 without a body.
+func RespondConditional(ctx context.Context, w http.ResponseWriter, r *http.Request, data any, cfg CacheConfig) error {
+	ctx, span := AddSpan(ctx, "foundation.web.respondconditional")
+	defer span.End()
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(jsonData)
+		etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+	}
+
+	if cfg.CacheControl != "" {
+		w.Header().Set("Cache-Control", cfg.CacheControl)
+	}
+
+	if !cfg.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", cfg.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, cfg.LastModified) {
+		span.SetAttributes(attribute.Int("status", http.StatusNotModified))
+		SetStatusCode(ctx, http.StatusNotModified)
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	span.SetAttributes(attribute.Int("status", http.StatusOK))
+	SetStatusCode(ctx, http.StatusOK)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(jsonData); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notModified reports whether the client already has the current
+// representation of the resource. If-None-Match takes precedence over
+// If-Modified-Since as required by RFC 9110.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if v := r.Header.Get("If-None-Match"); v != "" {
+		for _, tag := range strings.Split(v, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// Redirect sends the client to the url with the redirect status code.
+func Redirect(ctx context.Context, w http.ResponseWriter, r *http.Request, url string, statusCode int) error {
+	ctx, span := AddSpan(ctx, "foundation.web.redirect", attribute.Int("status", statusCode))
+	defer span.End()
+
+	SetStatusCode(ctx, statusCode)
+
+	http.Redirect(w, r, url, statusCode)
+
+	return nil
+}
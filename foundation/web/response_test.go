@@ -0,0 +1,94 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+func Test_RespondConditional(t *testing.T) {
+	lastModified := time.Date(2024, time.May, 1, 10, 30, 0, 0, time.UTC)
+
+	cfg := web.CacheConfig{
+		CacheControl: "private, no-cache",
+		LastModified: lastModified,
+	}
+
+	data := struct {
+		Name string `json:"name"`
+	}{
+		Name: "Comic Books",
+	}
+
+	respond := func(header http.Header, etag string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/v1/products", nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+
+		if err := web.RespondConditional(context.Background(), w, r, data, cfg); err != nil {
+			t.Fatalf("Should be able to respond : %s", err)
+		}
+
+		return w
+	}
+
+	w := respond(nil, "")
+	if w.Code != http.StatusOK || w.Body.String() != `{"name":"Comic Books"}` {
+		t.Fatalf("Should receive the body : %d : %s", w.Code, w.Body)
+	}
+
+	etag := w.Header().Get("ETag")
+	if len(etag) < 2 || etag[0] != '"' {
+		t.Fatalf("Should receive a strong ETag : %q", etag)
+	}
+
+	if got := w.Header().Get("Cache-Control"); got != cfg.CacheControl {
+		t.Fatalf("Should receive the Cache-Control : %q", got)
+	}
+
+	if got := w.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 10:30:00 GMT" {
+		t.Fatalf("Should receive the Last-Modified : %q", got)
+	}
+
+	if again := respond(nil, ""); again.Header().Get("ETag") != etag {
+		t.Fatalf("Should compute the same ETag for the same body : %q", again.Header().Get("ETag"))
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		etag   string
+		status int
+	}{
+		{"ifNoneMatch", http.Header{"If-None-Match": {etag}}, "", http.StatusNotModified},
+		{"ifNoneMatchList", http.Header{"If-None-Match": {`"other", ` + etag}}, "", http.StatusNotModified},
+		{"ifNoneMatchOther", http.Header{"If-None-Match": {`"other"`}}, "", http.StatusOK},
+		{"ifModifiedSince", http.Header{"If-Modified-Since": {"Wed, 01 May 2024 10:30:00 GMT"}}, "", http.StatusNotModified},
+		{"ifModifiedSinceOlder", http.Header{"If-Modified-Since": {"Wed, 01 May 2024 10:29:59 GMT"}}, "", http.StatusOK},
+		{"precedence", http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {"Wed, 01 May 2024 10:30:00 GMT"}}, "", http.StatusOK},
+		{"handlerETag", http.Header{"If-None-Match": {`"7"`}}, `"7"`, http.StatusNotModified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := respond(tt.header, tt.etag)
+			if w.Code != tt.status {
+				t.Fatalf("Should receive a status code of %d : %d", tt.status, w.Code)
+			}
+
+			if tt.status == http.StatusNotModified && w.Body.Len() != 0 {
+				t.Fatalf("Should NOT receive a body with a 304 : %s", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Set of media types a stream can be written in.
+const (
+	MediaJSON   = "application/json"
+	MediaNDJSON = "application/x-ndjson"
+	MediaCSV    = "text/csv"
+)
+
+// defaultFlushEvery is the number of elements written between flushes when
+// the config of a stream does not specify it.
+const defaultFlushEvery = 100
+
+// ErrNotAcceptable is returned when a stream can't be written in any of the
+// media types the client accepts.
+var ErrNotAcceptable = errors.New("none of the accepted media types can be produced")
+
+// CSVRecorder is implemented by the elements of a stream that can be written
+// as CSV records.
+type CSVRecorder interface {
+	CSVRecord() []string
+}
+
+// StreamConfig defines how the elements of a stream are written.
+type StreamConfig struct {
+	// CSVHeader is the first record of a CSV stream. A stream is only offered
+	// as CSV when it has a header.
+	CSVHeader []string
+
+	// FlushEvery is the number of elements written between flushes.
+	FlushEvery int
+
+	// WriteTimeout, when set, pushes the write deadline of the response that
+	// far past every flush, so a stream outlasting the write timeout of the
+	// server is not cut off while it makes progress.
+	WriteTimeout time.Duration
+}
+
+// RespondStream sends the elements handed to send by produce as they come,
+// without holding them in memory. The media type is chosen from the Accept
+// header of the request: a JSON array, NDJSON or CSV. The response is flushed
+// every few elements and send fails once the client goes away.
+//
+// Once the first byte is written the status code can't change anymore, an
+// error of produce then aborts the response and is returned as a stream
+// error.
+func RespondStream(ctx context.Context, w http.ResponseWriter, r *http.Request, cfg StreamConfig, produce func(send func(v any) error) error) error {
+	ctx, span := AddSpan(ctx, "foundation.web.respondstream")
+	defer span.End()
+
+	media, err := negotiateStream(r.Header.Get("Accept"), cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.FlushEvery <= 0 {
+		cfg.FlushEvery = defaultFlushEvery
+	}
+
+	span.SetAttributes(attribute.String("media", media), attribute.Int("status", http.StatusOK))
+	SetStatusCode(ctx, http.StatusOK)
+
+	w.Header().Set("Content-Type", media)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	s := stream{
+		ctx:   ctx,
+		media: media,
+		bw:    bufio.NewWriter(w),
+		rc:    http.NewResponseController(w),
+		cfg:   cfg,
+	}
+
+	if err := s.open(); err != nil {
+		return &streamError{err}
+	}
+
+	if err := produce(s.send); err != nil {
+		return &streamError{err}
+	}
+
+	if err := s.close(); err != nil {
+		return &streamError{err}
+	}
+
+	return nil
+}
+
+// stream writes the elements of a stream in its media type.
+type stream struct {
+	ctx   context.Context
+	media string
+	bw    *bufio.Writer
+	rc    *http.ResponseController
+	cfg   StreamConfig
+	cw    *csv.Writer
+	count int
+}
+
+func (s *stream) open() error {
+	if err := s.extend(); err != nil {
+		return err
+	}
+
+	switch s.media {
+	case MediaJSON:
+		return s.bw.WriteByte('[')
+
+	case MediaCSV:
+		s.cw = csv.NewWriter(s.bw)
+		return s.cw.Write(s.cfg.CSVHeader)
+	}
+
+	return nil
+}
+
+func (s *stream) send(v any) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	switch s.media {
+	case MediaCSV:
+		rec, ok := v.(CSVRecorder)
+		if !ok {
+			return fmt.Errorf("csv: %T does not implement CSVRecorder", v)
+		}
+
+		if err := s.cw.Write(rec.CSVRecord()); err != nil {
+			return err
+		}
+
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if s.media == MediaJSON && s.count > 0 {
+			data = append([]byte{','}, data...)
+		}
+
+		if s.media == MediaNDJSON {
+			data = append(data, '\n')
+		}
+
+		if _, err := s.bw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	s.count++
+	if s.count%s.cfg.FlushEvery == 0 {
+		return s.flush()
+	}
+
+	return nil
+}
+
+func (s *stream) close() error {
+	if s.media == MediaJSON {
+		if err := s.bw.WriteByte(']'); err != nil {
+			return err
+		}
+	}
+
+	return s.flush()
+}
+
+// flush sends what's buffered so far on to the client.
+func (s *stream) flush() error {
+	if s.cw != nil {
+		s.cw.Flush()
+		if err := s.cw.Error(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.bw.Flush(); err != nil {
+		return err
+	}
+
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return s.extend()
+}
+
+// extend pushes the write deadline of the response past the write timeout.
+func (s *stream) extend() error {
+	if s.cfg.WriteTimeout <= 0 {
+		return nil
+	}
+
+	if err := s.rc.SetWriteDeadline(time.Now().Add(s.cfg.WriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
+
+// negotiateStream picks the media type of a stream from the Accept header,
+// the one with the highest quality wins and JSON is the default.
+func negotiateStream(accept string, cfg StreamConfig) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaJSON, nil
+	}
+
+	offers := []string{MediaJSON, MediaNDJSON}
+	if len(cfg.CSVHeader) > 0 {
+		offers = append(offers, MediaCSV)
+	}
+
+	var best string
+	var bestQ float64
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, exists := params["q"]; exists {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q <= bestQ {
+			continue
+		}
+
+		for _, offer := range offers {
+			if matchMedia(mediaType, offer) {
+				best, bestQ = offer, q
+				break
+			}
+		}
+	}
+
+	if best == "" {
+		return "", ErrNotAcceptable
+	}
+
+	return best, nil
+}
+
+// matchMedia reports whether the media range accepts the media type.
+func matchMedia(mediaRange string, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+
+	prefix, found := strings.CutSuffix(mediaRange, "/*")
+	return found && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+// =============================================================================
+
+// streamError is a type used to report an error happening after the response
+// of a stream started.
+type streamError struct {
+	err error
+}
+
+// Error is the implementation of the error interface.
+func (se *streamError) Error() string {
+	return "stream aborted: " + se.err.Error()
+}
+
+// Unwrap returns the error that aborted the stream.
+func (se *streamError) Unwrap() error {
+	return se.err
+}
+
+// IsStreamError checks to see if the error aborted a stream whose response
+// already started, in which case nothing else can be sent to the client.
+func IsStreamError(err error) bool {
+	var se *streamError
+	return errors.As(err, &se)
+}
@@ -0,0 +1,112 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/diegomagalhaes-dev/go-service/foundation/web"
+)
+
+type streamItem struct {
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+}
+
+func (si streamItem) CSVRecord() []string {
+	return []string{si.Name, strconv.Itoa(si.Cost)}
+}
+
+func Test_RespondStream(t *testing.T) {
+	items := []streamItem{{Name: "Comics", Cost: 10}, {Name: "Novels", Cost: 20}}
+
+	cfg := web.StreamConfig{
+		CSVHeader:  []string{"name", "cost"},
+		FlushEvery: 1,
+	}
+
+	produce := func(send func(v any) error) error {
+		for _, item := range items {
+			if err := send(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		accept string
+		media  string
+		body   string
+	}{
+		{
+			name:  "default",
+			media: web.MediaJSON,
+			body:  `[{"name":"Comics","cost":10},{"name":"Novels","cost":20}]`,
+		},
+		{
+			name:   "ndjson",
+			accept: "application/json;q=0.5, application/x-ndjson",
+			media:  web.MediaNDJSON,
+			body:   "{\"name\":\"Comics\",\"cost\":10}\n{\"name\":\"Novels\",\"cost\":20}\n",
+		},
+		{
+			name:   "csv",
+			accept: "text/*",
+			media:  web.MediaCSV,
+			body:   "name,cost\nComics,10\nNovels,20\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/products/export", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			if err := web.RespondStream(context.Background(), w, r, cfg, produce); err != nil {
+				t.Fatalf("Should be able to stream the items : %s", err)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tt.media {
+				t.Fatalf("Should receive the negotiated media type : got %q, want %q", got, tt.media)
+			}
+
+			if w.Body.String() != tt.body {
+				t.Fatalf("Should receive the items : got %q, want %q", w.Body, tt.body)
+			}
+
+			if !w.Flushed {
+				t.Fatalf("Should flush the items as they are written")
+			}
+		})
+	}
+
+	t.Run("notAcceptable", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/products/export", nil)
+		r.Header.Set("Accept", "application/xml")
+
+		err := web.RespondStream(context.Background(), httptest.NewRecorder(), r, cfg, produce)
+		if !errors.Is(err, web.ErrNotAcceptable) {
+			t.Fatalf("Should NOT be able to stream in an unsupported media type : %v", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r := httptest.NewRequest(http.MethodGet, "/v1/products/export", nil)
+
+		err := web.RespondStream(ctx, httptest.NewRecorder(), r, cfg, produce)
+		if !web.IsStreamError(err) || !errors.Is(err, context.Canceled) {
+			t.Fatalf("Should stop streaming once the client goes away : %v", err)
+		}
+	})
+}